@@ -7,10 +7,18 @@ import (
 	"os"
 
 	"github.com/delqhi/mikasmissions/platform/apps/admin-studio-service/internal"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/tracing"
 )
 
 func main() {
+	tracer, err := tracing.InitFromEnv("admin-studio-service", os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer tracer.Close()
+
 	repo, err := internal.OpenRepositoryFromEnv()
 	if err != nil {
 		log.Fatal(err)
@@ -22,7 +30,7 @@ func main() {
 			}
 		}()
 	}
-	bus, err := queue.NewBusFromEnv()
+	bus, err := queue.NewBusFromEnv(queue.WithProducer("admin-studio-service"))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -34,6 +42,7 @@ func main() {
 		addr = ":" + fromEnv
 	}
 	log.Printf("admin-studio-service listening on %s", addr)
+	observability.ServeMetrics("admin-studio-service", mux, os.Getenv)
 	if err := http.ListenAndServe(addr, mux); err != nil {
 		log.Fatal(err)
 	}
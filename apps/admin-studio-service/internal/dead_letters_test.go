@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
+)
+
+func TestDeadLetterRedriveRepublishesOriginalPayload(t *testing.T) {
+	store := NewStore()
+	letter := store.RecordDeadLetter(DeadLetter{
+		EventID:       "evt-1",
+		OriginalTopic: "episode.published.v1",
+		Consumer:      "worker-publish",
+		Error:         "boom",
+		Attempts:      3,
+		Payload:       json.RawMessage(`{"episode_id":"ep-1"}`),
+		Headers:       queue.Headers{queue.HeaderTraceID: "trace-1"},
+	})
+	bus := queue.NewInMemoryBus()
+	defer bus.Close()
+	var republished queue.Event
+	_ = bus.Subscribe(context.Background(), "episode.published.v1", "test", func(_ context.Context, event queue.Event) error {
+		republished = event
+		return nil
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/admin/dead-letters/{dead_letter_id}/redrive", PostAdminDeadLetterRedrive(store, bus))
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/v1/admin/dead-letters/"+letter.ID+"/redrive", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var response contractsapi.AdminDeadLetterRedriveResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if republished.ID != response.RedriveEventID || republished.ID == "evt-1" {
+		t.Fatalf("expected new event id %q, got %q", response.RedriveEventID, republished.ID)
+	}
+	if string(republished.Payload) != `{"episode_id":"ep-1"}` || republished.Headers.TraceID() != "trace-1" {
+		t.Fatalf("unexpected republished event: %+v", republished)
+	}
+	if republished.Headers.CausationID() != "evt-1" {
+		t.Fatalf("expected causation evt-1, got %q", republished.Headers.CausationID())
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/v1/admin/dead-letters/"+letter.ID+"/redrive", nil))
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 on second redrive, got %d", rr.Code)
+	}
+}
+
+func TestGetDeadLettersFiltersByStatus(t *testing.T) {
+	store := NewStore()
+	store.RecordDeadLetter(DeadLetter{EventID: "evt-1", OriginalTopic: "video.run.requested.v1"})
+	store.RecordDeadLetter(DeadLetter{EventID: "evt-2", OriginalTopic: "video.run.requested.v1", Status: deadLetterRedriven})
+
+	rr := httptest.NewRecorder()
+	GetAdminDeadLetters(store).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/admin/dead-letters?status=open", nil))
+	var response contractsapi.AdminDeadLetterListResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(response.DeadLetters) != 1 || response.DeadLetters[0].EventID != "evt-1" {
+		t.Fatalf("unexpected dead letters: %+v", response.DeadLetters)
+	}
+
+	rr = httptest.NewRecorder()
+	GetAdminDeadLetters(store).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/admin/dead-letters?limit=0", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid limit, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"net/http"
+	"strconv"
+
+	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+	"github.com/delqhi/mikasmissions/platform/libs/httpx"
+)
+
+func GetAdminDeadLetters(repo Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter := DeadLetterFilter{
+			Status: r.URL.Query().Get("status"),
+			Topic:  r.URL.Query().Get("topic"),
+			Limit:  20,
+		}
+		if filter.Status != "" && filter.Status != deadLetterOpen && filter.Status != deadLetterRedriven {
+			httpx.WriteAPIError(w, http.StatusBadRequest, "workflow_invalid", "status must be one of: open, redriven")
+			return
+		}
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed <= 0 {
+				httpx.WriteAPIError(w, http.StatusBadRequest, "invalid_limit", "limit must be a positive integer")
+				return
+			}
+			filter.Limit = parsed
+		}
+		letters, err := repo.ListDeadLetters(filter)
+		if err != nil {
+			httpx.WriteAPIError(w, http.StatusInternalServerError, "workflow_error", err.Error())
+			return
+		}
+		mapped := make([]contractsapi.AdminDeadLetter, 0, len(letters))
+		for _, letter := range letters {
+			mapped = append(mapped, adminDeadLetter(letter, false))
+		}
+		httpx.WriteJSON(w, http.StatusOK, contractsapi.AdminDeadLetterListResponse{DeadLetters: mapped})
+	}
+}
+
+func GetAdminDeadLetter(repo Repository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		letter, found, err := repo.FindDeadLetter(r.PathValue("dead_letter_id"))
+		if err != nil {
+			httpx.WriteAPIError(w, http.StatusInternalServerError, "workflow_error", err.Error())
+			return
+		}
+		if !found {
+			httpx.WriteAPIError(w, http.StatusNotFound, "workflow_missing", "dead letter not found")
+			return
+		}
+		httpx.WriteJSON(w, http.StatusOK, adminDeadLetter(letter, true))
+	}
+}
+
+func adminDeadLetter(letter DeadLetter, detail bool) contractsapi.AdminDeadLetter {
+	mapped := contractsapi.AdminDeadLetter{
+		DeadLetterID:   letter.ID,
+		EventID:        letter.EventID,
+		OriginalTopic:  letter.OriginalTopic,
+		Consumer:       letter.Consumer,
+		Error:          letter.Error,
+		Attempts:       letter.Attempts,
+		FailedAt:       letter.FailedAt,
+		Status:         letter.Status,
+		RedriveEventID: letter.RedriveEventID,
+		RedrivenBy:     letter.RedrivenBy,
+		RedrivenAt:     letter.RedrivenAt,
+	}
+	if detail {
+		mapped.Payload = letter.Payload
+		mapped.Headers = letter.Headers
+	}
+	return mapped
+}
@@ -3,7 +3,6 @@ package internal
 import (
 	"net/http"
 
-	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
 	"github.com/delqhi/mikasmissions/platform/libs/httpx"
 )
 
@@ -23,14 +22,6 @@ func GetAdminModelProfile(repo Repository) http.HandlerFunc {
 			httpx.WriteAPIError(w, http.StatusNotFound, "workflow_missing", "model profile not found")
 			return
 		}
-		httpx.WriteJSON(w, http.StatusOK, contractsapi.AdminModelProfile{
-			ModelProfileID: profile.ID,
-			Provider:       profile.Provider,
-			BaseURL:        profile.BaseURL,
-			ModelID:        profile.ModelID,
-			TimeoutMS:      profile.TimeoutMS,
-			MaxRetries:     profile.MaxRetries,
-			SafetyPreset:   profile.SafetyPreset,
-		})
+		httpx.WriteJSON(w, http.StatusOK, modelProfileResponse(profile))
 	}
 }
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"encoding/json"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+)
+
+const (
+	deadLetterOpen     = "open"
+	deadLetterRedriven = "redriven"
+)
+
+type DeadLetter struct {
+	ID             string
+	EventID        string
+	OriginalTopic  string
+	Consumer       string
+	Error          string
+	Attempts       int
+	FailedAt       string
+	Payload        json.RawMessage
+	Headers        queue.Headers
+	Status         string
+	RedriveEventID string
+	RedrivenBy     string
+	RedrivenAt     string
+}
+
+type DeadLetterFilter struct {
+	Status string
+	Topic  string
+	Limit  int
+}
+
+func (d DeadLetter) dlqEvent() queue.DLQEvent {
+	return queue.DLQEvent{
+		EventID:       d.EventID,
+		OriginalTopic: d.OriginalTopic,
+		Consumer:      d.Consumer,
+		Error:         d.Error,
+		Attempts:      d.Attempts,
+		FailedAt:      d.FailedAt,
+		Payload:       d.Payload,
+		TraceID:       d.Headers.TraceID(),
+	}
+}
@@ -1,5 +1,12 @@
 package internal
 
+import (
+	"strings"
+
+	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+	"github.com/delqhi/mikasmissions/platform/libs/generatorprovider"
+)
+
 type ModelProfile struct {
 	ID           string
 	Provider     string
@@ -9,3 +16,32 @@ type ModelProfile struct {
 	MaxRetries   int
 	SafetyPreset string
 }
+
+func modelProfileResponse(profile ModelProfile) contractsapi.AdminModelProfile {
+	response := contractsapi.AdminModelProfile{
+		ModelProfileID: profile.ID,
+		Provider:       profile.Provider,
+		BaseURL:        profile.BaseURL,
+		ModelID:        profile.ModelID,
+		TimeoutMS:      profile.TimeoutMS,
+		MaxRetries:     profile.MaxRetries,
+		SafetyPreset:   profile.SafetyPreset,
+	}
+	if backend, ok := generatorprovider.Lookup(profile.Provider); ok {
+		response.Capabilities = &contractsapi.AdminProviderCapabilities{
+			MaxDurationMS: backend.Capabilities.MaxDurationMS,
+			Resolutions:   backend.Capabilities.Resolutions,
+			InputFields:   backend.Capabilities.InputFields,
+			Steps:         backend.Capabilities.Steps,
+			Callbacks:     backend.Capabilities.Callbacks,
+		}
+	}
+	return response
+}
+
+func validateProvider(provider string) *contractsapi.APIError {
+	if _, ok := generatorprovider.Lookup(provider); ok {
+		return nil
+	}
+	return &contractsapi.APIError{Code: "workflow_invalid", Message: "provider must be one of: " + strings.Join(generatorprovider.Providers(), ", ")}
+}
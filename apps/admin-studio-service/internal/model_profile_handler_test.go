@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+)
+
+func TestModelProfileExposesProviderCapabilities(t *testing.T) {
+	store := NewStore()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/admin/model-profiles/{id}", GetAdminModelProfile(store))
+	mux.HandleFunc("PUT /v1/admin/model-profiles/{id}", PutAdminModelProfile(store))
+	put := func(provider string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		body := `{"provider":"` + provider + `","base_url":"http://localhost:9100","model_id":"fake-v1","timeout_ms":15000,"max_retries":1,"safety_preset":"kids_strict"}`
+		mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/v1/admin/model-profiles/offline", strings.NewReader(body)))
+		return rr
+	}
+
+	if rr := put("sora_unknown"); rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "local_fake") {
+		t.Fatalf("expected unknown provider rejection listing providers, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := put("local_fake"); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/admin/model-profiles/offline", nil))
+	var profile contractsapi.AdminModelProfile
+	if err := json.Unmarshal(rr.Body.Bytes(), &profile); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if profile.Provider != "local_fake" || profile.Capabilities == nil || profile.Capabilities.MaxDurationMS != 60000 || len(profile.Capabilities.Steps) == 0 {
+		t.Fatalf("unexpected model profile: %+v", profile)
+	}
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+)
+
+type eventFlusher interface {
+	FlushEvents(ctx context.Context, bus queue.Bus) error
+}
+
+func flushEvents(ctx context.Context, repo Repository, bus queue.Bus) error {
+	flusher, ok := repo.(eventFlusher)
+	if !ok || bus == nil {
+		return nil
+	}
+	return flusher.FlushEvents(ctx, bus)
+}
+
+func (s *Store) FlushEvents(ctx context.Context, bus queue.Bus) error {
+	return s.events.Flush(ctx, bus)
+}
+
+func buildWorkflowEvents(workflow WorkflowTemplate, builders []workflowEvent) ([]queue.Event, error) {
+	events := make([]queue.Event, 0, len(builders))
+	for _, build := range builders {
+		event, err := build(workflow)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func buildRunEvents(run WorkflowRun, builders []runEvent) ([]queue.Event, error) {
+	events := make([]queue.Event, 0, len(builders))
+	for _, build := range builders {
+		event, err := build(run)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
+)
+
+func TestStoreCreateRunQueuesRequestedEventUntilFlushed(t *testing.T) {
+	store := NewStore()
+	workflow, _ := store.CreateWorkflow(WorkflowTemplate{Name: "Space Adventure", ModelProfileID: "nim-default", Steps: []string{"script", "nim", "qc"}}, "admin-1")
+	bus := queue.NewInMemoryBus()
+	defer bus.Close()
+	var requested contractsevents.VideoRunRequestedV1
+	var traceID string
+	_ = bus.Subscribe(context.Background(), "video.run.requested.v1", "test", func(_ context.Context, event queue.Event) error {
+		traceID = event.Headers.TraceID()
+		return json.Unmarshal(event.Payload, &requested)
+	})
+	run, err := store.CreateRun(WorkflowRun{WorkflowID: workflow.ID, Priority: "normal"}, "admin-1", func(run WorkflowRun) (queue.Event, error) {
+		return newRunRequestedEvent(context.Background(), run, workflow, "admin-1")
+	})
+	if err != nil {
+		t.Fatalf("create run: %v", err)
+	}
+	if err := flushEvents(context.Background(), store, bus); err != nil {
+		t.Fatalf("flush events: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if requested.RunID != run.ID || requested.ModelProfileID != "nim-default" || len(requested.Steps) != 3 {
+		t.Fatalf("unexpected run requested event: %+v", requested)
+	}
+	if traceID != run.ID {
+		t.Fatalf("expected trace id %q, got %q", run.ID, traceID)
+	}
+}
+
+func TestRunCancelPublishesCancelledEvent(t *testing.T) {
+	store := NewStore()
+	workflow, _ := store.CreateWorkflow(WorkflowTemplate{Name: "Space Adventure", ModelProfileID: "nim-default"}, "admin-1")
+	run, _ := store.CreateRun(WorkflowRun{WorkflowID: workflow.ID, Priority: "normal"}, "admin-1")
+	bus := queue.NewInMemoryBus()
+	defer bus.Close()
+	var cancelled contractsevents.VideoRunCancelledV1
+	_ = bus.Subscribe(context.Background(), "video.run.cancelled.v1", "test", func(_ context.Context, event queue.Event) error {
+		return json.Unmarshal(event.Payload, &cancelled)
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/admin/runs/{run_id}/cancel", PostAdminRunCancel(store, bus))
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/v1/admin/runs/"+run.ID+"/cancel", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	testkit.WaitBusIdle(t, bus)
+	if cancelled.RunID != run.ID || cancelled.CancelledBy == "" {
+		t.Fatalf("unexpected cancelled event: %+v", cancelled)
+	}
+	if stored, _, _ := store.FindRun(run.ID); stored.Status != "cancelled" {
+		t.Fatalf("expected run cancelled, got %q", stored.Status)
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/v1/admin/runs/missing/cancel", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown run, got %d", rr.Code)
+	}
+}
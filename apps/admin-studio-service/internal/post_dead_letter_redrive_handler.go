@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"net/http"
+
+	"github.com/delqhi/mikasmissions/platform/libs/authz"
+	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+	"github.com/delqhi/mikasmissions/platform/libs/httpx"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/google/uuid"
+)
+
+func PostAdminDeadLetterRedrive(repo Repository, bus queue.Bus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		deadLetterID := r.PathValue("dead_letter_id")
+		letter, found, err := repo.FindDeadLetter(deadLetterID)
+		if err != nil {
+			httpx.WriteAPIError(w, http.StatusInternalServerError, "workflow_error", err.Error())
+			return
+		}
+		if !found {
+			httpx.WriteAPIError(w, http.StatusNotFound, "workflow_missing", "dead letter not found")
+			return
+		}
+		if letter.Status != deadLetterOpen {
+			httpx.WriteAPIError(w, http.StatusConflict, "workflow_conflict", "dead letter was already redriven as "+letter.RedriveEventID)
+			return
+		}
+		actor := "admin-system"
+		if principal, ok := authz.PrincipalFrom(r.Context()); ok {
+			actor = actorIDFromPrincipal(principal)
+		}
+		event := queue.NewRedriveEvent(r.Context(), letter.dlqEvent(), uuid.NewString())
+		updated, err := repo.MarkDeadLetterRedriven(deadLetterID, event.ID, actor, event)
+		if err != nil {
+			httpx.WriteAPIError(w, http.StatusInternalServerError, "workflow_error", err.Error())
+			return
+		}
+		if !updated {
+			httpx.WriteAPIError(w, http.StatusConflict, "workflow_conflict", "dead letter was already redriven")
+			return
+		}
+		if err := flushEvents(r.Context(), repo, bus); err != nil {
+			httpx.WriteAPIError(w, http.StatusBadGateway, "workflow_error", err.Error())
+			return
+		}
+		httpx.WriteJSON(w, http.StatusOK, contractsapi.AdminDeadLetterRedriveResponse{
+			DeadLetterID:   deadLetterID,
+			Status:         deadLetterRedriven,
+			RedriveEventID: event.ID,
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/httpx"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/google/uuid"
+)
+
+func PostGenerationCallback(repo Repository, bus queue.Bus, token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token == "" || bus == nil {
+			httpx.WriteAPIError(w, http.StatusNotFound, "callback_disabled", "generation callbacks are not enabled")
+			return
+		}
+		presented := r.URL.Query().Get("token")
+		if presented == "" {
+			presented = r.Header.Get("X-Callback-Token")
+		}
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			httpx.WriteAPIError(w, http.StatusUnauthorized, "callback_unauthorized", "callback token is invalid")
+			return
+		}
+		runID := r.PathValue("run_id")
+		var req contractsapi.AdminGenerationCallbackRequest
+		if err := httpx.DecodeJSON(r, &req); err != nil {
+			httpx.WriteAPIError(w, http.StatusBadRequest, "invalid_json", err.Error())
+			return
+		}
+		if apiErr := req.Validate(); apiErr != nil {
+			httpx.WriteJSON(w, http.StatusBadRequest, apiErr)
+			return
+		}
+		_, found, err := repo.FindRun(runID)
+		if err != nil {
+			httpx.WriteAPIError(w, http.StatusInternalServerError, "workflow_error", err.Error())
+			return
+		}
+		if !found {
+			httpx.WriteAPIError(w, http.StatusNotFound, "workflow_missing", "run not found")
+			return
+		}
+		event, err := queue.NewJSONEvent(queue.ContextWithTraceID(r.Context(), runID), "video.run.job.updated.v1", uuid.NewString(), contractsevents.VideoRunJobUpdatedV1{
+			RunID:        runID,
+			JobID:        req.JobID,
+			Status:       req.Status,
+			Progress:     req.Progress,
+			ErrorMessage: req.Error,
+			UpdatedAt:    time.Now().UTC().Format(time.RFC3339),
+		})
+		if err != nil {
+			httpx.WriteAPIError(w, http.StatusInternalServerError, "workflow_error", err.Error())
+			return
+		}
+		if err := bus.Publish(r.Context(), event.WithOrderingKey(runID)); err != nil {
+			httpx.WriteAPIError(w, http.StatusBadGateway, "workflow_error", err.Error())
+			return
+		}
+		httpx.WriteJSON(w, http.StatusAccepted, contractsapi.AdminGenerationCallbackResponse{RunID: runID, JobID: req.JobID, Status: req.Status})
+	}
+}
@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
+)
+
+func TestGenerationCallbackPublishesJobUpdate(t *testing.T) {
+	store := NewStore()
+	workflow, _ := store.CreateWorkflow(WorkflowTemplate{Name: "Space Adventure", ModelProfileID: "nim-default"}, "admin-1")
+	run, _ := store.CreateRun(WorkflowRun{WorkflowID: workflow.ID, Priority: "normal"}, "admin-1")
+	bus := queue.NewInMemoryBus()
+	defer bus.Close()
+	var updates []contractsevents.VideoRunJobUpdatedV1
+	_ = bus.Subscribe(context.Background(), "video.run.job.updated.v1", "test", func(_ context.Context, event queue.Event) error {
+		var update contractsevents.VideoRunJobUpdatedV1
+		if err := json.Unmarshal(event.Payload, &update); err != nil {
+			return err
+		}
+		updates = append(updates, update)
+		return nil
+	})
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/admin/runs/{run_id}/generation-callback", PostGenerationCallback(store, bus, "s3cret"))
+	call := func(path, body string) int {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return rr.Code
+	}
+	body := `{"job_id":"job-1","status":"running","progress":55}`
+
+	if code := call("/v1/admin/runs/"+run.ID+"/generation-callback?token=wrong", body); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for bad token, got %d", code)
+	}
+	if code := call("/v1/admin/runs/missing/generation-callback?token=s3cret", body); code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown run, got %d", code)
+	}
+	if code := call("/v1/admin/runs/"+run.ID+"/generation-callback?token=s3cret", `{"job_id":"job-1","status":"done"}`); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown status, got %d", code)
+	}
+	if code := call("/v1/admin/runs/"+run.ID+"/generation-callback?token=s3cret", body); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if len(updates) != 1 || updates[0].RunID != run.ID || updates[0].JobID != "job-1" || updates[0].Progress != 55 {
+		t.Fatalf("unexpected job updates: %+v", updates)
+	}
+}
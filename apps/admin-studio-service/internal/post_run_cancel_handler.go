@@ -4,18 +4,29 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/delqhi/mikasmissions/platform/libs/authz"
 	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
 	"github.com/delqhi/mikasmissions/platform/libs/httpx"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
 )
 
-func PostAdminRunCancel(repo Repository) http.HandlerFunc {
+func PostAdminRunCancel(repo Repository, bus queue.Bus) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		runID := r.PathValue("run_id")
 		if runID == "" {
 			httpx.WriteAPIError(w, http.StatusBadRequest, "workflow_invalid", "run_id is required")
 			return
 		}
-		updated, err := repo.SetRunStatus(runID, "cancelled", "")
+		actor := "admin-system"
+		if principal, ok := authz.PrincipalFrom(r.Context()); ok {
+			actor = actorIDFromPrincipal(principal)
+		}
+		event, err := newRunCancelledEvent(r.Context(), runID, actor, "workflow run cancelled by admin")
+		if err != nil {
+			httpx.WriteAPIError(w, http.StatusInternalServerError, "workflow_error", err.Error())
+			return
+		}
+		updated, err := repo.SetRunStatus(runID, "cancelled", "", event)
 		if err != nil {
 			httpx.WriteAPIError(w, http.StatusInternalServerError, "workflow_error", err.Error())
 			return
@@ -31,6 +42,10 @@ func PostAdminRunCancel(repo Repository) http.HandlerFunc {
 			Message:   "workflow run cancelled by admin",
 			EventTime: time.Now().UTC().Format(time.RFC3339),
 		})
+		if err := flushEvents(r.Context(), repo, bus); err != nil {
+			httpx.WriteAPIError(w, http.StatusBadGateway, "workflow_error", err.Error())
+			return
+		}
 		httpx.WriteJSON(w, http.StatusOK, contractsapi.AdminWorkflowRunResponse{RunID: runID, Status: "cancelled"})
 	}
 }
@@ -6,7 +6,6 @@ import (
 
 	"github.com/delqhi/mikasmissions/platform/libs/authz"
 	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
-	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
 	"github.com/delqhi/mikasmissions/platform/libs/httpx"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
 )
@@ -36,14 +35,19 @@ func PostAdminRunRetry(repo Repository, bus queue.Bus) http.HandlerFunc {
 			httpx.WriteAPIError(w, http.StatusNotFound, "workflow_missing", "workflow not found")
 			return
 		}
-		if _, err := repo.SetRunStatus(runID, "requested", ""); err != nil {
-			httpx.WriteAPIError(w, http.StatusInternalServerError, "workflow_error", err.Error())
-			return
-		}
 		actor := "admin-system"
 		if principal, ok := authz.PrincipalFrom(r.Context()); ok {
 			actor = actorIDFromPrincipal(principal)
 		}
+		event, err := newRunRequestedEvent(r.Context(), run, workflow, actor)
+		if err != nil {
+			httpx.WriteAPIError(w, http.StatusInternalServerError, "workflow_error", err.Error())
+			return
+		}
+		if _, err := repo.SetRunStatus(runID, "requested", "", event); err != nil {
+			httpx.WriteAPIError(w, http.StatusInternalServerError, "workflow_error", err.Error())
+			return
+		}
 		_ = repo.AppendRunLog(WorkflowRunLog{
 			RunID:     runID,
 			Step:      "run",
@@ -51,20 +55,7 @@ func PostAdminRunRetry(repo Repository, bus queue.Bus) http.HandlerFunc {
 			Message:   "workflow run retry requested",
 			EventTime: time.Now().UTC().Format(time.RFC3339),
 		})
-		err = publishJSONEvent(r.Context(), bus, "video.run.requested.v1", contractsevents.VideoRunRequestedV1{
-			RunID:              run.ID,
-			WorkflowID:         run.WorkflowID,
-			ModelProfileID:     workflow.ModelProfileID,
-			InputPayload:       run.InputPayload,
-			AutoPublish:        run.AutoPublish,
-			Priority:           run.Priority,
-			ContentSuitability: workflow.ContentSuitability,
-			AgeBand:            workflow.AgeBand,
-			RequestedBy:        actor,
-			RequestedAt:        time.Now().UTC().Format(time.RFC3339),
-			TraceID:            run.ID,
-		})
-		if err != nil {
+		if err := flushEvents(r.Context(), repo, bus); err != nil {
 			httpx.WriteAPIError(w, http.StatusBadGateway, "nim_provider_error", err.Error())
 			return
 		}
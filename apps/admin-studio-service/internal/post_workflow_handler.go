@@ -9,6 +9,7 @@ import (
 	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
 	"github.com/delqhi/mikasmissions/platform/libs/httpx"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/google/uuid"
 )
 
 func PostAdminWorkflow(repo Repository, bus queue.Bus) http.HandlerFunc {
@@ -34,17 +35,19 @@ func PostAdminWorkflow(repo Repository, bus queue.Bus) http.HandlerFunc {
 			Steps:              req.Steps,
 			ModelProfileID:     req.ModelProfileID,
 			SafetyProfile:      req.SafetyProfile,
-		}, actor)
+		}, actor, func(created WorkflowTemplate) (queue.Event, error) {
+			return queue.NewJSONEvent(r.Context(), "video.workflow.created.v1", uuid.NewString(), contractsevents.VideoWorkflowCreatedV1{
+				WorkflowID: created.ID,
+				Version:    created.Version,
+				CreatedBy:  actor,
+				CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+			})
+		})
 		if err != nil {
 			httpx.WriteAPIError(w, http.StatusInternalServerError, "workflow_error", err.Error())
 			return
 		}
-		_ = publishJSONEvent(r.Context(), bus, "video.workflow.created.v1", contractsevents.VideoWorkflowCreatedV1{
-			WorkflowID: created.ID,
-			Version:    created.Version,
-			CreatedBy:  actor,
-			CreatedAt:  time.Now().UTC().Format(time.RFC3339),
-		})
+		_ = flushEvents(r.Context(), repo, bus)
 		httpx.WriteJSON(w, http.StatusCreated, mapWorkflowToContract(created))
 	}
 }
@@ -6,7 +6,6 @@ import (
 
 	"github.com/delqhi/mikasmissions/platform/libs/authz"
 	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
-	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
 	"github.com/delqhi/mikasmissions/platform/libs/httpx"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
 )
@@ -42,7 +41,9 @@ func PostAdminWorkflowRun(repo Repository, bus queue.Bus) http.HandlerFunc {
 			Priority:     req.Priority,
 			AutoPublish:  req.AutoPublish,
 			InputPayload: req.InputPayload,
-		}, actor)
+		}, actor, func(run WorkflowRun) (queue.Event, error) {
+			return newRunRequestedEvent(r.Context(), run, workflow, actor)
+		})
 		if err != nil {
 			httpx.WriteAPIError(w, http.StatusInternalServerError, "workflow_error", err.Error())
 			return
@@ -54,20 +55,7 @@ func PostAdminWorkflowRun(repo Repository, bus queue.Bus) http.HandlerFunc {
 			Message:   "workflow run requested",
 			EventTime: time.Now().UTC().Format(time.RFC3339),
 		})
-		err = publishJSONEvent(r.Context(), bus, "video.run.requested.v1", contractsevents.VideoRunRequestedV1{
-			RunID:              run.ID,
-			WorkflowID:         run.WorkflowID,
-			ModelProfileID:     workflow.ModelProfileID,
-			InputPayload:       req.InputPayload,
-			AutoPublish:        run.AutoPublish,
-			Priority:           run.Priority,
-			ContentSuitability: workflow.ContentSuitability,
-			AgeBand:            workflow.AgeBand,
-			RequestedBy:        actor,
-			RequestedAt:        time.Now().UTC().Format(time.RFC3339),
-			TraceID:            run.ID,
-		})
-		if err != nil {
+		if err := flushEvents(r.Context(), repo, bus); err != nil {
 			httpx.WriteAPIError(w, http.StatusBadGateway, "nim_provider_error", err.Error())
 			return
 		}
@@ -25,6 +25,10 @@ func PutAdminModelProfile(repo Repository) http.HandlerFunc {
 			httpx.WriteJSON(w, http.StatusBadRequest, apiErr)
 			return
 		}
+		if apiErr := validateProvider(req.Provider); apiErr != nil {
+			httpx.WriteJSON(w, http.StatusBadRequest, apiErr)
+			return
+		}
 		actor := "admin-system"
 		if principal, ok := authz.PrincipalFrom(r.Context()); ok {
 			actor = actorIDFromPrincipal(principal)
@@ -42,14 +46,6 @@ func PutAdminModelProfile(repo Repository) http.HandlerFunc {
 			httpx.WriteAPIError(w, http.StatusInternalServerError, "workflow_error", err.Error())
 			return
 		}
-		httpx.WriteJSON(w, http.StatusOK, contractsapi.AdminModelProfile{
-			ModelProfileID: profile.ID,
-			Provider:       profile.Provider,
-			BaseURL:        profile.BaseURL,
-			ModelID:        profile.ModelID,
-			TimeoutMS:      profile.TimeoutMS,
-			MaxRetries:     profile.MaxRetries,
-			SafetyPreset:   profile.SafetyPreset,
-		})
+		httpx.WriteJSON(w, http.StatusOK, modelProfileResponse(profile))
 	}
 }
@@ -1,20 +1,31 @@
 package internal
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+)
+
+type workflowEvent func(WorkflowTemplate) (queue.Event, error)
+
+type runEvent func(WorkflowRun) (queue.Event, error)
 
 type Repository interface {
 	ListWorkflows() ([]WorkflowTemplate, error)
-	CreateWorkflow(workflow WorkflowTemplate, createdBy string) (WorkflowTemplate, error)
+	CreateWorkflow(workflow WorkflowTemplate, createdBy string, events ...workflowEvent) (WorkflowTemplate, error)
 	UpdateWorkflow(workflow WorkflowTemplate, updatedBy string) (WorkflowTemplate, bool, error)
 	DeleteWorkflow(workflowID, deletedBy string) (bool, error)
 	FindWorkflow(workflowID string) (WorkflowTemplate, bool, error)
-	CreateRun(run WorkflowRun, createdBy string) (WorkflowRun, error)
+	CreateRun(run WorkflowRun, createdBy string, events ...runEvent) (WorkflowRun, error)
 	FindRun(runID string) (WorkflowRun, bool, error)
 	ListRunLogs(runID string) ([]WorkflowRunLog, error)
 	AppendRunLog(log WorkflowRunLog) error
-	SetRunStatus(runID, status, lastError string) (bool, error)
+	SetRunStatus(runID, status, lastError string, events ...queue.Event) (bool, error)
 	GetModelProfile(modelProfileID string) (ModelProfile, bool, error)
 	PutModelProfile(profile ModelProfile, updatedBy string) (ModelProfile, error)
+	ListDeadLetters(filter DeadLetterFilter) ([]DeadLetter, error)
+	FindDeadLetter(deadLetterID string) (DeadLetter, bool, error)
+	MarkDeadLetterRedriven(deadLetterID, redriveEventID, redrivenBy string, events ...queue.Event) (bool, error)
 }
 
 type runRequestedPayload struct {
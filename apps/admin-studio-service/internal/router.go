@@ -2,16 +2,18 @@ package internal
 
 import (
 	"net/http"
+	"os"
 
 	"github.com/delqhi/mikasmissions/platform/libs/authz"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
 )
 
-func NewMux(repo Repository) *http.ServeMux {
+func NewMux(repo Repository) http.Handler {
 	return NewMuxWithBus(repo, nil)
 }
 
-func NewMuxWithBus(repo Repository, bus queue.Bus) *http.ServeMux {
+func NewMuxWithBus(repo Repository, bus queue.Bus) http.Handler {
 	authorizer := authz.NewHTTPAuthorizerFromEnv()
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /v1/admin/workflows", authorizer.Wrap([]string{"admin", "service"}, GetAdminWorkflows(repo)))
@@ -22,12 +24,16 @@ func NewMuxWithBus(repo Repository, bus queue.Bus) *http.ServeMux {
 	mux.HandleFunc("GET /v1/admin/runs/{run_id}", authorizer.Wrap([]string{"admin", "service"}, GetAdminRun(repo)))
 	mux.HandleFunc("GET /v1/admin/runs/{run_id}/logs", authorizer.Wrap([]string{"admin", "service"}, GetAdminRunLogs(repo)))
 	mux.HandleFunc("POST /v1/admin/runs/{run_id}/retry", authorizer.Wrap([]string{"admin", "service"}, PostAdminRunRetry(repo, bus)))
-	mux.HandleFunc("POST /v1/admin/runs/{run_id}/cancel", authorizer.Wrap([]string{"admin", "service"}, PostAdminRunCancel(repo)))
+	mux.HandleFunc("POST /v1/admin/runs/{run_id}/cancel", authorizer.Wrap([]string{"admin", "service"}, PostAdminRunCancel(repo, bus)))
+	mux.HandleFunc("POST /v1/admin/runs/{run_id}/generation-callback", PostGenerationCallback(repo, bus, os.Getenv("GEN_CALLBACK_TOKEN")))
 	mux.HandleFunc("GET /v1/admin/model-profiles/{id}", authorizer.Wrap([]string{"admin", "service"}, GetAdminModelProfile(repo)))
 	mux.HandleFunc("PUT /v1/admin/model-profiles/{id}", authorizer.Wrap([]string{"admin", "service"}, PutAdminModelProfile(repo)))
+	mux.HandleFunc("GET /v1/admin/dead-letters", authorizer.Wrap([]string{"admin", "service"}, GetAdminDeadLetters(repo)))
+	mux.HandleFunc("GET /v1/admin/dead-letters/{dead_letter_id}", authorizer.Wrap([]string{"admin", "service"}, GetAdminDeadLetter(repo)))
+	mux.HandleFunc("POST /v1/admin/dead-letters/{dead_letter_id}/redrive", authorizer.Wrap([]string{"admin", "service"}, PostAdminDeadLetterRedrive(repo, bus)))
 	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
-	return mux
+	return observability.Instrument("admin-studio-service", mux)
 }
@@ -0,0 +1,23 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/google/uuid"
+)
+
+func newRunCancelledEvent(ctx context.Context, runID, actor, reason string) (queue.Event, error) {
+	event, err := queue.NewJSONEvent(queue.ContextWithTraceID(ctx, runID), "video.run.cancelled.v1", uuid.NewString(), contractsevents.VideoRunCancelledV1{
+		RunID:       runID,
+		CancelledBy: actor,
+		Reason:      reason,
+		CancelledAt: time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return queue.Event{}, err
+	}
+	return event.WithOrderingKey(runID), nil
+}
@@ -0,0 +1,31 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/google/uuid"
+)
+
+func newRunRequestedEvent(ctx context.Context, run WorkflowRun, workflow WorkflowTemplate, actor string) (queue.Event, error) {
+	event, err := queue.NewJSONEvent(queue.ContextWithTraceID(ctx, run.ID), "video.run.requested.v1", uuid.NewString(), contractsevents.VideoRunRequestedV1{
+		RunID:              run.ID,
+		WorkflowID:         run.WorkflowID,
+		ModelProfileID:     workflow.ModelProfileID,
+		InputPayload:       run.InputPayload,
+		AutoPublish:        run.AutoPublish,
+		Priority:           run.Priority,
+		ContentSuitability: workflow.ContentSuitability,
+		AgeBand:            workflow.AgeBand,
+		Steps:              workflow.Steps,
+		RequestedBy:        actor,
+		RequestedAt:        time.Now().UTC().Format(time.RFC3339),
+		TraceID:            run.ID,
+	})
+	if err != nil {
+		return queue.Event{}, err
+	}
+	return event.WithOrderingKey(run.ID), nil
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
 	"github.com/google/uuid"
 )
 
@@ -15,13 +16,17 @@ type Store struct {
 	runs        map[string]WorkflowRun
 	runLogs     map[string][]WorkflowRunLog
 	modelConfig map[string]ModelProfile
+	deadLetters map[string]DeadLetter
+	events      *queue.Outbox
 }
 
 func NewStore() *Store {
 	return &Store{
-		workflows: map[string]WorkflowTemplate{},
-		runs:      map[string]WorkflowRun{},
-		runLogs:   map[string][]WorkflowRunLog{},
+		workflows:   map[string]WorkflowTemplate{},
+		runs:        map[string]WorkflowRun{},
+		runLogs:     map[string][]WorkflowRunLog{},
+		deadLetters: map[string]DeadLetter{},
+		events:      queue.NewOutbox(),
 		modelConfig: map[string]ModelProfile{
 			"nim-default": {
 				ID:           "nim-default",
@@ -47,12 +52,17 @@ func (s *Store) ListWorkflows() ([]WorkflowTemplate, error) {
 	return result, nil
 }
 
-func (s *Store) CreateWorkflow(workflow WorkflowTemplate, _ string) (WorkflowTemplate, error) {
+func (s *Store) CreateWorkflow(workflow WorkflowTemplate, _ string, events ...workflowEvent) (WorkflowTemplate, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	workflow.ID = uuid.NewString()
 	workflow.Version = 1
+	built, err := buildWorkflowEvents(workflow, events)
+	if err != nil {
+		return WorkflowTemplate{}, err
+	}
 	s.workflows[workflow.ID] = workflow
+	s.events.Add(built...)
 	return workflow, nil
 }
 
@@ -85,7 +95,7 @@ func (s *Store) FindWorkflow(workflowID string) (WorkflowTemplate, bool, error)
 	return workflow, ok, nil
 }
 
-func (s *Store) CreateRun(run WorkflowRun, _ string) (WorkflowRun, error) {
+func (s *Store) CreateRun(run WorkflowRun, _ string, events ...runEvent) (WorkflowRun, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	run.ID = uuid.NewString()
@@ -93,7 +103,12 @@ func (s *Store) CreateRun(run WorkflowRun, _ string) (WorkflowRun, error) {
 	if len(run.InputPayload) == 0 {
 		run.InputPayload = json.RawMessage(`{}`)
 	}
+	built, err := buildRunEvents(run, events)
+	if err != nil {
+		return WorkflowRun{}, err
+	}
 	s.runs[run.ID] = run
+	s.events.Add(built...)
 	return run, nil
 }
 
@@ -123,7 +138,7 @@ func (s *Store) AppendRunLog(logEntry WorkflowRunLog) error {
 	return nil
 }
 
-func (s *Store) SetRunStatus(runID, status, lastError string) (bool, error) {
+func (s *Store) SetRunStatus(runID, status, lastError string, events ...queue.Event) (bool, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	run, ok := s.runs[runID]
@@ -133,6 +148,7 @@ func (s *Store) SetRunStatus(runID, status, lastError string) (bool, error) {
 	run.Status = status
 	run.LastError = lastError
 	s.runs[runID] = run
+	s.events.Add(events...)
 	return true, nil
 }
 
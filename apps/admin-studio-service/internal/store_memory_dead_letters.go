@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+)
+
+func (s *Store) RecordDeadLetter(letter DeadLetter) DeadLetter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	letter.ID = strconv.Itoa(len(s.deadLetters) + 1)
+	if letter.Status == "" {
+		letter.Status = deadLetterOpen
+	}
+	s.deadLetters[letter.ID] = letter
+	return letter
+}
+
+func (s *Store) ListDeadLetters(filter DeadLetterFilter) ([]DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]DeadLetter, 0, len(s.deadLetters))
+	for _, letter := range s.deadLetters {
+		if (filter.Status == "" || letter.Status == filter.Status) && (filter.Topic == "" || letter.OriginalTopic == filter.Topic) {
+			result = append(result, letter)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		left, _ := strconv.Atoi(result[i].ID)
+		right, _ := strconv.Atoi(result[j].ID)
+		return left > right
+	})
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+func (s *Store) FindDeadLetter(deadLetterID string) (DeadLetter, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	letter, ok := s.deadLetters[deadLetterID]
+	return letter, ok, nil
+}
+
+func (s *Store) MarkDeadLetterRedriven(deadLetterID, redriveEventID, redrivenBy string, events ...queue.Event) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	letter, ok := s.deadLetters[deadLetterID]
+	if !ok || letter.Status != deadLetterOpen {
+		return false, nil
+	}
+	letter.Status = deadLetterRedriven
+	letter.RedriveEventID = redriveEventID
+	letter.RedrivenBy = redrivenBy
+	letter.RedrivenAt = time.Now().UTC().Format(time.RFC3339)
+	s.deadLetters[deadLetterID] = letter
+	s.events.Add(events...)
+	return true, nil
+}
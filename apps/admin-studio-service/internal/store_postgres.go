@@ -1,9 +1,12 @@
 package internal
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
@@ -11,6 +14,11 @@ type PostgresStore struct {
 	db *sql.DB
 }
 
+type sqlRunner interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
 func NewPostgresStore(databaseURL string) (*PostgresStore, error) {
 	db, err := sql.Open("pgx", databaseURL)
 	if err != nil {
@@ -23,6 +31,25 @@ func NewPostgresStore(databaseURL string) (*PostgresStore, error) {
 	return &PostgresStore{db: db}, nil
 }
 
+func (s *PostgresStore) withOutbox(write func(tx *sql.Tx) ([]queue.Event, error)) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+	events, err := write(tx)
+	if err != nil {
+		return err
+	}
+	if err := queue.EnqueueOutboxTx(context.Background(), tx, events...); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
 func (s *PostgresStore) Close() error {
 	return s.db.Close()
 }
@@ -5,12 +5,12 @@ import (
 	"fmt"
 )
 
-func (s *PostgresStore) snapshotWorkflowVersion(workflow WorkflowTemplate, actor string) error {
+func snapshotWorkflowVersion(db sqlRunner, workflow WorkflowTemplate, actor string) error {
 	snapshot, err := json.Marshal(workflow)
 	if err != nil {
 		return fmt.Errorf("encode workflow snapshot: %w", err)
 	}
-	_, err = s.db.Exec(
+	_, err = db.Exec(
 		`insert into creator.workflow_template_versions
 		 (workflow_id, version, snapshot, created_by)
 		 values ($1::uuid, $2, $3::jsonb, $4)`,
@@ -0,0 +1,138 @@
+package internal
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+)
+
+const deadLetterColumns = `id::text, event_id, original_topic, consumer, error, attempts,
+	to_char(failed_at at time zone 'utc', 'YYYY-MM-DD"T"HH24:MI:SS"Z"'), payload, headers, status,
+	coalesce(redrive_event_id, ''), coalesce(redriven_by, ''),
+	coalesce(to_char(redriven_at at time zone 'utc', 'YYYY-MM-DD"T"HH24:MI:SS"Z"'), '')`
+
+type deadLetterScanner interface {
+	Scan(dest ...any) error
+}
+
+func (s *PostgresStore) ListDeadLetters(filter DeadLetterFilter) ([]DeadLetter, error) {
+	rows, err := s.db.Query(
+		`select `+deadLetterColumns+`
+		 from events.dead_letters
+		 where ($1 = '' or status = $1) and ($2 = '' or original_topic = $2)
+		 order by id desc
+		 limit $3`,
+		filter.Status,
+		filter.Topic,
+		filter.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list dead letters: %w", err)
+	}
+	defer rows.Close()
+	letters := make([]DeadLetter, 0, filter.Limit)
+	for rows.Next() {
+		letter, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, letter)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate dead letters: %w", err)
+	}
+	return letters, nil
+}
+
+func (s *PostgresStore) FindDeadLetter(deadLetterID string) (DeadLetter, bool, error) {
+	id, ok := parseDeadLetterID(deadLetterID)
+	if !ok {
+		return DeadLetter{}, false, nil
+	}
+	letter, err := scanDeadLetter(s.db.QueryRow(
+		`select `+deadLetterColumns+` from events.dead_letters where id = $1`,
+		id,
+	))
+	if err == sql.ErrNoRows {
+		return DeadLetter{}, false, nil
+	}
+	if err != nil {
+		return DeadLetter{}, false, err
+	}
+	return letter, true, nil
+}
+
+func (s *PostgresStore) MarkDeadLetterRedriven(deadLetterID, redriveEventID, redrivenBy string, events ...queue.Event) (bool, error) {
+	id, ok := parseDeadLetterID(deadLetterID)
+	if !ok {
+		return false, nil
+	}
+	var updated bool
+	err := s.withOutbox(func(tx *sql.Tx) ([]queue.Event, error) {
+		result, err := tx.Exec(
+			`update events.dead_letters
+			 set status = 'redriven',
+			     redrive_event_id = $2,
+			     redriven_by = $3,
+			     redriven_at = now()
+			 where id = $1 and status = 'open'`,
+			id,
+			redriveEventID,
+			redrivenBy,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("mark dead letter redriven: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("dead letter rows affected: %w", err)
+		}
+		updated = affected > 0
+		if !updated {
+			return nil, nil
+		}
+		return events, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated, nil
+}
+
+func parseDeadLetterID(deadLetterID string) (int64, bool) {
+	id, err := strconv.ParseInt(deadLetterID, 10, 64)
+	return id, err == nil && id > 0
+}
+
+func scanDeadLetter(row deadLetterScanner) (DeadLetter, error) {
+	var letter DeadLetter
+	var headers []byte
+	err := row.Scan(
+		&letter.ID,
+		&letter.EventID,
+		&letter.OriginalTopic,
+		&letter.Consumer,
+		&letter.Error,
+		&letter.Attempts,
+		&letter.FailedAt,
+		&letter.Payload,
+		&headers,
+		&letter.Status,
+		&letter.RedriveEventID,
+		&letter.RedrivenBy,
+		&letter.RedrivenAt,
+	)
+	if err == sql.ErrNoRows {
+		return DeadLetter{}, err
+	}
+	if err != nil {
+		return DeadLetter{}, fmt.Errorf("scan dead letter: %w", err)
+	}
+	if err := json.Unmarshal(headers, &letter.Headers); err != nil {
+		return DeadLetter{}, fmt.Errorf("decode dead letter headers: %w", err)
+	}
+	return letter, nil
+}
@@ -4,14 +4,32 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
 )
 
-func (s *PostgresStore) CreateRun(run WorkflowRun, createdBy string) (WorkflowRun, error) {
+func (s *PostgresStore) CreateRun(run WorkflowRun, createdBy string, events ...runEvent) (WorkflowRun, error) {
 	if len(run.InputPayload) == 0 {
 		run.InputPayload = json.RawMessage(`{}`)
 	}
 	var created WorkflowRun
-	err := s.db.QueryRow(
+	err := s.withOutbox(func(tx *sql.Tx) ([]queue.Event, error) {
+		var err error
+		created, err = insertRun(tx, run, createdBy)
+		if err != nil {
+			return nil, err
+		}
+		return buildRunEvents(created, events)
+	})
+	if err != nil {
+		return WorkflowRun{}, err
+	}
+	return created, nil
+}
+
+func insertRun(db sqlRunner, run WorkflowRun, createdBy string) (WorkflowRun, error) {
+	var created WorkflowRun
+	err := db.QueryRow(
 		`insert into creator.workflow_runs
 		 (workflow_id, status, input_payload, priority, auto_publish, created_by, updated_at)
 		 values ($1::uuid, 'requested', $2::jsonb, $3, $4, $5, now())
@@ -102,8 +120,24 @@ func (s *PostgresStore) AppendRunLog(logEntry WorkflowRunLog) error {
 	return nil
 }
 
-func (s *PostgresStore) SetRunStatus(runID, status, lastError string) (bool, error) {
-	result, err := s.db.Exec(
+func (s *PostgresStore) SetRunStatus(runID, status, lastError string, events ...queue.Event) (bool, error) {
+	var updated bool
+	err := s.withOutbox(func(tx *sql.Tx) ([]queue.Event, error) {
+		var err error
+		updated, err = updateRunStatus(tx, runID, status, lastError)
+		if err != nil || !updated {
+			return nil, err
+		}
+		return events, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated, nil
+}
+
+func updateRunStatus(db sqlRunner, runID, status, lastError string) (bool, error) {
+	result, err := db.Exec(
 		`update creator.workflow_runs
 		 set status = $2,
 		     last_error = $3,
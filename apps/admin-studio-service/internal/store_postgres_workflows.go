@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
 )
 
 func (s *PostgresStore) ListWorkflows() ([]WorkflowTemplate, error) {
@@ -44,13 +46,29 @@ func (s *PostgresStore) ListWorkflows() ([]WorkflowTemplate, error) {
 	return result, nil
 }
 
-func (s *PostgresStore) CreateWorkflow(workflow WorkflowTemplate, createdBy string) (WorkflowTemplate, error) {
+func (s *PostgresStore) CreateWorkflow(workflow WorkflowTemplate, createdBy string, events ...workflowEvent) (WorkflowTemplate, error) {
+	var created WorkflowTemplate
+	err := s.withOutbox(func(tx *sql.Tx) ([]queue.Event, error) {
+		var err error
+		created, err = insertWorkflow(tx, workflow, createdBy)
+		if err != nil {
+			return nil, err
+		}
+		return buildWorkflowEvents(created, events)
+	})
+	if err != nil {
+		return WorkflowTemplate{}, err
+	}
+	return created, nil
+}
+
+func insertWorkflow(db sqlRunner, workflow WorkflowTemplate, createdBy string) (WorkflowTemplate, error) {
 	steps, err := json.Marshal(workflow.Steps)
 	if err != nil {
 		return WorkflowTemplate{}, fmt.Errorf("encode steps: %w", err)
 	}
 	var created WorkflowTemplate
-	err = s.db.QueryRow(
+	err = db.QueryRow(
 		`insert into creator.workflow_templates
 		 (name, description, content_suitability, age_band, steps, model_profile_id, safety_profile, version, created_by, updated_at)
 		 values ($1, $2, $3, $4, $5::jsonb, $6, $7, 1, $8, now())
@@ -80,7 +98,7 @@ func (s *PostgresStore) CreateWorkflow(workflow WorkflowTemplate, createdBy stri
 	if err := json.Unmarshal(steps, &created.Steps); err != nil {
 		return WorkflowTemplate{}, fmt.Errorf("decode workflow steps: %w", err)
 	}
-	if err := s.snapshotWorkflowVersion(created, createdBy); err != nil {
+	if err := snapshotWorkflowVersion(db, created, createdBy); err != nil {
 		return WorkflowTemplate{}, err
 	}
 	return created, nil
@@ -135,7 +153,7 @@ func (s *PostgresStore) UpdateWorkflow(workflow WorkflowTemplate, updatedBy stri
 	if err := json.Unmarshal(steps, &updated.Steps); err != nil {
 		return WorkflowTemplate{}, false, fmt.Errorf("decode workflow steps: %w", err)
 	}
-	if err := s.snapshotWorkflowVersion(updated, updatedBy); err != nil {
+	if err := snapshotWorkflowVersion(s.db, updated, updatedBy); err != nil {
 		return WorkflowTemplate{}, false, err
 	}
 	return updated, true, nil
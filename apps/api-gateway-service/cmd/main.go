@@ -6,9 +6,17 @@ import (
 	"os"
 
 	"github.com/delqhi/mikasmissions/platform/apps/api-gateway-service/internal"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
+	"github.com/delqhi/mikasmissions/platform/libs/tracing"
 )
 
 func main() {
+	tracer, err := tracing.InitFromEnv("api-gateway-service", os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer tracer.Close()
+
 	upstreams, err := internal.LoadUpstreams()
 	if err != nil {
 		log.Fatal(err)
@@ -19,6 +27,7 @@ func main() {
 		addr = ":" + fromEnv
 	}
 	log.Printf("api-gateway-service listening on %s", addr)
+	observability.ServeMetrics("api-gateway-service", mux, os.Getenv)
 	if err := http.ListenAndServe(addr, mux); err != nil {
 		log.Fatal(err)
 	}
@@ -12,6 +12,8 @@ func requiredRolesForPattern(pattern string) []string {
 		return nil
 	case "POST /v1/parents/consent/verify":
 		return nil
+	case "POST /v1/admin/runs/{run_id}/generation-callback":
+		return nil
 	case "GET /v1/parents/dashboard":
 		return []string{"parent", "service"}
 	case "GET /v1/parents/controls/{child_profile_id}":
@@ -64,6 +66,12 @@ func requiredRolesForPattern(pattern string) []string {
 		return []string{"admin", "service"}
 	case "PUT /v1/admin/model-profiles/{id}":
 		return []string{"admin", "service"}
+	case "GET /v1/admin/dead-letters":
+		return []string{"admin", "service"}
+	case "GET /v1/admin/dead-letters/{dead_letter_id}":
+		return []string{"admin", "service"}
+	case "POST /v1/admin/dead-letters/{dead_letter_id}/redrive":
+		return []string{"admin", "service"}
 	default:
 		return nil
 	}
@@ -4,10 +4,13 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 )
 
 func proxyTo(target *url.URL) http.Handler {
 	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.Transport = observability.NewTransport(nil)
 	original := proxy.ErrorHandler
 	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
 		if original != nil {
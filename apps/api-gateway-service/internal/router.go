@@ -1,6 +1,10 @@
 package internal
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
+)
 
 func NewMux(upstreams Upstreams) http.Handler {
 	mux := http.NewServeMux()
@@ -49,12 +53,16 @@ func NewMux(upstreams Upstreams) http.Handler {
 	mux.Handle("GET /v1/admin/runs/{run_id}/logs", adminStudio)
 	mux.Handle("POST /v1/admin/runs/{run_id}/retry", adminStudio)
 	mux.Handle("POST /v1/admin/runs/{run_id}/cancel", adminStudio)
+	mux.Handle("POST /v1/admin/runs/{run_id}/generation-callback", adminStudio)
 	mux.Handle("GET /v1/admin/model-profiles/{id}", adminStudio)
 	mux.Handle("PUT /v1/admin/model-profiles/{id}", adminStudio)
+	mux.Handle("GET /v1/admin/dead-letters", adminStudio)
+	mux.Handle("GET /v1/admin/dead-letters/{dead_letter_id}", adminStudio)
+	mux.Handle("POST /v1/admin/dead-letters/{dead_letter_id}/redrive", adminStudio)
 
 	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
-	return newGatewayHandler(mux, newGatewayAuthFromEnv())
+	return observability.InstrumentHandler("api-gateway-service", mux, newGatewayHandler(mux, newGatewayAuthFromEnv()))
 }
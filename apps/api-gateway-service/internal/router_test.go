@@ -8,6 +8,8 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 )
 
 func mustParseURL(t *testing.T, raw string) *url.URL {
@@ -93,8 +95,12 @@ func TestGatewayRoutesToExpectedUpstream(t *testing.T) {
 		{method: http.MethodGet, target: "/v1/admin/runs/run-1/logs", expected: "admin-studio"},
 		{method: http.MethodPost, target: "/v1/admin/runs/run-1/retry", body: `{}`, expected: "admin-studio"},
 		{method: http.MethodPost, target: "/v1/admin/runs/run-1/cancel", body: `{}`, expected: "admin-studio"},
+		{method: http.MethodPost, target: "/v1/admin/runs/run-1/generation-callback", body: `{}`, expected: "admin-studio"},
 		{method: http.MethodGet, target: "/v1/admin/model-profiles/default", expected: "admin-studio"},
 		{method: http.MethodPut, target: "/v1/admin/model-profiles/default", body: `{}`, expected: "admin-studio"},
+		{method: http.MethodGet, target: "/v1/admin/dead-letters?status=open", expected: "admin-studio"},
+		{method: http.MethodGet, target: "/v1/admin/dead-letters/1", expected: "admin-studio"},
+		{method: http.MethodPost, target: "/v1/admin/dead-letters/1/redrive", body: `{}`, expected: "admin-studio"},
 	}
 
 	for _, tc := range cases {
@@ -131,3 +137,37 @@ func TestGatewayHealthz(t *testing.T) {
 		t.Fatalf("expected ok body, got %q", rr.Body.String())
 	}
 }
+
+func TestGatewayForwardsRequestIDAndServesMetrics(t *testing.T) {
+	var forwarded string
+	catalog := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Get("X-Request-Id")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer catalog.Close()
+	mux := NewMux(Upstreams{Catalog: mustParseURL(t, catalog.URL)})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/catalog/episodes/ep-1", nil)
+	req.Header.Set("X-Request-Id", "req-gateway-1")
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || forwarded != "req-gateway-1" || rr.Header().Get("X-Request-Id") != "req-gateway-1" {
+		t.Fatalf("expected request id forwarded, got code=%d upstream=%q response=%q", rr.Code, forwarded, rr.Header().Get("X-Request-Id"))
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rr.Code == http.StatusOK {
+		t.Fatalf("expected /metrics to stay off the public gateway, got %d", rr.Code)
+	}
+	metrics, ok := observability.MetricsHandler(mux)
+	if !ok {
+		t.Fatalf("expected gateway handler to expose metrics")
+	}
+	rr = httptest.NewRecorder()
+	metrics.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	want := `http_requests_total{method="GET",route="/v1/catalog/episodes/{id}",code="200"} 1`
+	if rr.Code != http.StatusOK || !bytes.Contains(rr.Body.Bytes(), []byte(want)) {
+		t.Fatalf("expected %q in metrics, got %d:\n%s", want, rr.Code, rr.Body.String())
+	}
+}
@@ -7,9 +7,17 @@ import (
 	"os"
 
 	"github.com/delqhi/mikasmissions/platform/apps/billing-service/internal"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
+	"github.com/delqhi/mikasmissions/platform/libs/tracing"
 )
 
 func main() {
+	tracer, err := tracing.InitFromEnv("billing-service", os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer tracer.Close()
+
 	repository, err := internal.OpenRepositoryFromEnv()
 	if err != nil {
 		log.Fatal(err)
@@ -27,6 +35,7 @@ func main() {
 		addr = ":" + fromEnv
 	}
 	log.Printf("billing-service listening on %s", addr)
+	observability.ServeMetrics("billing-service", mux, os.Getenv)
 	if err := http.ListenAndServe(addr, mux); err != nil {
 		log.Fatal(err)
 	}
@@ -1,13 +1,17 @@
 package internal
 
-import "net/http"
+import (
+	"net/http"
 
-func NewMux(repo Repository) *http.ServeMux {
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
+)
+
+func NewMux(repo Repository) http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /v1/billing/entitlements", GetEntitlement(repo))
 	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
-	return mux
+	return observability.Instrument("billing-service", mux)
 }
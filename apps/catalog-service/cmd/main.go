@@ -7,9 +7,17 @@ import (
 	"os"
 
 	"github.com/delqhi/mikasmissions/platform/apps/catalog-service/internal"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
+	"github.com/delqhi/mikasmissions/platform/libs/tracing"
 )
 
 func main() {
+	tracer, err := tracing.InitFromEnv("catalog-service", os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer tracer.Close()
+
 	repo, err := internal.OpenRepositoryFromEnv()
 	if err != nil {
 		log.Fatal(err)
@@ -27,6 +35,7 @@ func main() {
 		addr = ":" + fromEnv
 	}
 	log.Printf("catalog-service listening on %s", addr)
+	observability.ServeMetrics("catalog-service", mux, os.Getenv)
 	if err := http.ListenAndServe(addr, mux); err != nil {
 		log.Fatal(err)
 	}
@@ -1,8 +1,12 @@
 package internal
 
-import "net/http"
+import (
+	"net/http"
 
-func NewMux(repo Repository) *http.ServeMux {
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
+)
+
+func NewMux(repo Repository) http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /v1/catalog/episodes/{id}", GetEpisode(repo))
 	mux.HandleFunc("GET /internal/catalog/episodes", GetInternalEpisodes(repo))
@@ -11,5 +15,5 @@ func NewMux(repo Repository) *http.ServeMux {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
-	return mux
+	return observability.Instrument("catalog-service", mux)
 }
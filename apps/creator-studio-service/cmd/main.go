@@ -6,11 +6,19 @@ import (
 	"os"
 
 	"github.com/delqhi/mikasmissions/platform/apps/creator-studio-service/internal"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/tracing"
 )
 
 func main() {
-	bus, err := queue.NewBusFromEnv()
+	tracer, err := tracing.InitFromEnv("creator-studio-service", os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer tracer.Close()
+
+	bus, err := queue.NewBusFromEnv(queue.WithProducer("creator-studio-service"))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -30,6 +38,7 @@ func main() {
 		addr = ":" + fromEnv
 	}
 	log.Printf("creator-studio-service listening on %s", addr)
+	observability.ServeMetrics("creator-studio-service", mux, os.Getenv)
 	if err := http.ListenAndServe(addr, mux); err != nil {
 		log.Fatal(err)
 	}
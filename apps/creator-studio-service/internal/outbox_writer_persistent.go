@@ -10,6 +10,6 @@ type persistentOutboxWriter struct {
 	outbox *queue.PersistentOutbox
 }
 
-func (w *persistentOutboxWriter) EnqueueAndFlush(_ context.Context, _ queue.Bus, event queue.Event) error {
-	return w.outbox.Add(event)
+func (w *persistentOutboxWriter) EnqueueAndFlush(ctx context.Context, _ queue.Bus, event queue.Event) error {
+	return w.outbox.Add(ctx, event)
 }
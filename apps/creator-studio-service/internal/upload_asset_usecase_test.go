@@ -7,6 +7,7 @@ import (
 
 	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
 )
 
 func TestUploadAssetPublishesMediaUploadedEvent(t *testing.T) {
@@ -32,6 +33,7 @@ func TestUploadAssetPublishesMediaUploadedEvent(t *testing.T) {
 	if _, err := service.UploadAsset(context.Background(), UploadRequest{SourceURL: "https://cdn.local/a.mp4", UploaderID: "u-1"}); err != nil {
 		t.Fatalf("upload asset: %v", err)
 	}
+	testkit.WaitBusIdle(t, bus)
 	if seen != 1 {
 		t.Fatalf("expected 1 event, got %d", seen)
 	}
@@ -7,10 +7,18 @@ import (
 	"os"
 
 	"github.com/delqhi/mikasmissions/platform/apps/identity-service/internal"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/tracing"
 )
 
 func main() {
+	tracer, err := tracing.InitFromEnv("identity-service", os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer tracer.Close()
+
 	store, err := internal.OpenRepositoryFromEnv()
 	if err != nil {
 		log.Fatal(err)
@@ -20,7 +28,7 @@ func main() {
 			_ = closer.Close()
 		}()
 	}
-	bus, err := queue.NewBusFromEnv()
+	bus, err := queue.NewBusFromEnv(queue.WithProducer("identity-service"))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -31,6 +39,7 @@ func main() {
 		addr = ":" + fromEnv
 	}
 	log.Printf("identity-service listening on %s", addr)
+	observability.ServeMetrics("identity-service", mux, os.Getenv)
 	if err := http.ListenAndServe(addr, mux); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+)
+
+type eventFlusher interface {
+	FlushEvents(ctx context.Context, bus queue.Bus) error
+}
+
+func flushEvents(ctx context.Context, store Repository, bus queue.Bus) error {
+	flusher, ok := store.(eventFlusher)
+	if !ok || bus == nil {
+		return nil
+	}
+	return flusher.FlushEvents(ctx, bus)
+}
+
+func (s *Store) FlushEvents(ctx context.Context, bus queue.Bus) error {
+	return s.events.Flush(ctx, bus)
+}
+
+func buildConsentEvents(consent Consent, builders []consentEvent) ([]queue.Event, error) {
+	events := make([]queue.Event, 0, len(builders))
+	for _, build := range builders {
+		event, err := build(consent)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
+)
+
+type failingBus struct {
+	queue.Bus
+}
+
+func (failingBus) Publish(context.Context, queue.Event) error {
+	return errors.New("bus unavailable")
+}
+
+func TestPostConsentVerifyPublishesEventForStoredConsent(t *testing.T) {
+	store := NewStore()
+	parent, _ := store.CreateParent("parent@example.com", "DE", "de", "hash")
+	bus := queue.NewInMemoryBus()
+	defer bus.Close()
+	var published contractsevents.ConsentVerifiedV1
+	_ = bus.Subscribe(context.Background(), "consent.verified.v1", "test", func(_ context.Context, event queue.Event) error {
+		return json.Unmarshal(event.Payload, &published)
+	})
+	mux := NewMuxWithBus(store, bus)
+	req := httptest.NewRequest(http.MethodPost, "/v1/parents/consent/verify", strings.NewReader(`{"parent_user_id":"`+parent.ID+`","method":"card_check","challenge":"ok"}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp contractsapi.ParentConsentVerifyResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if published.ConsentID == "" || published.ConsentID != resp.ConsentID {
+		t.Fatalf("expected event for consent %q, got %+v", resp.ConsentID, published)
+	}
+}
+
+func TestMemoryStoreKeepsEventsUntilPublished(t *testing.T) {
+	store := NewStore()
+	event, err := queue.NewJSONEvent(context.Background(), "parent.controls.updated.v1", "evt-1", map[string]string{"child_profile_id": "child-1"})
+	if err != nil {
+		t.Fatalf("new event: %v", err)
+	}
+	if err := store.SetControls("child-1", contractsapi.DefaultStrictControls(), event); err != nil {
+		t.Fatalf("set controls: %v", err)
+	}
+	if err := flushEvents(context.Background(), store, failingBus{}); err == nil {
+		t.Fatalf("expected flush to surface publish failure")
+	}
+	bus := queue.NewInMemoryBus()
+	defer bus.Close()
+	received := make(chan string, 2)
+	_ = bus.Subscribe(context.Background(), "parent.controls.updated.v1", "test", func(_ context.Context, event queue.Event) error {
+		received <- event.ID
+		return nil
+	})
+	if err := flushEvents(context.Background(), store, bus); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if err := flushEvents(context.Background(), store, bus); err != nil {
+		t.Fatalf("second flush: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if len(received) != 1 || <-received != "evt-1" {
+		t.Fatalf("expected evt-1 to be published exactly once")
+	}
+}
@@ -31,17 +31,19 @@ func PostConsentVerify(store Repository, bus queue.Bus) http.HandlerFunc {
 			httpx.WriteAPIError(w, http.StatusNotFound, "parent_not_found", "parent_user_id not found")
 			return
 		}
-		consent, err := store.VerifyConsent(req.ParentUserID, req.Method)
+		consent, err := store.VerifyConsent(req.ParentUserID, req.Method, func(consent Consent) (queue.Event, error) {
+			return queue.NewJSONEvent(r.Context(), "consent.verified.v1", uuid.NewString(), contractsevents.ConsentVerifiedV1{
+				ConsentID:    consent.ID,
+				ParentUserID: req.ParentUserID,
+				Method:       req.Method,
+				VerifiedAt:   time.Now().UTC().Format(time.RFC3339),
+			})
+		})
 		if err != nil {
 			httpx.WriteAPIError(w, http.StatusInternalServerError, "consent_error", err.Error())
 			return
 		}
-		if err := publishEvent(r.Context(), bus, "consent.verified.v1", uuid.NewString(), contractsevents.ConsentVerifiedV1{
-			ConsentID:    consent.ID,
-			ParentUserID: req.ParentUserID,
-			Method:       req.Method,
-			VerifiedAt:   time.Now().UTC().Format(time.RFC3339),
-		}); err != nil {
+		if err := flushEvents(r.Context(), store, bus); err != nil {
 			httpx.WriteAPIError(w, http.StatusInternalServerError, "event_publish_failed", err.Error())
 			return
 		}
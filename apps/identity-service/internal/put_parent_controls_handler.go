@@ -48,18 +48,23 @@ func PutParentControls(store Repository, bus queue.Bus) http.HandlerFunc {
 			httpx.WriteAPIError(w, http.StatusForbidden, "child_profile_forbidden", err.Error())
 			return
 		}
-		if err := store.SetControls(childProfileID, controls); err != nil {
-			httpx.WriteAPIError(w, http.StatusInternalServerError, "controls_error", err.Error())
-			return
-		}
-		if err := publishEvent(r.Context(), bus, "parent.controls.updated.v1", uuid.NewString(), contractsevents.ParentControlsUpdatedV1{
+		event, err := queue.NewJSONEvent(r.Context(), "parent.controls.updated.v1", uuid.NewString(), contractsevents.ParentControlsUpdatedV1{
 			ParentUserID:     parentUserID,
 			ChildProfileID:   childProfileID,
 			SafetyMode:       controls.SafetyMode,
 			SessionLimitMins: controls.SessionLimitMinutes,
 			ExternalLinks:    controls.ExternalLinks,
 			AuditEventID:     uuid.NewString(),
-		}); err != nil {
+		})
+		if err != nil {
+			httpx.WriteAPIError(w, http.StatusInternalServerError, "controls_error", err.Error())
+			return
+		}
+		if err := store.SetControls(childProfileID, controls, event.WithOrderingKey(childProfileID)); err != nil {
+			httpx.WriteAPIError(w, http.StatusInternalServerError, "controls_error", err.Error())
+			return
+		}
+		if err := flushEvents(r.Context(), store, bus); err != nil {
 			httpx.WriteAPIError(w, http.StatusInternalServerError, "event_publish_failed", err.Error())
 			return
 		}
@@ -1,6 +1,11 @@
 package internal
 
-import contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+import (
+	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+)
+
+type consentEvent func(Consent) (queue.Event, error)
 
 type Repository interface {
 	CreateParent(email, country, lang, passwordHash string) (Parent, error)
@@ -8,10 +13,10 @@ type Repository interface {
 	UpdateParentLastLogin(parentID string) error
 	FindAdminByEmail(email string) (AdminUser, bool, error)
 	UpdateAdminLastLogin(adminUserID string) error
-	VerifyConsent(parentID, method string) (Consent, error)
+	VerifyConsent(parentID, method string, events ...consentEvent) (Consent, error)
 	ParentExists(parentID string) (bool, error)
 	GetControls(childProfileID string) (contractsapi.ParentalControls, error)
-	SetControls(childProfileID string, controls contractsapi.ParentalControls) error
+	SetControls(childProfileID string, controls contractsapi.ParentalControls, events ...queue.Event) error
 	SaveGateToken(childProfileID, gateToken string) error
 	IsValidGateToken(childProfileID, gateToken string) (bool, error)
 	ConsumeGateToken(childProfileID, gateToken string) (bool, error)
@@ -4,14 +4,15 @@ import (
 	"net/http"
 
 	"github.com/delqhi/mikasmissions/platform/libs/authz"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
 )
 
-func NewMux(store Repository) *http.ServeMux {
+func NewMux(store Repository) http.Handler {
 	return NewMuxWithBus(store, nil)
 }
 
-func NewMuxWithBus(store Repository, bus queue.Bus) *http.ServeMux {
+func NewMuxWithBus(store Repository, bus queue.Bus) http.Handler {
 	mux := http.NewServeMux()
 	authorizer := authz.NewHTTPAuthorizerFromEnv()
 	mux.HandleFunc("POST /v1/parents/signup", authorizer.Wrap(nil, PostSignup(store)))
@@ -28,5 +29,5 @@ func NewMuxWithBus(store Repository, bus queue.Bus) *http.ServeMux {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
-	return mux
+	return observability.Instrument("identity-service", mux)
 }
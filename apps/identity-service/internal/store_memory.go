@@ -5,6 +5,7 @@ import (
 	"time"
 
 	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
 	"github.com/google/uuid"
 )
 
@@ -31,6 +32,7 @@ type Store struct {
 	controls      map[string]contractsapi.ParentalControls
 	validGateByID map[string]gateTokenState
 	challenges    map[string]ParentGateChallenge
+	events        *queue.Outbox
 }
 
 func NewStore() *Store {
@@ -41,6 +43,7 @@ func NewStore() *Store {
 		controls:      map[string]contractsapi.ParentalControls{},
 		validGateByID: map[string]gateTokenState{},
 		challenges:    map[string]ParentGateChallenge{},
+		events:        queue.NewOutbox(),
 	}
 }
 
@@ -103,7 +106,7 @@ func (s *Store) UpdateAdminLastLogin(_ string) error {
 	return nil
 }
 
-func (s *Store) VerifyConsent(parentID, method string) (Consent, error) {
+func (s *Store) VerifyConsent(parentID, method string, events ...consentEvent) (Consent, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	consent := Consent{
@@ -112,7 +115,12 @@ func (s *Store) VerifyConsent(parentID, method string) (Consent, error) {
 		Method:   method,
 		Verified: true,
 	}
+	built, err := buildConsentEvents(consent, events)
+	if err != nil {
+		return Consent{}, err
+	}
 	s.consents[consent.ID] = consent
+	s.events.Add(built...)
 	return consent, nil
 }
 
@@ -134,10 +142,11 @@ func (s *Store) GetControls(childProfileID string) (contractsapi.ParentalControl
 	return controls, nil
 }
 
-func (s *Store) SetControls(childProfileID string, controls contractsapi.ParentalControls) error {
+func (s *Store) SetControls(childProfileID string, controls contractsapi.ParentalControls, events ...queue.Event) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.controls[childProfileID] = controls
+	s.events.Add(events...)
 	return nil
 }
 
@@ -1,9 +1,12 @@
 package internal
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
@@ -23,6 +26,25 @@ func NewPostgresStore(databaseURL string) (*PostgresStore, error) {
 	return &PostgresStore{db: db}, nil
 }
 
+func (s *PostgresStore) withOutbox(write func(tx *sql.Tx) ([]queue.Event, error)) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+	events, err := write(tx)
+	if err != nil {
+		return err
+	}
+	if err := queue.EnqueueOutboxTx(context.Background(), tx, events...); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
 func (s *PostgresStore) Close() error {
 	return s.db.Close()
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
 )
 
 func (s *PostgresStore) GetControls(childProfileID string) (contractsapi.ParentalControls, error) {
@@ -35,8 +36,17 @@ func (s *PostgresStore) GetControls(childProfileID string) (contractsapi.Parenta
 	return defaultControls, nil
 }
 
-func (s *PostgresStore) SetControls(childProfileID string, controls contractsapi.ParentalControls) error {
-	_, err := s.db.Exec(
+func (s *PostgresStore) SetControls(childProfileID string, controls contractsapi.ParentalControls, events ...queue.Event) error {
+	return s.withOutbox(func(tx *sql.Tx) ([]queue.Event, error) {
+		if err := upsertControls(tx, childProfileID, controls); err != nil {
+			return nil, err
+		}
+		return events, nil
+	})
+}
+
+func upsertControls(tx *sql.Tx, childProfileID string, controls contractsapi.ParentalControls) error {
+	_, err := tx.Exec(
 		`insert into identity.parent_controls
 		 (child_profile_id, autoplay, chat_enabled, external_links, session_limit_minutes, bedtime_window, safety_mode, updated_at)
 		 values ($1, $2, $3, $4, $5, $6, $7, now())
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
 	"github.com/google/uuid"
 )
 
@@ -25,19 +26,25 @@ func (s *PostgresStore) CreateParent(email, country, lang, passwordHash string)
 	return parent, nil
 }
 
-func (s *PostgresStore) VerifyConsent(parentID, method string) (Consent, error) {
+func (s *PostgresStore) VerifyConsent(parentID, method string, events ...consentEvent) (Consent, error) {
 	consent := Consent{
 		ID:       uuid.NewString(),
 		ParentID: parentID,
 		Method:   method,
 		Verified: true,
 	}
-	_, err := s.db.Exec(
-		`insert into identity.consents (id, parent_id, method, verified) values ($1, $2, $3, true)`,
-		consent.ID, consent.ParentID, consent.Method,
-	)
+	err := s.withOutbox(func(tx *sql.Tx) ([]queue.Event, error) {
+		_, err := tx.Exec(
+			`insert into identity.consents (id, parent_id, method, verified) values ($1, $2, $3, true)`,
+			consent.ID, consent.ParentID, consent.Method,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("insert consent: %w", err)
+		}
+		return buildConsentEvents(consent, events)
+	})
 	if err != nil {
-		return Consent{}, fmt.Errorf("insert consent: %w", err)
+		return Consent{}, err
 	}
 	return consent, nil
 }
@@ -1,13 +1,17 @@
 package internal
 
-import "net/http"
+import (
+	"net/http"
 
-func NewMux(service *Service) *http.ServeMux {
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
+)
+
+func NewMux(service *Service) http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("POST /internal/moderation/evaluate", PostEvaluateAsset(service))
 	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
-	return mux
+	return observability.Instrument("moderation-service", mux)
 }
@@ -6,11 +6,19 @@ import (
 	"os"
 
 	"github.com/delqhi/mikasmissions/platform/apps/playback-service/internal"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 	"github.com/delqhi/mikasmissions/platform/libs/profileclient"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/tracing"
 )
 
 func main() {
+	tracer, err := tracing.InitFromEnv("playback-service", os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer tracer.Close()
+
 	profileReader, err := profileclient.NewFromEnv()
 	if err != nil {
 		log.Fatal(err)
@@ -20,17 +28,25 @@ func main() {
 		internal.NewIdentityGateVerifierFromEnv(),
 		profileReader,
 	)
-	bus, err := queue.NewBusFromEnv()
+	bus, err := queue.NewBusFromEnv(queue.WithProducer("playback-service"))
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer bus.Close()
-	mux := internal.NewMuxWithBus(service, bus)
+	publisher, outbox, err := internal.NewEventPublisherFromEnv(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if outbox != nil {
+		defer outbox.Close()
+	}
+	mux := internal.NewMuxWithPublisher(service, publisher)
 	addr := ":8085"
 	if fromEnv := os.Getenv("PORT"); fromEnv != "" {
 		addr = ":" + fromEnv
 	}
 	log.Printf("playback-service listening on %s", addr)
+	observability.ServeMetrics("playback-service", mux, os.Getenv)
 	if err := http.ListenAndServe(addr, mux); err != nil {
 		log.Fatal(err)
 	}
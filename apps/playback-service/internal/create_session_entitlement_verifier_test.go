@@ -12,7 +12,7 @@ type stubEntitlementVerifier struct {
 	err      error
 }
 
-func (s stubEntitlementVerifier) IsEntitled(_ string, _ string) (bool, error) {
+func (s stubEntitlementVerifier) IsEntitled(_ context.Context, _ string, _ string) (bool, error) {
 	return s.entitled, s.err
 }
 
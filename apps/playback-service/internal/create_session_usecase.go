@@ -71,7 +71,7 @@ func (s *Service) CreateSession(ctx context.Context, req contractsapi.CreatePlay
 	if req.SessionMinutesUsed >= sessionLimit {
 		return contractsapi.CreatePlaybackSessionResponse{}, ErrSessionCapReached
 	}
-	entitled, err := resolveEntitlementAllowed(s.entitlementVerifier, req)
+	entitled, err := resolveEntitlementAllowed(ctx, s.entitlementVerifier, req)
 	if err != nil {
 		return contractsapi.CreatePlaybackSessionResponse{}, ErrEntitlementRequired
 	}
@@ -1,9 +1,13 @@
 package internal
 
-import contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+import (
+	"context"
+
+	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+)
 
 type EntitlementVerifier interface {
-	IsEntitled(childProfileID string, fallbackStatus string) (bool, error)
+	IsEntitled(ctx context.Context, childProfileID string, fallbackStatus string) (bool, error)
 }
 
 func resolveFallbackEntitlement(fallbackStatus string) bool {
@@ -25,9 +29,9 @@ func NewBillingEntitlementVerifierFromEnv() EntitlementVerifier {
 	return verifier
 }
 
-func resolveEntitlementAllowed(verifier EntitlementVerifier, req contractsapi.CreatePlaybackSessionRequest) (bool, error) {
+func resolveEntitlementAllowed(ctx context.Context, verifier EntitlementVerifier, req contractsapi.CreatePlaybackSessionRequest) (bool, error) {
 	if verifier == nil {
 		verifier = newDefaultEntitlementVerifier()
 	}
-	return verifier.IsEntitled(req.ChildProfileID, req.EntitlementStatus)
+	return verifier.IsEntitled(ctx, req.ChildProfileID, req.EntitlementStatus)
 }
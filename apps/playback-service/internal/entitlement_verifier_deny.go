@@ -1,12 +1,15 @@
 package internal
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 type denyEntitlementVerifier struct {
 	reason error
 }
 
-func (v denyEntitlementVerifier) IsEntitled(_ string, _ string) (bool, error) {
+func (v denyEntitlementVerifier) IsEntitled(_ context.Context, _ string, _ string) (bool, error) {
 	if v.reason != nil {
 		return false, fmt.Errorf("entitlement verifier unavailable: %w", v.reason)
 	}
@@ -1,7 +1,9 @@
 package internal
 
+import "context"
+
 type fallbackEntitlementVerifier struct{}
 
-func (v *fallbackEntitlementVerifier) IsEntitled(_ string, fallbackStatus string) (bool, error) {
+func (v *fallbackEntitlementVerifier) IsEntitled(_ context.Context, _ string, fallbackStatus string) (bool, error) {
 	return resolveFallbackEntitlement(fallbackStatus), nil
 }
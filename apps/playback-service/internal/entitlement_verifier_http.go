@@ -1,12 +1,15 @@
 package internal
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"os"
 	"time"
+
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 )
 
 type httpBillingEntitlementVerifier struct {
@@ -28,16 +31,16 @@ func newHTTPBillingEntitlementVerifierFromEnv() (*httpBillingEntitlementVerifier
 		return nil, fmt.Errorf("parse BILLING_URL: %w", err)
 	}
 	return &httpBillingEntitlementVerifier{
-		client:  &http.Client{Timeout: 2 * time.Second},
+		client:  &http.Client{Timeout: 2 * time.Second, Transport: observability.NewTransport(nil)},
 		baseURL: parsed,
 	}, nil
 }
 
-func (v *httpBillingEntitlementVerifier) IsEntitled(childProfileID string, fallbackStatus string) (bool, error) {
+func (v *httpBillingEntitlementVerifier) IsEntitled(ctx context.Context, childProfileID string, fallbackStatus string) (bool, error) {
 	if childProfileID == "" {
 		return false, fmt.Errorf("child_profile_id is required for entitlement lookup")
 	}
-	entitled, err := v.lookupChildEntitlement(childProfileID)
+	entitled, err := v.lookupChildEntitlement(ctx, childProfileID)
 	if err != nil {
 		return false, err
 	}
@@ -45,14 +48,18 @@ func (v *httpBillingEntitlementVerifier) IsEntitled(childProfileID string, fallb
 	return entitled, nil
 }
 
-func (v *httpBillingEntitlementVerifier) lookupChildEntitlement(childProfileID string) (bool, error) {
+func (v *httpBillingEntitlementVerifier) lookupChildEntitlement(ctx context.Context, childProfileID string) (bool, error) {
 	requestURL := *v.baseURL
 	requestURL.Path = "/v1/billing/entitlements"
 	query := requestURL.Query()
 	query.Set("child_profile_id", childProfileID)
 	requestURL.RawQuery = query.Encode()
 
-	resp, err := v.client.Get(requestURL.String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		return false, fmt.Errorf("build billing entitlement request: %w", err)
+	}
+	resp, err := v.client.Do(req)
 	if err != nil {
 		return false, fmt.Errorf("billing entitlement request: %w", err)
 	}
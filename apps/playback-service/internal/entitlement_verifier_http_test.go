@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -24,7 +25,7 @@ func TestHTTPBillingEntitlementVerifier(t *testing.T) {
 	if err != nil {
 		t.Fatalf("new verifier: %v", err)
 	}
-	entitled, err := verifier.IsEntitled("child-1", "inactive")
+	entitled, err := verifier.IsEntitled(context.Background(), "child-1", "inactive")
 	if err != nil {
 		t.Fatalf("is entitled: %v", err)
 	}
@@ -9,6 +9,8 @@ import (
 	"net/url"
 	"os"
 	"time"
+
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 )
 
 type httpGateVerifier struct {
@@ -43,7 +45,7 @@ func newHTTPGateVerifierFromEnv() (*httpGateVerifier, error) {
 		return nil, fmt.Errorf("parse IDENTITY_URL: %w", err)
 	}
 	return &httpGateVerifier{
-		client:  &http.Client{Timeout: 2 * time.Second},
+		client:  &http.Client{Timeout: 2 * time.Second, Transport: observability.NewTransport(nil)},
 		baseURL: parsed,
 	}, nil
 }
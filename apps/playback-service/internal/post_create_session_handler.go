@@ -8,11 +8,10 @@ import (
 	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
 	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
 	"github.com/delqhi/mikasmissions/platform/libs/httpx"
-	"github.com/delqhi/mikasmissions/platform/libs/queue"
 	"github.com/google/uuid"
 )
 
-func PostCreateSession(service *Service, bus queue.Bus) http.HandlerFunc {
+func PostCreateSession(service *Service, publisher EventPublisher) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req contractsapi.CreatePlaybackSessionRequest
 		if err := httpx.DecodeJSON(r, &req); err != nil {
@@ -58,7 +57,7 @@ func PostCreateSession(service *Service, bus queue.Bus) http.HandlerFunc {
 			httpx.WriteAPIError(w, http.StatusInternalServerError, "playback_error", err.Error())
 			return
 		}
-		if err := publishEvent(r.Context(), bus, "playback.session.started.v1", uuid.NewString(), contractsevents.PlaybackSessionStartedV1{
+		if err := publishEvent(r.Context(), publisher, "playback.session.started.v1", uuid.NewString(), contractsevents.PlaybackSessionStartedV1{
 			PlaybackSessionID: result.PlaybackSessionID,
 			ChildProfileID:    req.ChildProfileID,
 			EpisodeID:         req.EpisodeID,
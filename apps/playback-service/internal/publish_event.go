@@ -2,22 +2,44 @@ package internal
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
+	"io"
+	"os"
 
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
 )
 
-func publishEvent(ctx context.Context, bus queue.Bus, topic, eventID string, payload any) error {
-	if bus == nil {
+type EventPublisher interface {
+	Publish(ctx context.Context, event queue.Event) error
+}
+
+type outboxPublisher struct {
+	outbox *queue.PersistentOutbox
+}
+
+func (p *outboxPublisher) Publish(ctx context.Context, event queue.Event) error {
+	return p.outbox.Add(ctx, event)
+}
+
+func NewEventPublisherFromEnv(bus queue.Bus) (EventPublisher, io.Closer, error) {
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		return bus, nil, nil
+	}
+	outbox, err := queue.NewPersistentOutbox(databaseURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open playback outbox: %w", err)
+	}
+	return &outboxPublisher{outbox: outbox}, outbox, nil
+}
+
+func publishEvent(ctx context.Context, publisher EventPublisher, topic, eventID string, payload any) error {
+	if publisher == nil {
 		return nil
 	}
-	body, err := json.Marshal(payload)
+	event, err := queue.NewJSONEvent(ctx, topic, eventID, payload)
 	if err != nil {
 		return err
 	}
-	return bus.Publish(ctx, queue.Event{
-		ID:      eventID,
-		Topic:   topic,
-		Payload: body,
-	})
+	return publisher.Publish(ctx, event)
 }
@@ -4,20 +4,25 @@ import (
 	"net/http"
 
 	"github.com/delqhi/mikasmissions/platform/libs/authz"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
 )
 
-func NewMux(service *Service) *http.ServeMux {
+func NewMux(service *Service) http.Handler {
 	return NewMuxWithBus(service, nil)
 }
 
-func NewMuxWithBus(service *Service, bus queue.Bus) *http.ServeMux {
+func NewMuxWithBus(service *Service, bus queue.Bus) http.Handler {
+	return NewMuxWithPublisher(service, bus)
+}
+
+func NewMuxWithPublisher(service *Service, publisher EventPublisher) http.Handler {
 	mux := http.NewServeMux()
 	authorizer := authz.NewHTTPAuthorizerFromEnv()
-	mux.HandleFunc("POST /v1/playback/sessions", authorizer.Wrap([]string{"parent", "child", "service"}, PostCreateSession(service, bus)))
+	mux.HandleFunc("POST /v1/playback/sessions", authorizer.Wrap([]string{"parent", "child", "service"}, PostCreateSession(service, publisher)))
 	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
-	return mux
+	return observability.Instrument("playback-service", mux)
 }
@@ -7,9 +7,17 @@ import (
 	"os"
 
 	"github.com/delqhi/mikasmissions/platform/apps/profile-service/internal"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
+	"github.com/delqhi/mikasmissions/platform/libs/tracing"
 )
 
 func main() {
+	tracer, err := tracing.InitFromEnv("profile-service", os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer tracer.Close()
+
 	store, err := internal.OpenRepositoryFromEnv()
 	if err != nil {
 		log.Fatal(err)
@@ -25,6 +33,7 @@ func main() {
 		addr = ":" + fromEnv
 	}
 	log.Printf("profile-service listening on %s", addr)
+	observability.ServeMetrics("profile-service", mux, os.Getenv)
 	if err := http.ListenAndServe(addr, mux); err != nil {
 		log.Fatal(err)
 	}
@@ -4,9 +4,10 @@ import (
 	"net/http"
 
 	"github.com/delqhi/mikasmissions/platform/libs/authz"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 )
 
-func NewMux(store Repository) *http.ServeMux {
+func NewMux(store Repository) http.Handler {
 	mux := http.NewServeMux()
 	authorizer := authz.NewHTTPAuthorizerFromEnv()
 	mux.HandleFunc("POST /v1/children/profiles", authorizer.Wrap([]string{"parent", "service"}, PostCreateProfile(store)))
@@ -16,5 +17,5 @@ func NewMux(store Repository) *http.ServeMux {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
-	return mux
+	return observability.Instrument("profile-service", mux)
 }
@@ -7,11 +7,19 @@ import (
 	"os"
 
 	"github.com/delqhi/mikasmissions/platform/apps/progress-service/internal"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 	"github.com/delqhi/mikasmissions/platform/libs/profileclient"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/tracing"
 )
 
 func main() {
+	tracer, err := tracing.InitFromEnv("progress-service", os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer tracer.Close()
+
 	repository, err := internal.OpenRepositoryFromEnv()
 	if err != nil {
 		log.Fatal(err)
@@ -29,7 +37,7 @@ func main() {
 		log.Fatal(err)
 	}
 	service := internal.NewServiceWithRepositoryAndOwnerVerifier(repository, profileReader)
-	bus, err := queue.NewBusFromEnv()
+	bus, err := queue.NewBusFromEnv(queue.WithProducer("progress-service"))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -40,6 +48,7 @@ func main() {
 		addr = ":" + fromEnv
 	}
 	log.Printf("progress-service listening on %s", addr)
+	observability.ServeMetrics("progress-service", mux, os.Getenv)
 	if err := http.ListenAndServe(addr, mux); err != nil {
 		log.Fatal(err)
 	}
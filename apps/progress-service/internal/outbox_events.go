@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"context"
+
+	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+)
+
+type eventFlusher interface {
+	FlushEvents(ctx context.Context, bus queue.Bus) error
+}
+
+func (s *Service) FlushEvents(ctx context.Context, bus queue.Bus) error {
+	flusher, ok := s.repository.(eventFlusher)
+	if !ok || bus == nil {
+		return nil
+	}
+	return flusher.FlushEvents(ctx, bus)
+}
+
+func (s *Store) FlushEvents(ctx context.Context, bus queue.Bus) error {
+	return s.events.Flush(ctx, bus)
+}
+
+func buildWatchEvents(progress contractsapi.KidsProgressResponse, builders []watchEvent) ([]queue.Event, error) {
+	events := make([]queue.Event, 0, len(builders))
+	for _, build := range builders {
+		event, err := build(progress)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
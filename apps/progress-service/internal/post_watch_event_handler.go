@@ -23,20 +23,24 @@ func PostWatchEvent(service *Service, bus queue.Bus) http.HandlerFunc {
 			httpx.WriteJSON(w, http.StatusBadRequest, apiErr)
 			return
 		}
-		result, err := service.UpsertProgress(r.Context(), req)
-		if err != nil {
-			if errors.Is(err, ErrChildProfileForbidden) {
-				httpx.WriteAPIError(w, http.StatusForbidden, "child_profile_forbidden", err.Error())
-				return
-			}
-			httpx.WriteAPIError(w, http.StatusInternalServerError, "progress_error", err.Error())
-			return
-		}
 		endedAt := req.EventTimeISO
 		if _, err := time.Parse(time.RFC3339, endedAt); err != nil {
 			endedAt = time.Now().UTC().Format(time.RFC3339)
 		}
-		progress, err := service.GetKidsProgress(r.Context(), req.ChildProfileID)
+		result, err := service.UpsertProgress(r.Context(), req, func(progress contractsapi.KidsProgressResponse) (queue.Event, error) {
+			event, err := queue.NewJSONEvent(r.Context(), "playback.session.ended.v1", uuid.NewString(), contractsevents.PlaybackSessionEndedV1{
+				PlaybackSessionID: req.ChildProfileID + ":" + req.EpisodeID,
+				ChildProfileID:    req.ChildProfileID,
+				EpisodeID:         req.EpisodeID,
+				EndedAt:           endedAt,
+				WatchedMS:         req.WatchMS,
+				Capped:            progress.SessionCapped,
+			})
+			if err != nil {
+				return queue.Event{}, err
+			}
+			return event.WithOrderingKey(req.ChildProfileID), nil
+		})
 		if err != nil {
 			if errors.Is(err, ErrChildProfileForbidden) {
 				httpx.WriteAPIError(w, http.StatusForbidden, "child_profile_forbidden", err.Error())
@@ -45,14 +49,7 @@ func PostWatchEvent(service *Service, bus queue.Bus) http.HandlerFunc {
 			httpx.WriteAPIError(w, http.StatusInternalServerError, "progress_error", err.Error())
 			return
 		}
-		if err := publishEvent(r.Context(), bus, "playback.session.ended.v1", uuid.NewString(), contractsevents.PlaybackSessionEndedV1{
-			PlaybackSessionID: req.ChildProfileID + ":" + req.EpisodeID,
-			ChildProfileID:    req.ChildProfileID,
-			EpisodeID:         req.EpisodeID,
-			EndedAt:           endedAt,
-			WatchedMS:         req.WatchMS,
-			Capped:            progress.SessionCapped,
-		}); err != nil {
+		if err := service.FlushEvents(r.Context(), bus); err != nil {
 			httpx.WriteAPIError(w, http.StatusInternalServerError, "event_publish_failed", err.Error())
 			return
 		}
@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
+)
+
+func TestPostWatchEventPublishesSessionEndedWithCapFromStoredProgress(t *testing.T) {
+	bus := queue.NewInMemoryBus()
+	defer bus.Close()
+	var ended contractsevents.PlaybackSessionEndedV1
+	_ = bus.Subscribe(context.Background(), "playback.session.ended.v1", "test", func(_ context.Context, event queue.Event) error {
+		return json.Unmarshal(event.Payload, &ended)
+	})
+	mux := NewMuxWithBus(NewService(), bus)
+	eventTime := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+	req := httptest.NewRequest(http.MethodPost, "/v1/progress/watch-events", strings.NewReader(`{"child_profile_id":"child-1","episode_id":"ep-1","watch_ms":3600000,"event_time":"`+eventTime+`"}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rr.Code, rr.Body.String())
+	}
+	testkit.WaitBusIdle(t, bus)
+	if ended.ChildProfileID != "child-1" || ended.WatchedMS != 3600000 || !ended.Capped {
+		t.Fatalf("unexpected session ended event: %+v", ended)
+	}
+}
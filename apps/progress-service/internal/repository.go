@@ -5,9 +5,18 @@ import (
 	"time"
 
 	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
 )
 
+type watchEvent func(contractsapi.KidsProgressResponse) (queue.Event, error)
+
 type Repository interface {
-	AppendWatchEvent(ctx context.Context, req contractsapi.UpsertWatchEventRequest, eventTime time.Time) error
+	AppendWatchEvent(
+		ctx context.Context,
+		req contractsapi.UpsertWatchEventRequest,
+		eventTime time.Time,
+		defaultLimitMin int,
+		events ...watchEvent,
+	) error
 	GetKidsProgress(ctx context.Context, childProfileID string, now time.Time, defaultLimitMin int) (contractsapi.KidsProgressResponse, error)
 }
@@ -4,14 +4,15 @@ import (
 	"net/http"
 
 	"github.com/delqhi/mikasmissions/platform/libs/authz"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
 )
 
-func NewMux(service *Service) *http.ServeMux {
+func NewMux(service *Service) http.Handler {
 	return NewMuxWithBus(service, nil)
 }
 
-func NewMuxWithBus(service *Service, bus queue.Bus) *http.ServeMux {
+func NewMuxWithBus(service *Service, bus queue.Bus) http.Handler {
 	mux := http.NewServeMux()
 	authorizer := authz.NewHTTPAuthorizerFromEnv()
 	mux.HandleFunc("POST /v1/progress/watch-events", authorizer.Wrap([]string{"parent", "child", "service"}, PostWatchEvent(service, bus)))
@@ -20,5 +21,5 @@ func NewMuxWithBus(service *Service, bus queue.Bus) *http.ServeMux {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
-	return mux
+	return observability.Instrument("progress-service", mux)
 }
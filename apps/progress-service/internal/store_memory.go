@@ -6,6 +6,7 @@ import (
 	"time"
 
 	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
 )
 
 type watchRecord struct {
@@ -17,35 +18,53 @@ type watchRecord struct {
 type Store struct {
 	mu             sync.Mutex
 	recordsByChild map[string][]watchRecord
+	events         *queue.Outbox
 }
 
 func NewStore() *Store {
 	return &Store{
 		recordsByChild: map[string][]watchRecord{},
+		events:         queue.NewOutbox(),
 	}
 }
 
-func (s *Store) AppendWatchEvent(_ context.Context, req contractsapi.UpsertWatchEventRequest, eventTime time.Time) error {
+func (s *Store) AppendWatchEvent(
+	_ context.Context,
+	req contractsapi.UpsertWatchEventRequest,
+	eventTime time.Time,
+	defaultLimitMin int,
+	events ...watchEvent,
+) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.recordsByChild[req.ChildProfileID] = append(s.recordsByChild[req.ChildProfileID], watchRecord{
+	records := append(s.recordsByChild[req.ChildProfileID], watchRecord{
 		episodeID: req.EpisodeID,
 		watchMS:   req.WatchMS,
 		eventTime: eventTime,
 	})
+	built, err := buildWatchEvents(memoryKidsProgress(req.ChildProfileID, records, time.Now().UTC(), defaultLimitMin), events)
+	if err != nil {
+		return err
+	}
+	s.recordsByChild[req.ChildProfileID] = records
+	s.events.Add(built...)
 	return nil
 }
 
 func (s *Store) GetKidsProgress(_ context.Context, childProfileID string, now time.Time, defaultLimitMin int) (contractsapi.KidsProgressResponse, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return memoryKidsProgress(childProfileID, s.recordsByChild[childProfileID], now, defaultLimitMin), nil
+}
+
+func memoryKidsProgress(childProfileID string, records []watchRecord, now time.Time, defaultLimitMin int) contractsapi.KidsProgressResponse {
 	dayCutoff := now.Add(-24 * time.Hour)
 	weekCutoff := now.Add(-7 * 24 * time.Hour)
 	var dayMS int64
 	var weekMS int64
 	lastEpisode := ""
 	var latestTime time.Time
-	for _, rec := range s.recordsByChild[childProfileID] {
+	for _, rec := range records {
 		if rec.eventTime.After(weekCutoff) {
 			weekMS += rec.watchMS
 		}
@@ -79,5 +98,5 @@ func (s *Store) GetKidsProgress(_ context.Context, childProfileID string, now ti
 		SessionMinutesUsed:  usedTodayMin,
 		SessionCapped:       usedTodayMin >= defaultLimitMin,
 		LastEpisodeID:       lastEpisode,
-	}, nil
+	}
 }
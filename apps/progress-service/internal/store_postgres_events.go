@@ -7,18 +7,49 @@ import (
 	"time"
 
 	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
 )
 
-func (s *PostgresStore) AppendWatchEvent(ctx context.Context, req contractsapi.UpsertWatchEventRequest, eventTime time.Time) error {
-	_, err := s.db.ExecContext(
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (s *PostgresStore) AppendWatchEvent(
+	ctx context.Context,
+	req contractsapi.UpsertWatchEventRequest,
+	eventTime time.Time,
+	defaultLimitMin int,
+	events ...watchEvent,
+) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin watch event tx: %w", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(
 		ctx,
 		`insert into progress.watch_events (child_profile_id, episode_id, watch_ms, event_time)
 		 values ($1, $2, $3, $4)`,
 		req.ChildProfileID, req.EpisodeID, req.WatchMS, eventTime,
-	)
-	if err != nil {
+	); err != nil {
 		return fmt.Errorf("insert watch event: %w", err)
 	}
+	if len(events) > 0 {
+		progress, err := queryKidsProgress(ctx, tx, req.ChildProfileID, time.Now().UTC(), defaultLimitMin)
+		if err != nil {
+			return err
+		}
+		built, err := buildWatchEvents(progress, events)
+		if err != nil {
+			return err
+		}
+		if err := queue.EnqueueOutboxTx(ctx, tx, built...); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit watch event tx: %w", err)
+	}
 	return nil
 }
 
@@ -27,19 +58,29 @@ func (s *PostgresStore) GetKidsProgress(
 	childProfileID string,
 	now time.Time,
 	defaultLimitMin int,
+) (contractsapi.KidsProgressResponse, error) {
+	return queryKidsProgress(ctx, s.db, childProfileID, now, defaultLimitMin)
+}
+
+func queryKidsProgress(
+	ctx context.Context,
+	q rowQuerier,
+	childProfileID string,
+	now time.Time,
+	defaultLimitMin int,
 ) (contractsapi.KidsProgressResponse, error) {
 	dayCutoff := now.Add(-24 * time.Hour)
 	weekCutoff := now.Add(-7 * 24 * time.Hour)
 
-	dayMS, weekMS, err := s.loadWindowSums(ctx, childProfileID, dayCutoff, weekCutoff)
+	dayMS, weekMS, err := loadWindowSums(ctx, q, childProfileID, dayCutoff, weekCutoff)
 	if err != nil {
 		return contractsapi.KidsProgressResponse{}, err
 	}
-	lastEpisodeID, err := s.loadLastEpisodeID(ctx, childProfileID)
+	lastEpisodeID, err := loadLastEpisodeID(ctx, q, childProfileID)
 	if err != nil {
 		return contractsapi.KidsProgressResponse{}, err
 	}
-	sessionLimitMin, err := s.loadSessionLimit(ctx, childProfileID, defaultLimitMin)
+	sessionLimitMin, err := loadSessionLimit(ctx, q, childProfileID, defaultLimitMin)
 	if err != nil {
 		return contractsapi.KidsProgressResponse{}, err
 	}
@@ -70,15 +111,16 @@ func (s *PostgresStore) GetKidsProgress(
 	}, nil
 }
 
-func (s *PostgresStore) loadWindowSums(
+func loadWindowSums(
 	ctx context.Context,
+	q rowQuerier,
 	childProfileID string,
 	dayCutoff time.Time,
 	weekCutoff time.Time,
 ) (int64, int64, error) {
 	var dayMS int64
 	var weekMS int64
-	err := s.db.QueryRowContext(
+	err := q.QueryRowContext(
 		ctx,
 		`select
 		   coalesce(sum(case when event_time > $2 then watch_ms else 0 end), 0),
@@ -93,9 +135,9 @@ func (s *PostgresStore) loadWindowSums(
 	return dayMS, weekMS, nil
 }
 
-func (s *PostgresStore) loadLastEpisodeID(ctx context.Context, childProfileID string) (string, error) {
+func loadLastEpisodeID(ctx context.Context, q rowQuerier, childProfileID string) (string, error) {
 	var lastEpisodeID string
-	err := s.db.QueryRowContext(
+	err := q.QueryRowContext(
 		ctx,
 		`select episode_id
 		 from progress.watch_events
@@ -113,9 +155,9 @@ func (s *PostgresStore) loadLastEpisodeID(ctx context.Context, childProfileID st
 	return lastEpisodeID, nil
 }
 
-func (s *PostgresStore) loadSessionLimit(ctx context.Context, childProfileID string, defaultLimitMin int) (int, error) {
+func loadSessionLimit(ctx context.Context, q rowQuerier, childProfileID string, defaultLimitMin int) (int, error) {
 	var sessionLimit int
-	err := s.db.QueryRowContext(
+	err := q.QueryRowContext(
 		ctx,
 		`select session_limit_minutes
 		 from identity.parent_controls
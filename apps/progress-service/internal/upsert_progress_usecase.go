@@ -38,7 +38,11 @@ func NewServiceWithRepositoryAndOwnerVerifier(repository Repository, ownerVerifi
 	}
 }
 
-func (s *Service) UpsertProgress(ctx context.Context, req contractsapi.UpsertWatchEventRequest) (contractsapi.UpsertWatchEventResponse, error) {
+func (s *Service) UpsertProgress(
+	ctx context.Context,
+	req contractsapi.UpsertWatchEventRequest,
+	events ...watchEvent,
+) (contractsapi.UpsertWatchEventResponse, error) {
 	if err := s.ensureParentOwnership(ctx, req.ChildProfileID); err != nil {
 		return contractsapi.UpsertWatchEventResponse{}, err
 	}
@@ -46,7 +50,7 @@ func (s *Service) UpsertProgress(ctx context.Context, req contractsapi.UpsertWat
 	if parsed, err := time.Parse(time.RFC3339, req.EventTimeISO); err == nil {
 		eventTime = parsed.UTC()
 	}
-	if err := s.repository.AppendWatchEvent(ctx, req, eventTime); err != nil {
+	if err := s.repository.AppendWatchEvent(ctx, req, eventTime, s.defaultLimitMin, events...); err != nil {
 		return contractsapi.UpsertWatchEventResponse{}, err
 	}
 	return contractsapi.UpsertWatchEventResponse{Accepted: true}, nil
@@ -6,9 +6,17 @@ import (
 	"os"
 
 	"github.com/delqhi/mikasmissions/platform/apps/recommendation-service/internal"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
+	"github.com/delqhi/mikasmissions/platform/libs/tracing"
 )
 
 func main() {
+	tracer, err := tracing.InitFromEnv("recommendation-service", os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer tracer.Close()
+
 	service := internal.NewService()
 	mux := internal.NewMux(service)
 	addr := ":8084"
@@ -16,6 +24,7 @@ func main() {
 		addr = ":" + fromEnv
 	}
 	log.Printf("recommendation-service listening on %s", addr)
+	observability.ServeMetrics("recommendation-service", mux, os.Getenv)
 	if err := http.ListenAndServe(addr, mux); err != nil {
 		log.Fatal(err)
 	}
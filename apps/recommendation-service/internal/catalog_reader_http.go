@@ -11,6 +11,7 @@ import (
 	"time"
 
 	contractsapi "github.com/delqhi/mikasmissions/platform/libs/contracts-api"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 )
 
 type httpCatalogReader struct {
@@ -45,7 +46,7 @@ func newHTTPCatalogReaderFromEnv() (*httpCatalogReader, error) {
 		return nil, fmt.Errorf("parse CATALOG_URL: %w", err)
 	}
 	return &httpCatalogReader{
-		client:   &http.Client{Timeout: 2 * time.Second},
+		client:   &http.Client{Timeout: 2 * time.Second, Transport: observability.NewTransport(nil)},
 		baseURL:  parsed,
 		fallback: newDemoCatalogReader(),
 		strict:   strictRuntimeMode(),
@@ -4,9 +4,10 @@ import (
 	"net/http"
 
 	"github.com/delqhi/mikasmissions/platform/libs/authz"
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 )
 
-func NewMux(service *Service) *http.ServeMux {
+func NewMux(service *Service) http.Handler {
 	mux := http.NewServeMux()
 	authorizer := authz.NewHTTPAuthorizerFromEnv()
 	mux.HandleFunc("GET /v1/home/rails", authorizer.Wrap([]string{"parent", "child", "service"}, GetHomeRails(service)))
@@ -15,5 +16,5 @@ func NewMux(service *Service) *http.ServeMux {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
-	return mux
+	return observability.Instrument("recommendation-service", mux)
 }
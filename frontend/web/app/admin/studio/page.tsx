@@ -24,12 +24,19 @@ type WorkflowsResponse = {
 
 type ModelProfile = {
   model_profile_id: string;
-  provider: "nvidia_nim";
+  provider: "nvidia_nim" | "http_predictions" | "local_fake";
   base_url: string;
   model_id: string;
   timeout_ms: number;
   max_retries: number;
   safety_preset: string;
+  capabilities?: {
+    max_duration_ms: number;
+    resolutions: string[];
+    input_fields: string[];
+    steps: string[];
+    callbacks: boolean;
+  };
 };
 
 type RunResponse = {
@@ -83,7 +90,7 @@ async function updateModelProfile(formData: FormData) {
   const profileID = String(formData.get("model_profile_id") ?? "nim-default");
   const body = {
     model_profile_id: profileID,
-    provider: "nvidia_nim",
+    provider: String(formData.get("provider") ?? "nvidia_nim"),
     base_url: String(formData.get("base_url") ?? "http://127.0.0.1:9000"),
     model_id: String(formData.get("model_id") ?? "nim-video-v1"),
     timeout_ms: Number(formData.get("timeout_ms") ?? 12000),
@@ -180,6 +187,12 @@ export default async function AdminStudioPage() {
           <p>
             Provider {modelProfile.provider} · timeout {modelProfile.timeout_ms}ms · retries {modelProfile.max_retries}
           </p>
+          {modelProfile.capabilities ? (
+            <p>
+              Steps {modelProfile.capabilities.steps.join(", ")} · max {modelProfile.capabilities.max_duration_ms / 1000}s ·{" "}
+              {modelProfile.capabilities.resolutions.join(", ")}
+            </p>
+          ) : null}
         </article>
       </section>
 
@@ -192,6 +205,15 @@ export default async function AdminStudioPage() {
               <input defaultValue={modelProfile.model_profile_id} name="model_profile_id" readOnly />
             </label>
 
+            <label>
+              <span>{messages.admin.studio.labelProvider}</span>
+              <select defaultValue={modelProfile.provider} name="provider">
+                <option value="nvidia_nim">nvidia_nim</option>
+                <option value="http_predictions">http_predictions</option>
+                <option value="local_fake">local_fake</option>
+              </select>
+            </label>
+
             <label>
               <span>{messages.admin.studio.labelBaseUrl}</span>
               <input defaultValue={modelProfile.base_url} name="base_url" placeholder="NIM base url" required />
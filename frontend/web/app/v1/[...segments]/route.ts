@@ -40,7 +40,7 @@ type AdminWorkflow = {
 
 type ModelProfile = {
   model_profile_id: string;
-  provider: "nvidia_nim";
+  provider: "nvidia_nim" | "http_predictions" | "local_fake";
   base_url: string;
   model_id: string;
   timeout_ms: number;
@@ -401,7 +401,7 @@ async function handleAdminModelProfilePUT(request: Request, profileID: string):
   const body = await bodyJSON<ModelProfile>(request);
   const profile: ModelProfile = {
     model_profile_id: profileID,
-    provider: "nvidia_nim",
+    provider: body.provider === "http_predictions" || body.provider === "local_fake" ? body.provider : "nvidia_nim",
     base_url: typeof body.base_url === "string" ? body.base_url : "http://127.0.0.1:9000",
     model_id: typeof body.model_id === "string" ? body.model_id : "nim-video-v1",
     timeout_ms: typeof body.timeout_ms === "number" ? body.timeout_ms : 12000,
@@ -388,6 +388,22 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
+    "/v1/admin/runs/{run_id}/generation-callback": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        post: operations["postGenerationCallback"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
     "/v1/admin/model-profiles/{id}": {
         parameters: {
             query?: never;
@@ -404,6 +420,54 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
+    "/v1/admin/dead-letters": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["listAdminDeadLetters"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/v1/admin/dead-letters/{dead_letter_id}": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["getAdminDeadLetter"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/v1/admin/dead-letters/{dead_letter_id}/redrive": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        post: operations["redriveAdminDeadLetter"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
 }
 export type webhooks = Record<string, never>;
 export interface components {
@@ -683,13 +747,60 @@ export interface components {
         AdminModelProfile: {
             model_profile_id: string;
             /** @enum {string} */
-            provider: "nvidia_nim";
+            provider: "nvidia_nim" | "http_predictions" | "local_fake";
             /** Format: uri */
             base_url: string;
             model_id: string;
             timeout_ms: number;
             max_retries: number;
             safety_preset: string;
+            readonly capabilities?: components["schemas"]["AdminProviderCapabilities"];
+        };
+        AdminProviderCapabilities: {
+            /** Format: int64 */
+            max_duration_ms: number;
+            resolutions: string[];
+            input_fields: string[];
+            steps: string[];
+            callbacks: boolean;
+        };
+        AdminDeadLetter: {
+            dead_letter_id: string;
+            event_id: string;
+            original_topic: string;
+            consumer: string;
+            error: string;
+            attempts: number;
+            failed_at: string;
+            /** @enum {string} */
+            status: "open" | "redriven";
+            redrive_event_id?: string;
+            redriven_by?: string;
+            redriven_at?: string;
+            payload?: Record<string, never>;
+            headers?: {
+                [key: string]: string;
+            };
+        };
+        AdminDeadLetterListResponse: {
+            dead_letters: components["schemas"]["AdminDeadLetter"][];
+        };
+        AdminDeadLetterRedriveResponse: {
+            dead_letter_id: string;
+            status: string;
+            redrive_event_id: string;
+        };
+        AdminGenerationCallbackRequest: {
+            job_id: string;
+            /** @enum {string} */
+            status: "queued" | "running" | "succeeded" | "failed";
+            progress?: number;
+            error?: string;
+        };
+        AdminGenerationCallbackResponse: {
+            run_id: string;
+            job_id: string;
+            status: string;
         };
     };
     responses: {
@@ -709,6 +820,7 @@ export interface components {
         WorkflowIDPath: string;
         RunIDPath: string;
         ModelProfileIDPath: string;
+        DeadLetterIDPath: string;
     };
     requestBodies: never;
     headers: never;
@@ -1408,6 +1520,39 @@ export interface operations {
             404: components["responses"]["APIError"];
         };
     };
+    postGenerationCallback: {
+        parameters: {
+            query?: {
+                token?: string;
+            };
+            header?: {
+                "X-Callback-Token"?: string;
+            };
+            path: {
+                run_id: components["parameters"]["RunIDPath"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["AdminGenerationCallbackRequest"];
+            };
+        };
+        responses: {
+            /** @description Generation job update accepted. */
+            202: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["AdminGenerationCallbackResponse"];
+                };
+            };
+            400: components["responses"]["APIError"];
+            401: components["responses"]["APIError"];
+            404: components["responses"]["APIError"];
+        };
+    };
     getAdminModelProfile: {
         parameters: {
             query?: never;
@@ -1459,4 +1604,76 @@ export interface operations {
             400: components["responses"]["APIError"];
         };
     };
+    listAdminDeadLetters: {
+        parameters: {
+            query?: {
+                status?: "open" | "redriven";
+                topic?: string;
+                limit?: number;
+            };
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Dead-lettered events, newest first. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["AdminDeadLetterListResponse"];
+                };
+            };
+            400: components["responses"]["APIError"];
+        };
+    };
+    getAdminDeadLetter: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                dead_letter_id: components["parameters"]["DeadLetterIDPath"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Dead-lettered event with its original payload and headers. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["AdminDeadLetter"];
+                };
+            };
+            404: components["responses"]["APIError"];
+        };
+    };
+    redriveAdminDeadLetter: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                dead_letter_id: components["parameters"]["DeadLetterIDPath"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Original payload republished under a new event ID. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["AdminDeadLetterRedriveResponse"];
+                };
+            };
+            404: components["responses"]["APIError"];
+            409: components["responses"]["APIError"];
+        };
+    };
 }
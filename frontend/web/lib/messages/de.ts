@@ -275,6 +275,7 @@ export const deMessages: LocalizedMessages = {
       labelProfileId: "Profil ID",
       labelBaseUrl: "Base URL",
       labelModelId: "Model ID",
+      labelProvider: "Anbieter",
       labelTimeout: "Timeout (ms)",
       labelMaxRetries: "Max Retries",
       labelSafetyPreset: "Safety Preset",
@@ -275,6 +275,7 @@ export const enMessages: LocalizedMessages = {
       labelProfileId: "Profile ID",
       labelBaseUrl: "Base URL",
       labelModelId: "Model ID",
+      labelProvider: "Provider",
       labelTimeout: "Timeout (ms)",
       labelMaxRetries: "Max retries",
       labelSafetyPreset: "Safety preset",
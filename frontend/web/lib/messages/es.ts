@@ -275,6 +275,7 @@ export const esMessages: LocalizedMessages = {
       labelProfileId: "ID de perfil",
       labelBaseUrl: "URL base",
       labelModelId: "ID de modelo",
+      labelProvider: "Proveedor",
       labelTimeout: "Timeout (ms)",
       labelMaxRetries: "Máx. reintentos",
       labelSafetyPreset: "Preset de seguridad",
@@ -273,6 +273,7 @@ export type LocalizedMessages = {
       labelProfileId: string;
       labelBaseUrl: string;
       labelModelId: string;
+      labelProvider: string;
       labelTimeout: string;
       labelMaxRetries: string;
       labelSafetyPreset: string;
@@ -0,0 +1,2 @@
+alter table events.outbox
+  add column if not exists headers jsonb not null default '{}'::jsonb;
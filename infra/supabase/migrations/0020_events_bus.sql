@@ -0,0 +1,34 @@
+create table if not exists events.bus_messages (
+  id bigserial primary key,
+  event_id text not null unique,
+  topic text not null,
+  payload bytea not null,
+  headers jsonb not null default '{}'::jsonb,
+  tx_id xid8 not null default pg_current_xact_id(),
+  created_at timestamptz not null default now()
+);
+
+create index if not exists idx_events_bus_messages_topic_tx
+on events.bus_messages (topic, tx_id, id);
+
+create table if not exists events.bus_consumers (
+  consumer text not null,
+  topic text not null,
+  last_tx_id xid8 not null,
+  last_message_id bigint not null default 0,
+  updated_at timestamptz not null default now(),
+  primary key (consumer, topic)
+);
+
+create table if not exists events.bus_redeliveries (
+  consumer text not null,
+  topic text not null,
+  message_id bigint not null references events.bus_messages(id) on delete cascade,
+  attempts integer not null,
+  available_at timestamptz not null,
+  last_error text,
+  primary key (consumer, topic, message_id)
+);
+
+create index if not exists idx_events_bus_redeliveries_due
+on events.bus_redeliveries (consumer, topic, available_at);
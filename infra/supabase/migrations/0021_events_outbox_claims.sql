@@ -0,0 +1,8 @@
+alter table events.outbox
+  add column if not exists partition_key text,
+  add column if not exists locked_by text,
+  add column if not exists locked_until timestamptz;
+
+create index if not exists idx_events_outbox_partition_pending
+on events.outbox (partition_key, id)
+where status = 'pending' and partition_key is not null;
@@ -0,0 +1,19 @@
+create table if not exists events.outbox_archive (
+  id bigint primary key,
+  event_id text not null,
+  topic text not null,
+  payload jsonb not null,
+  headers jsonb not null default '{}'::jsonb,
+  attempts integer not null default 0,
+  partition_key text,
+  created_at timestamptz not null,
+  published_at timestamptz,
+  archived_at timestamptz not null default now()
+);
+
+create index if not exists idx_events_outbox_archive_topic_created
+on events.outbox_archive (topic, created_at);
+
+create index if not exists idx_events_outbox_published_retention
+on events.outbox (published_at, id)
+where status = 'published';
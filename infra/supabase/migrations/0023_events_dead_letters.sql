@@ -0,0 +1,23 @@
+create table if not exists events.dead_letters (
+  id bigserial primary key,
+  dlq_event_id text not null unique,
+  event_id text not null,
+  original_topic text not null,
+  consumer text not null default '',
+  error text not null default '',
+  attempts integer not null default 0,
+  failed_at timestamptz not null default now(),
+  payload jsonb not null,
+  headers jsonb not null default '{}'::jsonb,
+  status text not null default 'open' check (status in ('open', 'redriven')),
+  redrive_event_id text,
+  redriven_by text,
+  redriven_at timestamptz,
+  received_at timestamptz not null default now()
+);
+
+create index if not exists idx_events_dead_letters_status_topic
+on events.dead_letters (status, original_topic, id desc);
+
+create index if not exists idx_events_dead_letters_event
+on events.dead_letters (event_id);
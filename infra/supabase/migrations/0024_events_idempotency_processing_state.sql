@@ -0,0 +1,4 @@
+alter table events.idempotency_keys
+  add column if not exists status text not null default 'completed'
+    check (status in ('processing', 'completed')),
+  add column if not exists updated_at timestamptz not null default now();
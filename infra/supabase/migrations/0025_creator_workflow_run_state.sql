@@ -0,0 +1,13 @@
+create table if not exists creator.workflow_run_state (
+  run_id uuid primary key references creator.workflow_runs(id) on delete cascade,
+  request jsonb not null,
+  steps jsonb not null,
+  current_step int not null default 0,
+  status text not null,
+  artifacts jsonb not null default '{}'::jsonb,
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now()
+);
+
+create index if not exists idx_creator_workflow_run_state_status
+on creator.workflow_run_state (status, updated_at desc);
@@ -0,0 +1,14 @@
+create table if not exists creator.generation_jobs (
+  run_id uuid not null references creator.workflow_runs(id) on delete cascade,
+  step_index int not null,
+  job_id text not null,
+  provider text not null,
+  status text not null,
+  progress int not null default 0 check (progress between 0 and 100),
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now(),
+  primary key (run_id, step_index)
+);
+
+create index if not exists idx_creator_generation_jobs_status
+on creator.generation_jobs (status, updated_at desc);
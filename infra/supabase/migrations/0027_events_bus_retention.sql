@@ -0,0 +1,5 @@
+create index if not exists idx_events_bus_messages_created
+on events.bus_messages (created_at, id);
+
+create index if not exists idx_events_bus_redeliveries_message
+on events.bus_redeliveries (message_id);
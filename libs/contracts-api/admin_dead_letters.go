@@ -0,0 +1,29 @@
+package contractsapi
+
+import "encoding/json"
+
+type AdminDeadLetter struct {
+	DeadLetterID   string            `json:"dead_letter_id"`
+	EventID        string            `json:"event_id"`
+	OriginalTopic  string            `json:"original_topic"`
+	Consumer       string            `json:"consumer"`
+	Error          string            `json:"error"`
+	Attempts       int               `json:"attempts"`
+	FailedAt       string            `json:"failed_at"`
+	Status         string            `json:"status"`
+	RedriveEventID string            `json:"redrive_event_id,omitempty"`
+	RedrivenBy     string            `json:"redriven_by,omitempty"`
+	RedrivenAt     string            `json:"redriven_at,omitempty"`
+	Payload        json.RawMessage   `json:"payload,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty"`
+}
+
+type AdminDeadLetterListResponse struct {
+	DeadLetters []AdminDeadLetter `json:"dead_letters"`
+}
+
+type AdminDeadLetterRedriveResponse struct {
+	DeadLetterID   string `json:"dead_letter_id"`
+	Status         string `json:"status"`
+	RedriveEventID string `json:"redrive_event_id"`
+}
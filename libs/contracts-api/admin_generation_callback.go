@@ -0,0 +1,29 @@
+package contractsapi
+
+type AdminGenerationCallbackRequest struct {
+	JobID    string `json:"job_id"`
+	Status   string `json:"status"`
+	Progress int    `json:"progress"`
+	Error    string `json:"error,omitempty"`
+}
+
+type AdminGenerationCallbackResponse struct {
+	RunID  string `json:"run_id"`
+	JobID  string `json:"job_id"`
+	Status string `json:"status"`
+}
+
+func (r AdminGenerationCallbackRequest) Validate() *APIError {
+	if r.JobID == "" {
+		return &APIError{Code: "callback_invalid", Message: "job_id is required"}
+	}
+	switch r.Status {
+	case "queued", "running", "succeeded", "failed":
+	default:
+		return &APIError{Code: "callback_invalid", Message: "status must be one of: queued, running, succeeded, failed"}
+	}
+	if r.Progress < 0 || r.Progress > 100 {
+		return &APIError{Code: "callback_invalid", Message: "progress must be between 0 and 100"}
+	}
+	return nil
+}
@@ -90,13 +90,22 @@ type AdminRunLogsResponse struct {
 }
 
 type AdminModelProfile struct {
-	ModelProfileID string `json:"model_profile_id"`
-	Provider       string `json:"provider"`
-	BaseURL        string `json:"base_url"`
-	ModelID        string `json:"model_id"`
-	TimeoutMS      int    `json:"timeout_ms"`
-	MaxRetries     int    `json:"max_retries"`
-	SafetyPreset   string `json:"safety_preset"`
+	ModelProfileID string                     `json:"model_profile_id"`
+	Provider       string                     `json:"provider"`
+	BaseURL        string                     `json:"base_url"`
+	ModelID        string                     `json:"model_id"`
+	TimeoutMS      int                        `json:"timeout_ms"`
+	MaxRetries     int                        `json:"max_retries"`
+	SafetyPreset   string                     `json:"safety_preset"`
+	Capabilities   *AdminProviderCapabilities `json:"capabilities,omitempty"`
+}
+
+type AdminProviderCapabilities struct {
+	MaxDurationMS int64    `json:"max_duration_ms"`
+	Resolutions   []string `json:"resolutions"`
+	InputFields   []string `json:"input_fields"`
+	Steps         []string `json:"steps"`
+	Callbacks     bool     `json:"callbacks"`
 }
 
 func (r AdminLoginRequest) Validate() *APIError {
@@ -143,9 +152,6 @@ func (p AdminModelProfile) Validate() *APIError {
 	if p.ModelProfileID == "" || p.Provider == "" || p.BaseURL == "" || p.ModelID == "" {
 		return &APIError{Code: "workflow_invalid", Message: "model_profile_id, provider, base_url and model_id are required"}
 	}
-	if p.Provider != "nvidia_nim" {
-		return &APIError{Code: "workflow_invalid", Message: "provider must be nvidia_nim"}
-	}
 	if p.TimeoutMS < 500 || p.TimeoutMS > 120000 {
 		return &APIError{Code: "workflow_invalid", Message: "timeout_ms must be between 500 and 120000"}
 	}
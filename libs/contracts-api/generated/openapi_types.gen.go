@@ -9,6 +9,20 @@ import (
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+// Defines values for AdminDeadLetterStatus.
+const (
+	AdminDeadLetterStatusOpen     AdminDeadLetterStatus = "open"
+	AdminDeadLetterStatusRedriven AdminDeadLetterStatus = "redriven"
+)
+
+// Defines values for AdminGenerationCallbackRequestStatus.
+const (
+	AdminGenerationCallbackRequestStatusFailed    AdminGenerationCallbackRequestStatus = "failed"
+	AdminGenerationCallbackRequestStatusQueued    AdminGenerationCallbackRequestStatus = "queued"
+	AdminGenerationCallbackRequestStatusRunning   AdminGenerationCallbackRequestStatus = "running"
+	AdminGenerationCallbackRequestStatusSucceeded AdminGenerationCallbackRequestStatus = "succeeded"
+)
+
 // Defines values for AdminLoginResponseRole.
 const (
 	Admin AdminLoginResponseRole = "admin"
@@ -16,7 +30,9 @@ const (
 
 // Defines values for AdminModelProfileProvider.
 const (
-	NvidiaNim AdminModelProfileProvider = "nvidia_nim"
+	HttpPredictions AdminModelProfileProvider = "http_predictions"
+	LocalFake       AdminModelProfileProvider = "local_fake"
+	NvidiaNim       AdminModelProfileProvider = "nvidia_nim"
 )
 
 // Defines values for AdminWorkflowContentSuitability.
@@ -53,6 +69,12 @@ const (
 	KidsModeTeen  KidsMode = "teen"
 )
 
+// Defines values for ListAdminDeadLettersParamsStatus.
+const (
+	ListAdminDeadLettersParamsStatusOpen     ListAdminDeadLettersParamsStatus = "open"
+	ListAdminDeadLettersParamsStatusRedriven ListAdminDeadLettersParamsStatus = "redriven"
+)
+
 // Defines values for RailItemContentSuitability.
 const (
 	RailItemContentSuitabilityCore  RailItemContentSuitability = "core"
@@ -72,6 +94,56 @@ type APIError struct {
 	Message string `json:"message"`
 }
 
+// AdminDeadLetter defines model for AdminDeadLetter.
+type AdminDeadLetter struct {
+	Attempts       int                     `json:"attempts"`
+	Consumer       string                  `json:"consumer"`
+	DeadLetterId   string                  `json:"dead_letter_id"`
+	Error          string                  `json:"error"`
+	EventId        string                  `json:"event_id"`
+	FailedAt       string                  `json:"failed_at"`
+	Headers        *map[string]string      `json:"headers,omitempty"`
+	OriginalTopic  string                  `json:"original_topic"`
+	Payload        *map[string]interface{} `json:"payload,omitempty"`
+	RedriveEventId *string                 `json:"redrive_event_id,omitempty"`
+	RedrivenAt     *string                 `json:"redriven_at,omitempty"`
+	RedrivenBy     *string                 `json:"redriven_by,omitempty"`
+	Status         AdminDeadLetterStatus   `json:"status"`
+}
+
+// AdminDeadLetterStatus defines model for AdminDeadLetter.Status.
+type AdminDeadLetterStatus string
+
+// AdminDeadLetterListResponse defines model for AdminDeadLetterListResponse.
+type AdminDeadLetterListResponse struct {
+	DeadLetters []AdminDeadLetter `json:"dead_letters"`
+}
+
+// AdminDeadLetterRedriveResponse defines model for AdminDeadLetterRedriveResponse.
+type AdminDeadLetterRedriveResponse struct {
+	DeadLetterId   string `json:"dead_letter_id"`
+	RedriveEventId string `json:"redrive_event_id"`
+	Status         string `json:"status"`
+}
+
+// AdminGenerationCallbackRequest defines model for AdminGenerationCallbackRequest.
+type AdminGenerationCallbackRequest struct {
+	Error    *string                              `json:"error,omitempty"`
+	JobId    string                               `json:"job_id"`
+	Progress *int                                 `json:"progress,omitempty"`
+	Status   AdminGenerationCallbackRequestStatus `json:"status"`
+}
+
+// AdminGenerationCallbackRequestStatus defines model for AdminGenerationCallbackRequest.Status.
+type AdminGenerationCallbackRequestStatus string
+
+// AdminGenerationCallbackResponse defines model for AdminGenerationCallbackResponse.
+type AdminGenerationCallbackResponse struct {
+	JobId  string `json:"job_id"`
+	RunId  string `json:"run_id"`
+	Status string `json:"status"`
+}
+
 // AdminLoginRequest defines model for AdminLoginRequest.
 type AdminLoginRequest struct {
 	Email    openapi_types.Email `json:"email"`
@@ -92,18 +164,28 @@ type AdminLoginResponseRole string
 
 // AdminModelProfile defines model for AdminModelProfile.
 type AdminModelProfile struct {
-	BaseUrl        string                    `json:"base_url"`
-	MaxRetries     int                       `json:"max_retries"`
-	ModelId        string                    `json:"model_id"`
-	ModelProfileId string                    `json:"model_profile_id"`
-	Provider       AdminModelProfileProvider `json:"provider"`
-	SafetyPreset   string                    `json:"safety_preset"`
-	TimeoutMs      int                       `json:"timeout_ms"`
+	BaseUrl        string                     `json:"base_url"`
+	Capabilities   *AdminProviderCapabilities `json:"capabilities,omitempty"`
+	MaxRetries     int                        `json:"max_retries"`
+	ModelId        string                     `json:"model_id"`
+	ModelProfileId string                     `json:"model_profile_id"`
+	Provider       AdminModelProfileProvider  `json:"provider"`
+	SafetyPreset   string                     `json:"safety_preset"`
+	TimeoutMs      int                        `json:"timeout_ms"`
 }
 
 // AdminModelProfileProvider defines model for AdminModelProfile.Provider.
 type AdminModelProfileProvider string
 
+// AdminProviderCapabilities defines model for AdminProviderCapabilities.
+type AdminProviderCapabilities struct {
+	Callbacks     bool     `json:"callbacks"`
+	InputFields   []string `json:"input_fields"`
+	MaxDurationMs int64    `json:"max_duration_ms"`
+	Resolutions   []string `json:"resolutions"`
+	Steps         []string `json:"steps"`
+}
+
 // AdminRunLogEntry defines model for AdminRunLogEntry.
 type AdminRunLogEntry struct {
 	EventTime time.Time `json:"event_time"`
@@ -441,6 +523,9 @@ type ChildProfileIDPath = string
 // ChildProfileIDQuery defines model for ChildProfileIDQuery.
 type ChildProfileIDQuery = string
 
+// DeadLetterIDPath defines model for DeadLetterIDPath.
+type DeadLetterIDPath = string
+
 // ModelProfileIDPath defines model for ModelProfileIDPath.
 type ModelProfileIDPath = string
 
@@ -450,6 +535,22 @@ type RunIDPath = string
 // WorkflowIDPath defines model for WorkflowIDPath.
 type WorkflowIDPath = string
 
+// ListAdminDeadLettersParams defines parameters for ListAdminDeadLetters.
+type ListAdminDeadLettersParams struct {
+	Status *ListAdminDeadLettersParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+	Topic  *string                           `form:"topic,omitempty" json:"topic,omitempty"`
+	Limit  *int                              `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListAdminDeadLettersParamsStatus defines parameters for ListAdminDeadLetters.
+type ListAdminDeadLettersParamsStatus string
+
+// PostGenerationCallbackParams defines parameters for PostGenerationCallback.
+type PostGenerationCallbackParams struct {
+	Token          *string `form:"token,omitempty" json:"token,omitempty"`
+	XCallbackToken *string `json:"X-Callback-Token,omitempty"`
+}
+
 // GetBillingEntitlementParams defines parameters for GetBillingEntitlement.
 type GetBillingEntitlementParams struct {
 	ParentUserId   *string `form:"parent_user_id,omitempty" json:"parent_user_id,omitempty"`
@@ -483,6 +584,9 @@ type AdminLoginJSONRequestBody = AdminLoginRequest
 // PutAdminModelProfileJSONRequestBody defines body for PutAdminModelProfile for application/json ContentType.
 type PutAdminModelProfileJSONRequestBody = AdminModelProfile
 
+// PostGenerationCallbackJSONRequestBody defines body for PostGenerationCallback for application/json ContentType.
+type PostGenerationCallbackJSONRequestBody = AdminGenerationCallbackRequest
+
 // CreateAdminWorkflowJSONRequestBody defines body for CreateAdminWorkflow for application/json ContentType.
 type CreateAdminWorkflowJSONRequestBody = CreateAdminWorkflowRequest
 
@@ -0,0 +1,68 @@
+package contractsevents
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+type Contract interface {
+	Validate() error
+}
+
+var registry = map[string]func() Contract{
+	"child.session.capped.v1":      func() Contract { return &ChildSessionCappedV1{} },
+	"consent.verified.v1":          func() Contract { return &ConsentVerifiedV1{} },
+	"episode.published.v1":         func() Contract { return &EpisodePublishedV1{} },
+	"media.approved.v1":            func() Contract { return &MediaApprovedV1{} },
+	"media.reviewed.v1":            func() Contract { return &MediaReviewedV1{} },
+	"media.transcode.requested.v1": func() Contract { return &MediaTranscodeRequestedV1{} },
+	"media.transcoded.v1":          func() Contract { return &MediaTranscodedV1{} },
+	"media.uploaded.v1":            func() Contract { return &MediaUploadedV1{} },
+	"parent.controls.updated.v1":   func() Contract { return &ParentControlsUpdatedV1{} },
+	"parent.gate.challenge.v1":     func() Contract { return &ParentGateChallengeV1{} },
+	"playback.session.ended.v1":    func() Contract { return &PlaybackSessionEndedV1{} },
+	"playback.session.started.v1":  func() Contract { return &PlaybackSessionStartedV1{} },
+	"safety.filter.applied.v1":     func() Contract { return &SafetyFilterAppliedV1{} },
+	"ux.flow.completed.v1":         func() Contract { return &UXFlowCompletedV1{} },
+	"video.asset.ready.v1":         func() Contract { return &VideoAssetReadyV1{} },
+	"video.run.cancelled.v1":       func() Contract { return &VideoRunCancelledV1{} },
+	"video.run.failed.v1":          func() Contract { return &VideoRunFailedV1{} },
+	"video.run.job.updated.v1":     func() Contract { return &VideoRunJobUpdatedV1{} },
+	"video.run.requested.v1":       func() Contract { return &VideoRunRequestedV1{} },
+	"video.run.step.completed.v1":  func() Contract { return &VideoRunStepCompletedV1{} },
+	"video.run.step.requested.v1":  func() Contract { return &VideoRunStepRequestedV1{} },
+	"video.workflow.created.v1":    func() Contract { return &VideoWorkflowCreatedV1{} },
+	"watch.event.v1":               func() Contract { return &WatchEventV1{} },
+}
+
+func ForTopic(topic string) (Contract, bool) {
+	newContract, ok := registry[topic]
+	if !ok {
+		return nil, false
+	}
+	return newContract(), true
+}
+
+func Topics() []string {
+	topics := make([]string, 0, len(registry))
+	for topic := range registry {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+func DecodeAndValidate(topic string, payload []byte) (Contract, error) {
+	contract, ok := ForTopic(topic)
+	if !ok {
+		return nil, fmt.Errorf("no contract registered for %s", topic)
+	}
+	if err := json.Unmarshal(payload, contract); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", topic, err)
+	}
+	if err := contract.Validate(); err != nil {
+		return nil, err
+	}
+	return contract, nil
+}
@@ -0,0 +1,35 @@
+package contractsevents
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRegistryCoversEverySchema(t *testing.T) {
+	entries, err := os.ReadDir("schemas")
+	if err != nil {
+		t.Fatalf("read schemas: %v", err)
+	}
+	for _, entry := range entries {
+		topic := strings.TrimSuffix(entry.Name(), ".json")
+		if _, ok := ForTopic(topic); !ok {
+			t.Fatalf("schema %s has no registered contract", entry.Name())
+		}
+	}
+	if len(Topics()) != len(entries) {
+		t.Fatalf("expected %d topics, got %d", len(entries), len(Topics()))
+	}
+}
+
+func TestDecodeAndValidate(t *testing.T) {
+	if _, err := DecodeAndValidate("media.uploaded.v1", []byte(`{"asset_id":"a1","source_url":"https://cdn/x.mp4","uploader_id":"u1","trace_id":"tr1"}`)); err != nil {
+		t.Fatalf("expected valid payload, got %v", err)
+	}
+	if _, err := DecodeAndValidate("media.uploaded.v1", []byte(`{"asset_id":"a1"}`)); err == nil {
+		t.Fatalf("expected validation error")
+	}
+	if _, err := DecodeAndValidate("unknown.topic.v1", []byte(`{}`)); err == nil {
+		t.Fatalf("expected unknown topic error")
+	}
+}
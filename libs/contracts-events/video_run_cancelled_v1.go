@@ -0,0 +1,17 @@
+package contractsevents
+
+import "errors"
+
+type VideoRunCancelledV1 struct {
+	RunID       string `json:"run_id"`
+	CancelledBy string `json:"cancelled_by"`
+	Reason      string `json:"reason"`
+	CancelledAt string `json:"cancelled_at"`
+}
+
+func (e VideoRunCancelledV1) Validate() error {
+	if e.RunID == "" || e.CancelledBy == "" || e.CancelledAt == "" {
+		return errors.New("video.run.cancelled.v1 has missing required fields")
+	}
+	return nil
+}
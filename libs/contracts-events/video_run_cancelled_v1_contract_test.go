@@ -0,0 +1,24 @@
+package contractsevents
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestVideoRunCancelledV1Contract(t *testing.T) {
+	raw := []byte(`{"run_id":"run1","cancelled_by":"admin1","reason":"workflow run cancelled by admin","cancelled_at":"2026-03-11T09:00:00Z"}`)
+	var event VideoRunCancelledV1
+	if err := json.Unmarshal(raw, &event); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if err := event.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+}
+
+func TestVideoRunCancelledV1ContractRejectsMissingActor(t *testing.T) {
+	event := VideoRunCancelledV1{RunID: "run1", CancelledAt: "2026-03-11T09:00:00Z"}
+	if err := event.Validate(); err == nil {
+		t.Fatalf("expected validation error")
+	}
+}
@@ -5,6 +5,7 @@ import "errors"
 type VideoRunFailedV1 struct {
 	RunID        string `json:"run_id"`
 	Step         string `json:"step"`
+	StepIndex    int    `json:"step_index"`
 	ErrorCode    string `json:"error_code"`
 	ErrorMessage string `json:"error_message"`
 	FailedAt     string `json:"failed_at"`
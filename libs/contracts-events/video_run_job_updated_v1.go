@@ -0,0 +1,22 @@
+package contractsevents
+
+import "errors"
+
+type VideoRunJobUpdatedV1 struct {
+	RunID        string `json:"run_id"`
+	JobID        string `json:"job_id"`
+	Status       string `json:"status"`
+	Progress     int    `json:"progress"`
+	ErrorMessage string `json:"error_message,omitempty"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+func (e VideoRunJobUpdatedV1) Validate() error {
+	if e.RunID == "" || e.JobID == "" || e.Status == "" || e.UpdatedAt == "" {
+		return errors.New("video.run.job.updated.v1 has missing required fields")
+	}
+	if e.Progress < 0 || e.Progress > 100 {
+		return errors.New("video.run.job.updated.v1 progress must be between 0 and 100")
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package contractsevents
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestVideoRunJobUpdatedV1Contract(t *testing.T) {
+	raw := []byte(`{"run_id":"run1","job_id":"job1","status":"running","progress":40,"updated_at":"2026-03-11T09:00:00Z"}`)
+	var event VideoRunJobUpdatedV1
+	if err := json.Unmarshal(raw, &event); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if err := event.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+}
+
+func TestVideoRunJobUpdatedV1ContractRejectsInvalidProgress(t *testing.T) {
+	event := VideoRunJobUpdatedV1{RunID: "run1", JobID: "job1", Status: "running", Progress: 140, UpdatedAt: "2026-03-11T09:00:00Z"}
+	if err := event.Validate(); err == nil {
+		t.Fatalf("expected validation error")
+	}
+}
@@ -14,6 +14,7 @@ type VideoRunRequestedV1 struct {
 	Priority           string          `json:"priority"`
 	ContentSuitability string          `json:"content_suitability"`
 	AgeBand            string          `json:"age_band"`
+	Steps              []string        `json:"steps,omitempty"`
 	RequestedBy        string          `json:"requested_by"`
 	RequestedAt        string          `json:"requested_at"`
 	TraceID            string          `json:"trace_id"`
@@ -3,11 +3,13 @@ package contractsevents
 import "errors"
 
 type VideoRunStepCompletedV1 struct {
-	RunID       string `json:"run_id"`
-	Step        string `json:"step"`
-	Status      string `json:"status"`
-	Details     string `json:"details"`
-	CompletedAt string `json:"completed_at"`
+	RunID       string            `json:"run_id"`
+	Step        string            `json:"step"`
+	StepIndex   int               `json:"step_index"`
+	Status      string            `json:"status"`
+	Details     string            `json:"details"`
+	Artifacts   map[string]string `json:"artifacts,omitempty"`
+	CompletedAt string            `json:"completed_at"`
 }
 
 func (e VideoRunStepCompletedV1) Validate() error {
@@ -0,0 +1,34 @@
+package contractsevents
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+type VideoRunStepRequestedV1 struct {
+	RunID              string            `json:"run_id"`
+	WorkflowID         string            `json:"workflow_id"`
+	Step               string            `json:"step"`
+	StepIndex          int               `json:"step_index"`
+	ModelProfileID     string            `json:"model_profile_id"`
+	InputPayload       json.RawMessage   `json:"input_payload"`
+	AutoPublish        bool              `json:"auto_publish"`
+	ContentSuitability string            `json:"content_suitability"`
+	AgeBand            string            `json:"age_band"`
+	RequestedBy        string            `json:"requested_by"`
+	Artifacts          map[string]string `json:"artifacts,omitempty"`
+	RequestedAt        string            `json:"requested_at"`
+}
+
+func (e VideoRunStepRequestedV1) Validate() error {
+	if e.RunID == "" || e.WorkflowID == "" || e.Step == "" || e.ModelProfileID == "" || e.RequestedBy == "" {
+		return errors.New("video.run.step.requested.v1 has missing required fields")
+	}
+	if e.StepIndex < 0 {
+		return errors.New("video.run.step.requested.v1 requires non-negative step_index")
+	}
+	if e.RequestedAt == "" || e.ContentSuitability == "" || e.AgeBand == "" {
+		return errors.New("video.run.step.requested.v1 has missing metadata fields")
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package contractsevents
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestVideoRunStepRequestedV1Contract(t *testing.T) {
+	raw := []byte(`{"run_id":"run1","workflow_id":"wf1","step":"qc","step_index":3,"model_profile_id":"nim-default","input_payload":{"theme":"space"},"auto_publish":false,"content_suitability":"core","age_band":"6-11","requested_by":"admin1","artifacts":{"asset_id":"a1"},"requested_at":"2026-03-11T09:00:00Z"}`)
+	var event VideoRunStepRequestedV1
+	if err := json.Unmarshal(raw, &event); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if err := event.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if event.Artifacts["asset_id"] != "a1" {
+		t.Fatalf("expected artifacts to decode, got %v", event.Artifacts)
+	}
+}
+
+func TestVideoRunStepRequestedV1ContractRejectsMissingStep(t *testing.T) {
+	event := VideoRunStepRequestedV1{RunID: "run1", WorkflowID: "wf1", ModelProfileID: "nim-default", RequestedBy: "admin1", RequestedAt: "2026-03-11T09:00:00Z", ContentSuitability: "core", AgeBand: "6-11"}
+	if err := event.Validate(); err == nil {
+		t.Fatalf("expected validation error")
+	}
+}
@@ -0,0 +1,84 @@
+package generatorprovider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RetryableError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+func IsRetryable(err error) bool {
+	var retryable *RetryableError
+	return errors.As(err, &retryable)
+}
+
+func RetryAfter(err error) time.Duration {
+	var retryable *RetryableError
+	if errors.As(err, &retryable) {
+		return retryable.RetryAfter
+	}
+	return 0
+}
+
+func classifyTransport(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+	return &RetryableError{Err: err}
+}
+
+type statusError struct {
+	code int
+	err  error
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+func hasStatus(err error, codes ...int) bool {
+	var status *statusError
+	return errors.As(err, &status) && slices.Contains(codes, status.code)
+}
+
+func classifyStatus(resp *http.Response, err error) error {
+	err = &statusError{code: resp.StatusCode, err: err}
+	if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusRequestTimeout {
+		return err
+	}
+	return &RetryableError{Err: err, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())}
+}
+
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
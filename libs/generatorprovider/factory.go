@@ -1,12 +1,21 @@
 package generatorprovider
 
-import "fmt"
-
 func NewProvider(profile ModelProfile) (Provider, error) {
-	switch profile.Provider {
-	case "nvidia_nim":
-		return NewNIMProvider(profile), nil
-	default:
-		return nil, fmt.Errorf("unsupported provider: %s", profile.Provider)
+	return defaultRegistry.New(profile)
+}
+
+func nimBackend() Backend {
+	return Backend{
+		Name: "nvidia_nim",
+		Capabilities: Capabilities{
+			MaxDurationMS: 600000,
+			Resolutions:   []string{"720p", "1080p"},
+			InputFields:   []string{"theme", "prompt", "duration_ms", "resolution", "style"},
+			Steps:         []string{"script", "voice", "nim"},
+			Callbacks:     true,
+		},
+		New: func(profile ModelProfile) (Provider, error) {
+			return NewNIMProvider(profile), nil
+		},
 	}
 }
@@ -0,0 +1,189 @@
+package generatorprovider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	fakeVideoHeader    = "mikasmissions-fake-video\n"
+	fakeMaxDurationMS  = 60000
+	fakeSampleRate     = 8000
+	fakeWordDurationMS = 400
+	fakeDefaultVideoMS = 60000
+	fakeMaxVoiceMS     = 30000
+)
+
+type fakeProvider struct {
+	dir     string
+	baseURL string
+}
+
+type fakeVideo struct {
+	JobID      string `json:"job_id"`
+	RunID      string `json:"run_id"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+func fakeBackend() Backend {
+	return Backend{
+		Name: "local_fake",
+		Capabilities: Capabilities{
+			MaxDurationMS: fakeMaxDurationMS,
+			Resolutions:   []string{"360p"},
+			InputFields:   []string{"theme", "prompt", "duration_ms"},
+			Steps:         []string{"script", "voice", "nim"},
+		},
+		New: func(profile ModelProfile) (Provider, error) {
+			dir := os.Getenv("GEN_FAKE_OUTPUT_DIR")
+			if dir == "" {
+				dir = filepath.Join(os.TempDir(), "mikasmissions-fake-generator")
+			}
+			return NewFakeProvider(dir, profile.BaseURL), nil
+		},
+	}
+}
+
+func NewFakeProvider(dir, baseURL string) Provider {
+	return &fakeProvider{dir: dir, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (p *fakeProvider) GenerateScript(_ context.Context, req GenerateRequest) (ScriptResult, error) {
+	input := fakeInput(req)
+	theme, _ := input["theme"].(string)
+	if prompt, ok := input["prompt"].(string); ok && theme == "" {
+		theme = prompt
+	}
+	if theme == "" {
+		theme = "a friendly adventure"
+	}
+	return ScriptResult{Script: fmt.Sprintf("Once upon a time there was %s. Story %s.", theme, fakeDigest(req))}, nil
+}
+
+func (p *fakeProvider) GenerateVoice(_ context.Context, req GenerateRequest) (VoiceResult, error) {
+	durationMS := int64(len(strings.Fields(req.Artifacts["script"]))) * fakeWordDurationMS
+	if durationMS <= 0 {
+		durationMS = fakeWordDurationMS
+	}
+	if durationMS > fakeMaxVoiceMS {
+		durationMS = fakeMaxVoiceMS
+	}
+	name := "voice-" + fakeDigest(req) + ".wav"
+	if err := p.write(name, silentWAV(durationMS)); err != nil {
+		return VoiceResult{}, err
+	}
+	return VoiceResult{AudioURL: p.url(name), DurationMS: durationMS}, nil
+}
+
+func (p *fakeProvider) SubmitVideo(_ context.Context, req GenerateRequest) (Job, error) {
+	durationMS := int64(fakeDefaultVideoMS)
+	if requested, ok := fakeInput(req)["duration_ms"].(float64); ok && requested > 0 {
+		durationMS = int64(requested)
+	}
+	if durationMS > fakeMaxDurationMS {
+		durationMS = fakeMaxDurationMS
+	}
+	video := fakeVideo{JobID: "fake-" + fakeDigest(req), RunID: req.RunID, DurationMS: durationMS}
+	payload, err := json.Marshal(video)
+	if err != nil {
+		return Job{}, fmt.Errorf("marshal fake video: %w", err)
+	}
+	if err := p.write(video.JobID+".mp4", append([]byte(fakeVideoHeader), payload...)); err != nil {
+		return Job{}, err
+	}
+	return Job{ID: video.JobID, Status: JobSucceeded, Progress: 100}, nil
+}
+
+func (p *fakeProvider) VideoStatus(_ context.Context, jobID string) (Job, error) {
+	if _, err := p.read(jobID); err != nil {
+		return Job{ID: jobID, Status: JobFailed, Error: err.Error()}, nil
+	}
+	return Job{ID: jobID, Status: JobSucceeded, Progress: 100}, nil
+}
+
+func (p *fakeProvider) FetchVideo(_ context.Context, jobID string) (GenerateResult, error) {
+	video, err := p.read(jobID)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	return GenerateResult{AssetID: video.JobID, SourceURL: p.url(video.JobID + ".mp4"), DurationMS: video.DurationMS}, nil
+}
+
+func (p *fakeProvider) read(jobID string) (fakeVideo, error) {
+	raw, err := os.ReadFile(filepath.Join(p.dir, filepath.Base(jobID)+".mp4"))
+	if errors.Is(err, os.ErrNotExist) {
+		return fakeVideo{}, fmt.Errorf("unknown fake video job %s", jobID)
+	}
+	if err != nil {
+		return fakeVideo{}, fmt.Errorf("read fake video: %w", err)
+	}
+	var video fakeVideo
+	if err := json.Unmarshal(bytes.TrimPrefix(raw, []byte(fakeVideoHeader)), &video); err != nil {
+		return fakeVideo{}, fmt.Errorf("decode fake video: %w", err)
+	}
+	return video, nil
+}
+
+func (p *fakeProvider) write(name string, payload []byte) error {
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return fmt.Errorf("create fake output dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(p.dir, name), payload, 0o644); err != nil {
+		return fmt.Errorf("write fake asset: %w", err)
+	}
+	return nil
+}
+
+func (p *fakeProvider) url(name string) string {
+	if p.baseURL == "" {
+		return "file://" + filepath.Join(p.dir, name)
+	}
+	return p.baseURL + "/" + name
+}
+
+func fakeInput(req GenerateRequest) map[string]any {
+	input := map[string]any{}
+	_ = json.Unmarshal(req.InputPayload, &input)
+	return input
+}
+
+func fakeDigest(req GenerateRequest) string {
+	keys := make([]string, 0, len(req.Artifacts))
+	for key := range req.Artifacts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	hash := sha256.New()
+	hash.Write([]byte(req.RunID))
+	hash.Write(req.InputPayload)
+	for _, key := range keys {
+		hash.Write([]byte("\x00" + key + "=" + req.Artifacts[key]))
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+func silentWAV(durationMS int64) []byte {
+	samples := uint32(durationMS * fakeSampleRate / 1000)
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, 36+samples)
+	buf.WriteString("WAVEfmt ")
+	_ = binary.Write(&buf, binary.LittleEndian, []uint32{16})
+	_ = binary.Write(&buf, binary.LittleEndian, []uint16{1, 1})
+	_ = binary.Write(&buf, binary.LittleEndian, []uint32{fakeSampleRate, fakeSampleRate})
+	_ = binary.Write(&buf, binary.LittleEndian, []uint16{1, 8})
+	buf.WriteString("data")
+	_ = binary.Write(&buf, binary.LittleEndian, samples)
+	buf.Write(bytes.Repeat([]byte{0x80}, int(samples)))
+	return buf.Bytes()
+}
@@ -0,0 +1,45 @@
+package generatorprovider
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFakeProviderIsDeterministicAndWritesAssets(t *testing.T) {
+	dir := t.TempDir()
+	provider := NewFakeProvider(dir, "http://localhost:9100/fake-assets/")
+	req := GenerateRequest{RunID: "run-1", InputPayload: json.RawMessage(`{"theme":"space whales","duration_ms":45000}`)}
+
+	first, err := provider.GenerateScript(context.Background(), req)
+	second, _ := provider.GenerateScript(context.Background(), req)
+	if err != nil || first.Script != second.Script || first.Script == "" {
+		t.Fatalf("expected deterministic script, got %q and %q (%v)", first.Script, second.Script, err)
+	}
+	req.Artifacts = map[string]string{"script": first.Script}
+	voice, err := provider.GenerateVoice(context.Background(), req)
+	if err != nil || voice.DurationMS <= 0 {
+		t.Fatalf("unexpected voice: %+v %v", voice, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, filepath.Base(voice.AudioURL))); err != nil {
+		t.Fatalf("expected voice file on disk: %v", err)
+	}
+
+	job, err := provider.SubmitVideo(context.Background(), req)
+	again, _ := provider.SubmitVideo(context.Background(), req)
+	if err != nil || job.ID != again.ID || !job.Done() {
+		t.Fatalf("expected deterministic finished job, got %+v and %+v (%v)", job, again, err)
+	}
+	result, err := AwaitVideo(context.Background(), provider, job.ID, fastPolling(), nil, nil)
+	if err != nil {
+		t.Fatalf("await: %v", err)
+	}
+	if result.AssetID != job.ID || result.DurationMS != 45000 || result.SourceURL != "http://localhost:9100/fake-assets/"+job.ID+".mp4" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if status, _ := provider.VideoStatus(context.Background(), "fake-missing"); status.Status != JobFailed {
+		t.Fatalf("expected unknown job to fail, got %+v", status)
+	}
+}
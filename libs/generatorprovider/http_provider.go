@@ -0,0 +1,196 @@
+package generatorprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+type httpProvider struct {
+	jsonClient
+	modelID string
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+type predictionRequest struct {
+	Model string         `json:"model"`
+	Input map[string]any `json:"input"`
+}
+
+type predictionResponse struct {
+	ID     string          `json:"id"`
+	Status string          `json:"status"`
+	Output json.RawMessage `json:"output"`
+	Error  string          `json:"error"`
+}
+
+const predictionPollInterval = 500 * time.Millisecond
+
+func httpBackend() Backend {
+	return Backend{
+		Name: "http_predictions",
+		Capabilities: Capabilities{
+			MaxDurationMS: 300000,
+			Resolutions:   []string{"576p", "720p"},
+			InputFields:   []string{"prompt", "duration_ms", "resolution", "seed"},
+			Steps:         []string{"script", "voice", "nim"},
+		},
+		New: func(profile ModelProfile) (Provider, error) {
+			return NewHTTPProvider(profile, os.Getenv("GEN_HTTP_PROVIDER_TOKEN")), nil
+		},
+	}
+}
+
+func NewHTTPProvider(profile ModelProfile, token string) Provider {
+	headers := map[string]string{}
+	if token != "" {
+		headers["authorization"] = "Bearer " + token
+	}
+	return &httpProvider{
+		jsonClient: newJSONClient("http provider", profile, headers),
+		modelID:    profile.ModelID,
+	}
+}
+
+func (p *httpProvider) GenerateScript(ctx context.Context, req GenerateRequest) (ScriptResult, error) {
+	var decoded chatResponse
+	if err := p.do(ctx, http.MethodPost, "/v1/chat/completions", chatRequest{
+		Model: p.modelID,
+		Messages: []chatMessage{
+			{Role: "system", Content: "Write a short, age-appropriate narration script for a kids video."},
+			{Role: "user", Content: string(req.InputPayload)},
+		},
+	}, &decoded); err != nil {
+		return ScriptResult{}, err
+	}
+	if len(decoded.Choices) == 0 || decoded.Choices[0].Message.Content == "" {
+		return ScriptResult{}, fmt.Errorf("http provider script response is empty")
+	}
+	return ScriptResult{Script: decoded.Choices[0].Message.Content}, nil
+}
+
+func (p *httpProvider) GenerateVoice(ctx context.Context, req GenerateRequest) (VoiceResult, error) {
+	job, err := p.submit(ctx, "voice", req)
+	if err != nil {
+		return VoiceResult{}, err
+	}
+	for !job.Done() {
+		select {
+		case <-ctx.Done():
+			return VoiceResult{}, ctx.Err()
+		case <-time.After(predictionPollInterval):
+		}
+		if job, err = p.VideoStatus(ctx, job.ID); err != nil {
+			return VoiceResult{}, err
+		}
+	}
+	if job.Status == JobFailed {
+		return VoiceResult{}, fmt.Errorf("voice prediction %s failed: %s", job.ID, job.Error)
+	}
+	prediction, err := p.prediction(ctx, job.ID)
+	if err != nil {
+		return VoiceResult{}, err
+	}
+	audioURL := prediction.outputURL()
+	if audioURL == "" {
+		return VoiceResult{}, fmt.Errorf("voice prediction %s has no output", job.ID)
+	}
+	return VoiceResult{AudioURL: audioURL}, nil
+}
+
+func (p *httpProvider) SubmitVideo(ctx context.Context, req GenerateRequest) (Job, error) {
+	return p.submit(ctx, "video", req)
+}
+
+func (p *httpProvider) VideoStatus(ctx context.Context, jobID string) (Job, error) {
+	prediction, err := p.prediction(ctx, jobID)
+	if err != nil {
+		return Job{}, err
+	}
+	return prediction.job(), nil
+}
+
+func (p *httpProvider) FetchVideo(ctx context.Context, jobID string) (GenerateResult, error) {
+	prediction, err := p.prediction(ctx, jobID)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	sourceURL := prediction.outputURL()
+	if sourceURL == "" {
+		return GenerateResult{}, fmt.Errorf("video prediction %s has no output", jobID)
+	}
+	return GenerateResult{AssetID: jobID, SourceURL: sourceURL}, nil
+}
+
+func (p *httpProvider) submit(ctx context.Context, task string, req GenerateRequest) (Job, error) {
+	input := map[string]any{}
+	_ = json.Unmarshal(req.InputPayload, &input)
+	input["task"] = task
+	for key, value := range req.Artifacts {
+		input[key] = value
+	}
+	var decoded predictionResponse
+	prediction := predictionRequest{Model: p.modelID, Input: input}
+	if err := p.doKeyed(ctx, http.MethodPost, "/v1/predictions", req.IdempotencyKey, prediction, &decoded); err != nil {
+		return Job{}, err
+	}
+	if decoded.ID == "" {
+		return Job{}, fmt.Errorf("http provider prediction has no id")
+	}
+	return decoded.job(), nil
+}
+
+func (p *httpProvider) prediction(ctx context.Context, id string) (predictionResponse, error) {
+	var decoded predictionResponse
+	if err := p.do(ctx, http.MethodGet, "/v1/predictions/"+url.PathEscape(id), nil, &decoded); err != nil {
+		return predictionResponse{}, err
+	}
+	if decoded.ID == "" {
+		decoded.ID = id
+	}
+	return decoded, nil
+}
+
+func (r predictionResponse) job() Job {
+	switch r.Status {
+	case "starting":
+		return Job{ID: r.ID, Status: JobQueued}
+	case "succeeded":
+		return Job{ID: r.ID, Status: JobSucceeded, Progress: 100}
+	case "failed", "canceled":
+		return Job{ID: r.ID, Status: JobFailed, Error: r.Error}
+	default:
+		return Job{ID: r.ID, Status: JobRunning}
+	}
+}
+
+func (r predictionResponse) outputURL() string {
+	var single string
+	if err := json.Unmarshal(r.Output, &single); err == nil {
+		return single
+	}
+	var many []string
+	if err := json.Unmarshal(r.Output, &many); err == nil && len(many) > 0 {
+		return many[0]
+	}
+	return ""
+}
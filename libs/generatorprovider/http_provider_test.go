@@ -0,0 +1,83 @@
+package generatorprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPProviderRunsPredictions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"A whale sings."}}]}`))
+	})
+	mux.HandleFunc("POST /v1/predictions", func(w http.ResponseWriter, r *http.Request) {
+		var body predictionRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.Model != "video-model" || body.Input["task"] != "video" || body.Input["script"] != "A whale sings." || body.Input["theme"] != "ocean" {
+			http.Error(w, "bad input", http.StatusUnprocessableEntity)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"pred-1","status":"starting"}`))
+	})
+	mux.HandleFunc("GET /v1/predictions/pred-1", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"pred-1","status":"succeeded","output":["https://cdn.example/pred-1.mp4"]}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	provider := NewHTTPProvider(ModelProfile{BaseURL: server.URL, ModelID: "video-model"}, "secret")
+
+	script, err := provider.GenerateScript(context.Background(), GenerateRequest{RunID: "run-1", InputPayload: json.RawMessage(`{"theme":"ocean"}`)})
+	if err != nil || script.Script != "A whale sings." {
+		t.Fatalf("unexpected script: %+v %v", script, err)
+	}
+	req := GenerateRequest{RunID: "run-1", InputPayload: json.RawMessage(`{"theme":"ocean"}`), Artifacts: map[string]string{"script": script.Script}}
+	job, err := provider.SubmitVideo(context.Background(), req)
+	if err != nil || job.ID != "pred-1" || job.Status != JobQueued {
+		t.Fatalf("unexpected submit: %+v %v", job, err)
+	}
+	result, err := AwaitVideo(context.Background(), provider, job.ID, fastPolling(), nil, nil)
+	if err != nil || result.SourceURL != "https://cdn.example/pred-1.mp4" || result.AssetID != "pred-1" || result.DurationMS != 0 {
+		t.Fatalf("unexpected result: %+v %v", result, err)
+	}
+}
+
+func TestHTTPProviderKeysVoicePredictions(t *testing.T) {
+	created := map[string]string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/predictions", func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			http.Error(w, "missing idempotency key", http.StatusBadRequest)
+			return
+		}
+		if _, ok := created[key]; !ok {
+			created[key] = fmt.Sprintf("voice-%d", len(created)+1)
+		}
+		_, _ = w.Write([]byte(`{"id":"` + created[key] + `","status":"succeeded"}`))
+	})
+	mux.HandleFunc("GET /v1/predictions/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"` + r.PathValue("id") + `","status":"succeeded","output":"https://cdn.example/` + r.PathValue("id") + `.wav"}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	provider := NewHTTPProvider(ModelProfile{BaseURL: server.URL, ModelID: "voice-model"}, "")
+
+	req := GenerateRequest{RunID: "run-1", IdempotencyKey: "run-1:1", Artifacts: map[string]string{"script": "A whale sings."}}
+	for attempt := 0; attempt < 2; attempt++ {
+		voice, err := provider.GenerateVoice(context.Background(), req)
+		if err != nil || voice.AudioURL != "https://cdn.example/voice-1.wav" {
+			t.Fatalf("attempt %d: unexpected voice: %+v %v", attempt, voice, err)
+		}
+	}
+	if len(created) != 1 {
+		t.Fatalf("expected one voice prediction for a repeated key, got %v", created)
+	}
+}
@@ -0,0 +1,87 @@
+package generatorprovider
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+type Job struct {
+	ID       string
+	Status   JobStatus
+	Progress int
+	Error    string
+	Result   *GenerateResult
+}
+
+func (j Job) Done() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed
+}
+
+type PollPolicy struct {
+	Initial         time.Duration
+	Max             time.Duration
+	Multiplier      float64
+	MaxStatusErrors int
+}
+
+func DefaultPollPolicy() PollPolicy {
+	return PollPolicy{Initial: 2 * time.Second, Max: 30 * time.Second, Multiplier: 2, MaxStatusErrors: 5}
+}
+
+func (p PollPolicy) next(delay time.Duration) time.Duration {
+	next := time.Duration(float64(delay) * p.Multiplier)
+	if next > p.Max || next <= 0 {
+		return p.Max
+	}
+	return next
+}
+
+func AwaitVideo(ctx context.Context, provider Provider, jobID string, policy PollPolicy, wake <-chan struct{}, progress func(Job)) (GenerateResult, error) {
+	delay := policy.Initial
+	lastProgress := -1
+	statusErrors := 0
+	for {
+		job, err := provider.VideoStatus(ctx, jobID)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return GenerateResult{}, ctx.Err()
+		case err != nil:
+			statusErrors++
+			if statusErrors > policy.MaxStatusErrors {
+				return GenerateResult{}, fmt.Errorf("poll video job %s: %w", jobID, err)
+			}
+		default:
+			statusErrors = 0
+			if job.Progress != lastProgress && progress != nil {
+				progress(job)
+			}
+			lastProgress = job.Progress
+			if job.Status == JobSucceeded {
+				return provider.FetchVideo(ctx, jobID)
+			}
+			if job.Status == JobFailed {
+				return GenerateResult{}, fmt.Errorf("video job %s failed: %s", jobID, job.Error)
+			}
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return GenerateResult{}, ctx.Err()
+		case <-wake:
+			timer.Stop()
+		case <-timer.C:
+			delay = policy.next(delay)
+		}
+	}
+}
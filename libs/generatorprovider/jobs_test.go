@@ -0,0 +1,106 @@
+package generatorprovider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newJobServer(t *testing.T, finalStatus string) *httptest.Server {
+	t.Helper()
+	var polls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/jobs/video", func(w http.ResponseWriter, r *http.Request) {
+		var body nimRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.CallbackURL != "https://admin.example/callback" {
+			http.Error(w, "missing callback", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"job_id":"job-1","status":"queued","progress":0}`))
+	})
+	mux.HandleFunc("GET /v1/jobs/job-1", func(w http.ResponseWriter, _ *http.Request) {
+		switch polls.Add(1) {
+		case 1:
+			_, _ = w.Write([]byte(`{"job_id":"job-1","status":"running","progress":40}`))
+		case 2:
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write([]byte(`{"job_id":"job-1","status":"` + finalStatus + `","progress":100,"error":"unsafe content"}`))
+		}
+	})
+	mux.HandleFunc("GET /v1/jobs/job-1/result", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"asset_id":"asset-1","source_url":"https://cdn.example/asset-1.mp4","duration_ms":90000}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func fastPolling() PollPolicy {
+	return PollPolicy{Initial: time.Millisecond, Max: 4 * time.Millisecond, Multiplier: 2, MaxStatusErrors: 2}
+}
+
+func TestAwaitVideoPollsUntilResult(t *testing.T) {
+	provider := NewNIMProvider(ModelProfile{BaseURL: newJobServer(t, "succeeded").URL, ModelID: "nim-video-v1"})
+	job, err := provider.SubmitVideo(context.Background(), GenerateRequest{RunID: "run-1", CallbackURL: "https://admin.example/callback"})
+	if err != nil || job.ID != "job-1" || job.Status != JobQueued {
+		t.Fatalf("unexpected submit result: %+v %v", job, err)
+	}
+	var seen []int
+	result, err := AwaitVideo(context.Background(), provider, job.ID, fastPolling(), nil, func(job Job) {
+		seen = append(seen, job.Progress)
+	})
+	if err != nil {
+		t.Fatalf("await: %v", err)
+	}
+	if result.AssetID != "asset-1" || result.DurationMS != 90000 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if len(seen) != 2 || seen[0] != 40 || seen[1] != 100 {
+		t.Fatalf("expected progress 40 then 100, got %v", seen)
+	}
+}
+
+func TestAwaitVideoReturnsJobFailure(t *testing.T) {
+	provider := NewNIMProvider(ModelProfile{BaseURL: newJobServer(t, "failed").URL})
+	if _, err := AwaitVideo(context.Background(), provider, "job-1", fastPolling(), nil, nil); err == nil {
+		t.Fatalf("expected job failure error")
+	}
+}
+
+func TestPollPolicyBacksOffToMax(t *testing.T) {
+	policy := PollPolicy{Initial: time.Second, Max: 5 * time.Second, Multiplier: 2}
+	delay := policy.Initial
+	for range 5 {
+		delay = policy.next(delay)
+	}
+	if delay != 5*time.Second {
+		t.Fatalf("expected delay capped at 5s, got %s", delay)
+	}
+}
+
+func TestSubmitVideoFallsBackToSyncEndpoint(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/generate/video", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(idempotencyKeyHeader) != "run-1:2" {
+			http.Error(w, "missing idempotency key", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"asset_id":"asset-sync","source_url":"https://cdn.example/asset-sync.mp4","duration_ms":60000}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	provider := NewNIMProvider(ModelProfile{BaseURL: server.URL, ModelID: "nim-video-v1"})
+	job, err := provider.SubmitVideo(context.Background(), GenerateRequest{RunID: "run-1", IdempotencyKey: "run-1:2"})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if !job.Done() || job.Status != JobSucceeded || job.Result == nil || job.Result.SourceURL != "https://cdn.example/asset-sync.mp4" || job.Result.DurationMS != 60000 {
+		t.Fatalf("expected completed sync job, got %+v", job)
+	}
+}
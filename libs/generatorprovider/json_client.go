@@ -0,0 +1,86 @@
+package generatorprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type jsonClient struct {
+	name    string
+	client  *http.Client
+	baseURL *url.URL
+	headers map[string]string
+}
+
+func newJSONClient(name string, profile ModelProfile, headers map[string]string) jsonClient {
+	timeout := profile.TimeoutMS
+	if timeout <= 0 {
+		timeout = 15000
+	}
+	parsed, _ := url.Parse(profile.BaseURL)
+	if parsed == nil {
+		parsed = &url.URL{Scheme: "http", Host: "127.0.0.1:9000"}
+	}
+	return jsonClient{
+		name:    name,
+		client:  &http.Client{Timeout: time.Duration(timeout) * time.Millisecond},
+		baseURL: parsed,
+		headers: headers,
+	}
+}
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+func (c jsonClient) do(ctx context.Context, method, path string, body any, out any) error {
+	return c.doKeyed(ctx, method, path, "", body, out)
+}
+
+func (c jsonClient) doKeyed(ctx context.Context, method, path, idempotencyKey string, body any, out any) error {
+	if c.baseURL == nil {
+		return fmt.Errorf("%s provider url is not configured", c.name)
+	}
+	requestURL := *c.baseURL
+	requestURL.Path = path
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal %s request: %w", c.name, err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, requestURL.String(), reader)
+	if err != nil {
+		return fmt.Errorf("build %s request: %w", c.name, err)
+	}
+	if body != nil {
+		httpReq.Header.Set("content-type", "application/json")
+	}
+	for key, value := range c.headers {
+		httpReq.Header.Set(key, value)
+	}
+	if idempotencyKey != "" {
+		httpReq.Header.Set(idempotencyKeyHeader, idempotencyKey)
+	}
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return classifyTransport(ctx, fmt.Errorf("%s request failed: %w", c.name, err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return classifyStatus(resp, fmt.Errorf("%s server error status: %d", c.name, resp.StatusCode))
+	}
+	if resp.StatusCode >= 400 {
+		return classifyStatus(resp, fmt.Errorf("%s request rejected status: %d", c.name, resp.StatusCode))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s response: %w", c.name, err)
+	}
+	return nil
+}
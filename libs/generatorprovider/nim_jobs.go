@@ -0,0 +1,98 @@
+package generatorprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type nimJobResponse struct {
+	JobID    string `json:"job_id"`
+	Status   string `json:"status"`
+	Progress int    `json:"progress"`
+	Error    string `json:"error"`
+}
+
+type nimResultResponse struct {
+	AssetID    string `json:"asset_id"`
+	SourceURL  string `json:"source_url"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+func (p *nimProvider) SubmitVideo(ctx context.Context, req GenerateRequest) (Job, error) {
+	var decoded nimJobResponse
+	err := p.doKeyed(ctx, http.MethodPost, "/v1/jobs/video", req.IdempotencyKey, p.request(req), &decoded)
+	if hasStatus(err, http.StatusNotFound, http.StatusMethodNotAllowed) {
+		return p.generateVideo(ctx, req)
+	}
+	if err != nil {
+		return Job{}, err
+	}
+	if decoded.JobID == "" {
+		return Job{}, fmt.Errorf("nim job response has no job_id")
+	}
+	return decoded.job(), nil
+}
+
+func (p *nimProvider) generateVideo(ctx context.Context, req GenerateRequest) (Job, error) {
+	var decoded nimResultResponse
+	if err := p.doKeyed(ctx, http.MethodPost, "/v1/generate/video", req.IdempotencyKey, p.request(req), &decoded); err != nil {
+		return Job{}, err
+	}
+	if decoded.AssetID == "" || decoded.SourceURL == "" {
+		return Job{}, fmt.Errorf("nim video response has no asset_id or source_url")
+	}
+	return Job{
+		ID:       decoded.AssetID,
+		Status:   JobSucceeded,
+		Progress: 100,
+		Result:   &GenerateResult{AssetID: decoded.AssetID, SourceURL: decoded.SourceURL, DurationMS: decoded.DurationMS},
+	}, nil
+}
+
+func (p *nimProvider) VideoStatus(ctx context.Context, jobID string) (Job, error) {
+	var decoded nimJobResponse
+	if err := p.do(ctx, http.MethodGet, "/v1/jobs/"+url.PathEscape(jobID), nil, &decoded); err != nil {
+		return Job{}, err
+	}
+	if decoded.JobID == "" {
+		decoded.JobID = jobID
+	}
+	return decoded.job(), nil
+}
+
+func (p *nimProvider) FetchVideo(ctx context.Context, jobID string) (GenerateResult, error) {
+	var decoded nimResultResponse
+	if err := p.do(ctx, http.MethodGet, "/v1/jobs/"+url.PathEscape(jobID)+"/result", nil, &decoded); err != nil {
+		return GenerateResult{}, err
+	}
+	if decoded.AssetID == "" {
+		decoded.AssetID = jobID
+	}
+	if decoded.SourceURL == "" {
+		decoded.SourceURL = fmt.Sprintf("https://cdn.generated.local/%s.mp4", decoded.AssetID)
+	}
+	return GenerateResult{
+		AssetID:    decoded.AssetID,
+		SourceURL:  decoded.SourceURL,
+		DurationMS: decoded.DurationMS,
+	}, nil
+}
+
+func (r nimJobResponse) job() Job {
+	status := JobStatus(r.Status)
+	switch status {
+	case JobQueued, JobRunning, JobSucceeded, JobFailed:
+	default:
+		status = JobRunning
+	}
+	progress := r.Progress
+	if progress < 0 {
+		progress = 0
+	}
+	if progress > 100 {
+		progress = 100
+	}
+	return Job{ID: r.JobID, Status: status, Progress: progress, Error: r.Error}
+}
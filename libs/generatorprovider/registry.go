@@ -0,0 +1,89 @@
+package generatorprovider
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type Capabilities struct {
+	MaxDurationMS int64
+	Resolutions   []string
+	InputFields   []string
+	Steps         []string
+	Callbacks     bool
+}
+
+func (c Capabilities) SupportsStep(step string) bool {
+	for _, supported := range c.Steps {
+		if supported == step {
+			return true
+		}
+	}
+	return false
+}
+
+type Backend struct {
+	Name         string
+	Capabilities Capabilities
+	New          func(ModelProfile) (Provider, error)
+}
+
+type Registry struct {
+	mu       sync.RWMutex
+	backends map[string]Backend
+}
+
+func NewRegistry(backends ...Backend) *Registry {
+	r := &Registry{backends: map[string]Backend{}}
+	for _, backend := range backends {
+		r.Register(backend)
+	}
+	return r
+}
+
+func (r *Registry) Register(backend Backend) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.backends[backend.Name] = backend
+}
+
+func (r *Registry) Lookup(name string) (Backend, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	backend, ok := r.backends[name]
+	return backend, ok
+}
+
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.backends))
+	for name := range r.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *Registry) New(profile ModelProfile) (Provider, error) {
+	backend, ok := r.Lookup(profile.Provider)
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider: %s", profile.Provider)
+	}
+	return backend.New(profile)
+}
+
+var defaultRegistry = NewRegistry(nimBackend(), httpBackend(), fakeBackend())
+
+func Register(backend Backend) {
+	defaultRegistry.Register(backend)
+}
+
+func Lookup(name string) (Backend, bool) {
+	return defaultRegistry.Lookup(name)
+}
+
+func Providers() []string {
+	return defaultRegistry.Names()
+}
@@ -0,0 +1,43 @@
+package generatorprovider
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDefaultRegistryResolvesBuiltInBackends(t *testing.T) {
+	names := Providers()
+	if len(names) != 3 || names[0] != "http_predictions" || names[1] != "local_fake" || names[2] != "nvidia_nim" {
+		t.Fatalf("unexpected providers: %v", names)
+	}
+	for _, name := range names {
+		backend, ok := Lookup(name)
+		if !ok || backend.Capabilities.MaxDurationMS <= 0 || !backend.Capabilities.SupportsStep("nim") {
+			t.Fatalf("unexpected backend %s: %+v", name, backend)
+		}
+		if _, err := NewProvider(ModelProfile{Provider: name, BaseURL: "http://127.0.0.1:9000"}); err != nil {
+			t.Fatalf("new %s provider: %v", name, err)
+		}
+	}
+	if _, err := NewProvider(ModelProfile{Provider: "unknown"}); err == nil {
+		t.Fatal("expected unknown provider to be rejected")
+	}
+}
+
+func TestRegistryAcceptsCustomBackends(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(Backend{Name: "custom", Capabilities: Capabilities{Steps: []string{"script"}}, New: func(ModelProfile) (Provider, error) {
+		return NewFakeProvider(t.TempDir(), ""), nil
+	}})
+	provider, err := registry.New(ModelProfile{Provider: "custom"})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if _, err := provider.GenerateScript(context.Background(), GenerateRequest{RunID: "run-1"}); err != nil {
+		t.Fatalf("script: %v", err)
+	}
+	backend, _ := registry.Lookup("custom")
+	if backend.Capabilities.SupportsStep("nim") {
+		t.Fatal("expected custom backend to only support script")
+	}
+}
@@ -0,0 +1,73 @@
+package generatorprovider
+
+import (
+	"context"
+	"time"
+)
+
+type RetryPolicy struct {
+	MaxRetries int
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+type Attempt struct {
+	Number   int
+	Attempts int
+	Err      error
+	Retrying bool
+	Wait     time.Duration
+}
+
+func DefaultRetryPolicy(maxRetries int) RetryPolicy {
+	return RetryPolicy{MaxRetries: maxRetries, Initial: time.Second, Max: 30 * time.Second, Multiplier: 2}
+}
+
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.Initial
+	for i := 1; i < retry; i++ {
+		delay = time.Duration(float64(delay) * p.Multiplier)
+		if delay >= p.Max {
+			return p.Max
+		}
+	}
+	return delay
+}
+
+func (p RetryPolicy) wait(retry int, err error) time.Duration {
+	wait := max(p.backoff(retry), RetryAfter(err))
+	if p.Max > 0 {
+		wait = min(wait, p.Max)
+	}
+	return wait
+}
+
+func Retry[T any](ctx context.Context, policy RetryPolicy, call func(context.Context) (T, error), observe func(Attempt)) (T, error) {
+	attempts := policy.MaxRetries + 1
+	for number := 1; ; number++ {
+		result, err := call(ctx)
+		attempt := Attempt{Number: number, Attempts: attempts, Err: err}
+		attempt.Retrying = err != nil && number < attempts && IsRetryable(err) && ctx.Err() == nil
+		if attempt.Retrying {
+			attempt.Wait = policy.wait(number, err)
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= attempt.Wait {
+				attempt.Retrying = false
+			}
+		}
+		if observe != nil {
+			observe(attempt)
+		}
+		if !attempt.Retrying {
+			return result, err
+		}
+		timer := time.NewTimer(attempt.Wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			var zero T
+			return zero, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package generatorprovider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fastRetries(maxRetries int) RetryPolicy {
+	return RetryPolicy{MaxRetries: maxRetries, Initial: time.Millisecond, Max: 4 * time.Millisecond, Multiplier: 2}
+}
+
+func scriptServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		call := int(calls.Add(1))
+		if call <= len(statuses) {
+			if statuses[call-1] == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "1")
+			}
+			http.Error(w, "unavailable", statuses[call-1])
+			return
+		}
+		_, _ = w.Write([]byte(`{"script":"Once upon a time"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestClassifiesProviderErrors(t *testing.T) {
+	cases := map[int]bool{
+		http.StatusInternalServerError: true,
+		http.StatusBadGateway:          true,
+		http.StatusTooManyRequests:     true,
+		http.StatusRequestTimeout:      true,
+		http.StatusBadRequest:          false,
+		http.StatusUnprocessableEntity: false,
+		http.StatusUnauthorized:        false,
+	}
+	for status, retryable := range cases {
+		server, _ := scriptServer(t, status)
+		_, err := NewNIMProvider(ModelProfile{BaseURL: server.URL}).GenerateScript(context.Background(), GenerateRequest{RunID: "run-1"})
+		if err == nil || IsRetryable(err) != retryable {
+			t.Fatalf("status %d: expected retryable=%v, got %v", status, retryable, err)
+		}
+		if status == http.StatusTooManyRequests && RetryAfter(err) != time.Second {
+			t.Fatalf("expected Retry-After to be parsed, got %s", RetryAfter(err))
+		}
+	}
+	_, err := NewNIMProvider(ModelProfile{BaseURL: "http://127.0.0.1:1"}).GenerateScript(context.Background(), GenerateRequest{RunID: "run-1"})
+	if !IsRetryable(err) {
+		t.Fatalf("expected transport error to be retryable, got %v", err)
+	}
+}
+
+func TestRetryStopsAfterMaxRetries(t *testing.T) {
+	server, calls := scriptServer(t, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusServiceUnavailable)
+	provider := NewNIMProvider(ModelProfile{BaseURL: server.URL})
+	var attempts []Attempt
+	_, err := Retry(context.Background(), fastRetries(1), func(ctx context.Context) (ScriptResult, error) {
+		return provider.GenerateScript(ctx, GenerateRequest{RunID: "run-1"})
+	}, func(attempt Attempt) { attempts = append(attempts, attempt) })
+	if err == nil || calls.Load() != 2 || len(attempts) != 2 || !attempts[0].Retrying || attempts[1].Retrying {
+		t.Fatalf("expected two failed attempts, got calls=%d attempts=%+v err=%v", calls.Load(), attempts, err)
+	}
+
+	result, err := Retry(context.Background(), fastRetries(3), func(ctx context.Context) (ScriptResult, error) {
+		return provider.GenerateScript(ctx, GenerateRequest{RunID: "run-1"})
+	}, nil)
+	if err != nil || result.Script == "" || calls.Load() != 4 {
+		t.Fatalf("expected success on a later attempt, got %+v %v after %d calls", result, err, calls.Load())
+	}
+}
+
+func TestRetryDoesNotRepeatTerminalErrors(t *testing.T) {
+	terminal := errors.New("validation failed")
+	calls := 0
+	_, err := Retry(context.Background(), fastRetries(5), func(context.Context) (int, error) {
+		calls++
+		return 0, terminal
+	}, nil)
+	if !errors.Is(err, terminal) || calls != 1 {
+		t.Fatalf("expected a single terminal attempt, got %d calls: %v", calls, err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 3, 11, 10, 0, 0, 0, time.UTC)
+	if got := parseRetryAfter("7", now); got != 7*time.Second {
+		t.Fatalf("seconds: got %s", got)
+	}
+	if got := parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now); got != 90*time.Second {
+		t.Fatalf("http date: got %s", got)
+	}
+	if got := parseRetryAfter("soon", now); got != 0 {
+		t.Fatalf("garbage: got %s", got)
+	}
+}
+
+func TestRetryWaitIsCappedByPolicyAndDeadline(t *testing.T) {
+	throttled := &RetryableError{Err: errors.New("throttled"), RetryAfter: time.Hour}
+	if wait := fastRetries(3).wait(1, throttled); wait != 4*time.Millisecond {
+		t.Fatalf("expected Retry-After capped at policy max, got %s", wait)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	policy := RetryPolicy{MaxRetries: 3, Initial: time.Second, Max: time.Minute, Multiplier: 2}
+	var attempts []Attempt
+	_, err := Retry(ctx, policy, func(context.Context) (int, error) {
+		return 0, throttled
+	}, func(attempt Attempt) { attempts = append(attempts, attempt) })
+	if !errors.Is(err, throttled) || len(attempts) != 1 || attempts[0].Retrying {
+		t.Fatalf("expected no retry past the deadline, got attempts=%+v err=%v", attempts, err)
+	}
+}
+
+func TestSubmitVideoSendsIdempotencyKey(t *testing.T) {
+	var key atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key.Store(r.Header.Get("Idempotency-Key"))
+		_, _ = w.Write([]byte(`{"job_id":"job-1","status":"queued"}`))
+	}))
+	defer server.Close()
+	_, err := NewNIMProvider(ModelProfile{BaseURL: server.URL}).SubmitVideo(context.Background(), GenerateRequest{RunID: "run-1", IdempotencyKey: "run-1:2"})
+	if err != nil || key.Load() != "run-1:2" {
+		t.Fatalf("expected idempotency key header, got %v err=%v", key.Load(), err)
+	}
+}
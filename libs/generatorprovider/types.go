@@ -1,6 +1,9 @@
 package generatorprovider
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+)
 
 type ModelProfile struct {
 	Provider     string
@@ -12,8 +15,20 @@ type ModelProfile struct {
 }
 
 type GenerateRequest struct {
-	RunID        string
-	InputPayload json.RawMessage
+	RunID          string
+	InputPayload   json.RawMessage
+	Artifacts      map[string]string
+	CallbackURL    string
+	IdempotencyKey string
+}
+
+type ScriptResult struct {
+	Script string
+}
+
+type VoiceResult struct {
+	AudioURL   string
+	DurationMS int64
 }
 
 type GenerateResult struct {
@@ -23,5 +38,9 @@ type GenerateResult struct {
 }
 
 type Provider interface {
-	GenerateVideo(req GenerateRequest) (GenerateResult, error)
+	GenerateScript(ctx context.Context, req GenerateRequest) (ScriptResult, error)
+	GenerateVoice(ctx context.Context, req GenerateRequest) (VoiceResult, error)
+	SubmitVideo(ctx context.Context, req GenerateRequest) (Job, error)
+	VideoStatus(ctx context.Context, jobID string) (Job, error)
+	FetchVideo(ctx context.Context, jobID string) (GenerateResult, error)
 }
@@ -0,0 +1,111 @@
+package generatorrunstore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrRunCancelled = errors.New("workflow run cancelled")
+
+type StatusFunc func(ctx context.Context, runID string) (string, error)
+
+type Cancellations struct {
+	mu        sync.Mutex
+	status    StatusFunc
+	poll      time.Duration
+	retain    time.Duration
+	now       func() time.Time
+	cancelled map[string]time.Time
+	inFlight  map[string]map[int]context.CancelCauseFunc
+	nextID    int
+}
+
+func NewCancellations(poll time.Duration) *Cancellations {
+	return NewCancellationsWithStatus(RunStatus, poll)
+}
+
+func NewCancellationsWithStatus(status StatusFunc, poll time.Duration) *Cancellations {
+	return &Cancellations{
+		status:    status,
+		poll:      poll,
+		retain:    24 * time.Hour,
+		now:       time.Now,
+		cancelled: map[string]time.Time{},
+		inFlight:  map[string]map[int]context.CancelCauseFunc{},
+	}
+}
+
+func (c *Cancellations) Cancel(runID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for id, at := range c.cancelled {
+		if now.Sub(at) > c.retain {
+			delete(c.cancelled, id)
+		}
+	}
+	c.cancelled[runID] = now
+	for _, cancel := range c.inFlight[runID] {
+		cancel(ErrRunCancelled)
+	}
+}
+
+func (c *Cancellations) Cancelled(ctx context.Context, runID string) bool {
+	if status, err := c.status(ctx, runID); err == nil && status != "" {
+		return status == "cancelled"
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.cancelled[runID]
+	return ok
+}
+
+func (c *Cancellations) Guard(ctx context.Context, runID string) (context.Context, func()) {
+	guarded, cancel := context.WithCancelCause(ctx)
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	if c.inFlight[runID] == nil {
+		c.inFlight[runID] = map[int]context.CancelCauseFunc{}
+	}
+	c.inFlight[runID][id] = cancel
+	c.mu.Unlock()
+	stop := make(chan struct{})
+	if c.poll > 0 {
+		go c.watch(guarded, runID, stop)
+	}
+	return guarded, func() {
+		close(stop)
+		c.mu.Lock()
+		delete(c.inFlight[runID], id)
+		if len(c.inFlight[runID]) == 0 {
+			delete(c.inFlight, runID)
+		}
+		c.mu.Unlock()
+		cancel(context.Canceled)
+	}
+}
+
+func (c *Cancellations) watch(ctx context.Context, runID string, stop <-chan struct{}) {
+	ticker := time.NewTicker(c.poll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if status, err := c.status(ctx, runID); err == nil && status == "cancelled" {
+				c.Cancel(runID)
+				return
+			}
+		}
+	}
+}
+
+func IsCancelled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrRunCancelled)
+}
@@ -0,0 +1,63 @@
+package generatorrunstore
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func noStatus(context.Context, string) (string, error) {
+	return "", nil
+}
+
+func TestCancelAbortsGuardedContext(t *testing.T) {
+	cancellations := NewCancellationsWithStatus(noStatus, 0)
+	ctx, done := cancellations.Guard(context.Background(), "run-1")
+	defer done()
+	other, otherDone := cancellations.Guard(context.Background(), "run-2")
+	defer otherDone()
+
+	cancellations.Cancel("run-1")
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("expected guarded context to be cancelled")
+	}
+	if !IsCancelled(ctx) || other.Err() != nil {
+		t.Fatalf("expected only run-1 to be cancelled")
+	}
+	if !cancellations.Cancelled(context.Background(), "run-1") || cancellations.Cancelled(context.Background(), "run-2") {
+		t.Fatalf("unexpected cancellation state")
+	}
+}
+
+func TestGuardPollsRunStatus(t *testing.T) {
+	var status atomic.Value
+	status.Store("running")
+	cancellations := NewCancellationsWithStatus(func(context.Context, string) (string, error) {
+		return status.Load().(string), nil
+	}, 5*time.Millisecond)
+	ctx, done := cancellations.Guard(context.Background(), "run-1")
+	defer done()
+
+	status.Store("cancelled")
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("expected status poll to cancel guarded context")
+	}
+	if !IsCancelled(ctx) {
+		t.Fatalf("expected cancellation cause, got %v", context.Cause(ctx))
+	}
+}
+
+func TestStoredStatusOverridesCancelEvent(t *testing.T) {
+	cancellations := NewCancellationsWithStatus(func(context.Context, string) (string, error) {
+		return "running", nil
+	}, 0)
+	cancellations.Cancel("run-1")
+	if cancellations.Cancelled(context.Background(), "run-1") {
+		t.Fatalf("expected retried run status to win over an earlier cancel event")
+	}
+}
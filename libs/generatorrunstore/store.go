@@ -3,6 +3,7 @@ package generatorrunstore
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -38,6 +39,26 @@ func SetRunStatus(ctx context.Context, runID, status, lastError string) error {
 	return nil
 }
 
+func RunStatus(ctx context.Context, runID string) (string, error) {
+	handle, err := openDB()
+	if err != nil || handle == nil {
+		return "", err
+	}
+	var status string
+	err = handle.QueryRowContext(
+		ctx,
+		`select status from creator.workflow_runs where id::text = $1`,
+		runID,
+	).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("query workflow run status: %w", err)
+	}
+	return status, nil
+}
+
 func AppendRunLog(ctx context.Context, runID, step, status, message string) error {
 	handle, err := openDB()
 	if err != nil || handle == nil {
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type histogramSeries struct {
+	values []string
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+type HistogramVec struct {
+	metric  string
+	help    string
+	labels  []string
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	vec := &HistogramVec{metric: name, help: help, labels: labels, buckets: sorted, series: make(map[string]*histogramSeries)}
+	r.register(vec)
+	return vec
+}
+
+func (h *HistogramVec) name() string {
+	return h.metric
+}
+
+func (h *HistogramVec) Observe(value float64, values ...string) {
+	checkLabels(h.metric, h.labels, values)
+	key := seriesKey(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	entry, ok := h.series[key]
+	if !ok {
+		entry = &histogramSeries{values: append([]string(nil), values...), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = entry
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			entry.counts[i]++
+		}
+	}
+	entry.count++
+	entry.sum += value
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	writeHeader(w, h.metric, h.help, "histogram")
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		entry := h.series[key]
+		for i, bound := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.metric, labelString(h.labels, entry.values, "le", formatFloat(bound)), entry.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.metric, labelString(h.labels, entry.values, "le", "+Inf"), entry.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.metric, labelString(h.labels, entry.values), formatFloat(entry.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.metric, labelString(h.labels, entry.values), entry.count)
+	}
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryWritesPrometheusText(t *testing.T) {
+	registry := NewRegistry()
+	events := registry.Counter("worker_events_total", "Events handled.", "consumer", "outcome")
+	latency := registry.Histogram("worker_handler_duration_seconds", "Handler latency.", []float64{0.1, 1}, "consumer")
+	lag := registry.Gauge("worker_consumer_lag", "Pending messages.", "consumer")
+	registry.OnCollect(func() { lag.Set(7, "worker-ingest") })
+	events.Inc("worker-ingest", "processed")
+	events.Add(2, "worker-ingest", "processed")
+	events.Inc("worker-ingest", `fa"iled`)
+	latency.Observe(0.05, "worker-ingest")
+	latency.Observe(0.5, "worker-ingest")
+
+	rr := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rr.Body.String()
+	for _, want := range []string{
+		"# TYPE worker_events_total counter\n",
+		`worker_events_total{consumer="worker-ingest",outcome="processed"} 3` + "\n",
+		`worker_events_total{consumer="worker-ingest",outcome="fa\"iled"} 1` + "\n",
+		`worker_handler_duration_seconds_bucket{consumer="worker-ingest",le="0.1"} 1` + "\n",
+		`worker_handler_duration_seconds_bucket{consumer="worker-ingest",le="1"} 2` + "\n",
+		`worker_handler_duration_seconds_bucket{consumer="worker-ingest",le="+Inf"} 2` + "\n",
+		`worker_handler_duration_seconds_count{consumer="worker-ingest"} 2` + "\n",
+		`worker_consumer_lag{consumer="worker-ingest"} 7` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("missing %q in:\n%s", want, body)
+		}
+	}
+	if !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected content type %q", rr.Header().Get("Content-Type"))
+	}
+}
+
+func TestRegistryRejectsDuplicateNames(t *testing.T) {
+	registry := NewRegistry()
+	registry.Counter("dup_total", "first")
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected duplicate registration panic")
+		}
+	}()
+	registry.Counter("dup_total", "second")
+}
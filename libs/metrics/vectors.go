@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+type series struct {
+	values []string
+	value  float64
+}
+
+type scalarVec struct {
+	metric string
+	help   string
+	kind   string
+	labels []string
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+func newScalarVec(metric, help, kind string, labels []string) *scalarVec {
+	return &scalarVec{metric: metric, help: help, kind: kind, labels: labels, series: make(map[string]*series)}
+}
+
+func (v *scalarVec) name() string {
+	return v.metric
+}
+
+func (v *scalarVec) update(values []string, apply func(current float64) float64) {
+	checkLabels(v.metric, v.labels, values)
+	key := seriesKey(values)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	entry, ok := v.series[key]
+	if !ok {
+		entry = &series{values: append([]string(nil), values...)}
+		v.series[key] = entry
+	}
+	entry.value = apply(entry.value)
+}
+
+func (v *scalarVec) write(w *bufio.Writer) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	writeHeader(w, v.metric, v.help, v.kind)
+	keys := make([]string, 0, len(v.series))
+	for key := range v.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		entry := v.series[key]
+		fmt.Fprintf(w, "%s%s %s\n", v.metric, labelString(v.labels, entry.values), formatFloat(entry.value))
+	}
+}
+
+type CounterVec struct {
+	vec *scalarVec
+}
+
+func (r *Registry) Counter(name, help string, labels ...string) *CounterVec {
+	vec := newScalarVec(name, help, "counter", labels)
+	r.register(vec)
+	return &CounterVec{vec: vec}
+}
+
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *CounterVec) Add(delta float64, values ...string) {
+	if delta < 0 {
+		return
+	}
+	c.vec.update(values, func(current float64) float64 { return current + delta })
+}
+
+type GaugeVec struct {
+	vec *scalarVec
+}
+
+func (r *Registry) Gauge(name, help string, labels ...string) *GaugeVec {
+	vec := newScalarVec(name, help, "gauge", labels)
+	r.register(vec)
+	return &GaugeVec{vec: vec}
+}
+
+func (g *GaugeVec) Set(value float64, values ...string) {
+	g.vec.update(values, func(float64) float64 { return value })
+}
+
+func (g *GaugeVec) Add(delta float64, values ...string) {
+	g.vec.update(values, func(current float64) float64 { return current + delta })
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
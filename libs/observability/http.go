@@ -0,0 +1,125 @@
+package observability
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/libs/metrics"
+	"github.com/delqhi/mikasmissions/platform/libs/tracing"
+)
+
+const unmatchedRoute = "unmatched"
+
+type httpInstrumentation struct {
+	registry *metrics.Registry
+	routes   *http.ServeMux
+	next     http.Handler
+	logger   *slog.Logger
+	requests *metrics.CounterVec
+	duration *metrics.HistogramVec
+	inFlight *metrics.GaugeVec
+}
+
+func Instrument(service string, mux *http.ServeMux) http.Handler {
+	return InstrumentHandler(service, mux, mux)
+}
+
+func InstrumentHandler(service string, routes *http.ServeMux, next http.Handler) http.Handler {
+	registry := metrics.NewRegistry()
+	return &httpInstrumentation{
+		registry: registry,
+		routes:   routes,
+		next:     next,
+		logger:   NewLogger(service),
+		requests: registry.Counter("http_requests_total", "HTTP requests by route pattern and status code.", "method", "route", "code"),
+		duration: registry.Histogram("http_request_duration_seconds", "HTTP request latency in seconds.", metrics.DefaultBuckets, "method", "route"),
+		inFlight: registry.Gauge("http_requests_in_flight", "HTTP requests currently being served."),
+	}
+}
+
+func (h *httpInstrumentation) Metrics() http.Handler {
+	return h.registry.Handler()
+}
+
+func (h *httpInstrumentation) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	started := time.Now()
+	requestID := requestIDFrom(r)
+	route := h.route(r)
+	ctx := tracing.Extract(r.Context(), r.Header.Get)
+	ctx, span := tracing.Start(WithRequestID(ctx, requestID), r.Method+" "+route, tracing.SpanKindServer)
+	defer span.End()
+	r = r.WithContext(ctx)
+	r.Header.Set(RequestIDHeader, requestID)
+	w.Header().Set(RequestIDHeader, requestID)
+
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	h.inFlight.Add(1)
+	defer h.inFlight.Add(-1)
+	h.next.ServeHTTP(recorder, r)
+
+	elapsed := time.Since(started)
+	status := strconv.Itoa(recorder.status)
+	h.requests.Inc(r.Method, route, status)
+	h.duration.Observe(elapsed.Seconds(), r.Method, route)
+	span.SetAttribute("http.request.method", r.Method)
+	span.SetAttribute("http.route", route)
+	span.SetAttribute("http.response.status_code", status)
+	span.SetAttribute("request_id", requestID)
+	if recorder.status >= http.StatusInternalServerError {
+		span.RecordError(fmt.Errorf("http status %d", recorder.status))
+	}
+	if route == "/healthz" {
+		return
+	}
+	h.logger.Info("http request",
+		"request_id", requestID,
+		"trace_id", span.SpanContext().TraceID.String(),
+		"method", r.Method,
+		"route", route,
+		"path", r.URL.Path,
+		"status", recorder.status,
+		"bytes", recorder.bytes,
+		"duration_ms", elapsed.Milliseconds(),
+	)
+}
+
+func (h *httpInstrumentation) route(r *http.Request) string {
+	_, pattern := h.routes.Handler(r)
+	if pattern == "" {
+		return unmatchedRoute
+	}
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		return path
+	}
+	return pattern
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(body []byte) (int, error) {
+	r.wroteHeader = true
+	written, err := r.ResponseWriter.Write(body)
+	r.bytes += written
+	return written, err
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,89 @@
+package observability
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/catalog/episodes/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "missing" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(RequestID(r.Context())))
+	})
+	return Instrument("catalog-service", mux)
+}
+
+func TestInstrumentRecordsREDMetricsPerRoutePattern(t *testing.T) {
+	handler := newTestHandler()
+	for _, path := range []string{"/v1/catalog/episodes/ep-1", "/v1/catalog/episodes/ep-2", "/v1/catalog/episodes/missing", "/nope"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	public := httptest.NewRecorder()
+	handler.ServeHTTP(public, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if public.Code != http.StatusNotFound {
+		t.Fatalf("expected /metrics to stay off the public mux, got %d", public.Code)
+	}
+	metrics, ok := MetricsHandler(handler)
+	if !ok {
+		t.Fatalf("expected instrumented handler to expose metrics")
+	}
+	rr := httptest.NewRecorder()
+	metrics.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rr.Body.String()
+	for _, want := range []string{
+		`http_requests_total{method="GET",route="/v1/catalog/episodes/{id}",code="200"} 2`,
+		`http_requests_total{method="GET",route="/v1/catalog/episodes/{id}",code="404"} 1`,
+		`http_requests_total{method="GET",route="unmatched",code="404"} 2`,
+		`http_request_duration_seconds_count{method="GET",route="/v1/catalog/episodes/{id}"} 3`,
+		`http_requests_in_flight 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("missing %q in:\n%s", want, body)
+		}
+	}
+}
+
+func TestInstrumentPropagatesRequestID(t *testing.T) {
+	handler := newTestHandler()
+	req := httptest.NewRequest(http.MethodGet, "/v1/catalog/episodes/ep-1", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Header().Get(RequestIDHeader) != "req-123" || rr.Body.String() != "req-123" {
+		t.Fatalf("expected propagated request id, got header %q body %q", rr.Header().Get(RequestIDHeader), rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/v1/catalog/episodes/ep-1", nil)
+	req.Header.Set(RequestIDHeader, "bad id\n")
+	handler.ServeHTTP(rr, req)
+	generated := rr.Header().Get(RequestIDHeader)
+	if generated == "" || generated == "bad id\n" || rr.Body.String() != generated {
+		t.Fatalf("expected generated request id, got %q", generated)
+	}
+}
+
+func TestTransportForwardsRequestID(t *testing.T) {
+	var received string
+	upstream := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(RequestIDHeader)
+	}))
+	defer upstream.Close()
+	client := &http.Client{Transport: NewTransport(nil)}
+	req, _ := http.NewRequestWithContext(WithRequestID(context.Background(), "req-456"), http.MethodGet, upstream.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+	if received != "req-456" {
+		t.Fatalf("expected forwarded request id, got %q", received)
+	}
+}
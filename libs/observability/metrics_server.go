@@ -0,0 +1,47 @@
+package observability
+
+import (
+	"log"
+	"net/http"
+)
+
+const defaultMetricsAddr = ":9090"
+
+type metricsSource interface {
+	Metrics() http.Handler
+}
+
+func MetricsHandler(handler http.Handler) (http.Handler, bool) {
+	source, ok := handler.(metricsSource)
+	if !ok {
+		return nil, false
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", source.Metrics())
+	return mux, true
+}
+
+func MetricsAddrFromEnv(getenv func(string) string) string {
+	switch addr := getenv("METRICS_ADDR"); addr {
+	case "":
+		return defaultMetricsAddr
+	case "off":
+		return ""
+	default:
+		return addr
+	}
+}
+
+func ServeMetrics(service string, handler http.Handler, getenv func(string) string) {
+	addr := MetricsAddrFromEnv(getenv)
+	metrics, ok := MetricsHandler(handler)
+	if addr == "" || !ok {
+		return
+	}
+	go func() {
+		log.Printf("%s metrics listening on %s", service, addr)
+		if err := http.ListenAndServe(addr, metrics); err != nil {
+			log.Printf("%s metrics listener stopped: %v", service, err)
+		}
+	}()
+}
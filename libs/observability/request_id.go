@@ -0,0 +1,36 @@
+package observability
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-Id"
+
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+func requestIDFrom(r *http.Request) string {
+	incoming := r.Header.Get(RequestIDHeader)
+	if incoming == "" || len(incoming) > maxRequestIDLength {
+		return uuid.NewString()
+	}
+	for _, char := range incoming {
+		if char < 0x21 || char > 0x7e {
+			return uuid.NewString()
+		}
+	}
+	return incoming
+}
@@ -0,0 +1,43 @@
+package observability
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/delqhi/mikasmissions/platform/libs/tracing"
+)
+
+type propagatingTransport struct {
+	base http.RoundTripper
+}
+
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &propagatingTransport{base: base}
+}
+
+func (t *propagatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := tracing.Start(req.Context(), req.Method+" "+req.URL.Host, tracing.SpanKindClient)
+	defer span.End()
+	span.SetAttribute("http.request.method", req.Method)
+	span.SetAttribute("server.address", req.URL.Host)
+	span.SetAttribute("url.path", req.URL.Path)
+	outgoing := req.Clone(ctx)
+	tracing.Inject(ctx, outgoing.Header.Set)
+	if requestID := RequestID(ctx); requestID != "" && outgoing.Header.Get(RequestIDHeader) == "" {
+		outgoing.Header.Set(RequestIDHeader, requestID)
+	}
+	resp, err := t.base.RoundTrip(outgoing)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttribute("http.response.status_code", strconv.Itoa(resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.RecordError(fmt.Errorf("http status %d", resp.StatusCode))
+	}
+	return resp, nil
+}
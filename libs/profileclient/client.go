@@ -8,6 +8,8 @@ import (
 	"net/url"
 	"os"
 	"time"
+
+	"github.com/delqhi/mikasmissions/platform/libs/observability"
 )
 
 type Reader interface {
@@ -30,7 +32,7 @@ func NewFromEnv() (*Client, error) {
 		return nil, fmt.Errorf("parse PROFILE_URL: %w", err)
 	}
 	return &Client{
-		httpClient: &http.Client{Timeout: 2 * time.Second},
+		httpClient: &http.Client{Timeout: 2 * time.Second, Transport: observability.NewTransport(nil)},
 		baseURL:    parsed,
 	}, nil
 }
@@ -0,0 +1,76 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+type HealthChecker interface {
+	Ping(ctx context.Context) error
+}
+
+type LagReporter interface {
+	ConsumerLag(ctx context.Context, topic, consumer string) (int64, error)
+}
+
+func (b *InMemoryBus) Ping(context.Context) error {
+	if b.isClosed() {
+		return errBusClosed
+	}
+	return nil
+}
+
+func (b *InMemoryBus) ConsumerLag(_ context.Context, topic, consumer string) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	group, ok := b.consumers[topic][consumer]
+	if !ok {
+		return 0, nil
+	}
+	return int64(len(group.queue)), nil
+}
+
+func (b *NATSBus) Ping(context.Context) error {
+	if !b.conn.IsConnected() {
+		return fmt.Errorf("nats connection is %s", b.conn.Status())
+	}
+	return nil
+}
+
+func (b *NATSBus) ConsumerLag(ctx context.Context, topic, consumer string) (int64, error) {
+	stream, err := b.js.StreamNameBySubject(topic, nats.Context(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("resolve stream for %s: %w", topic, err)
+	}
+	info, err := b.js.ConsumerInfo(stream, consumer, nats.Context(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("read consumer %s on %s: %w", consumer, stream, err)
+	}
+	return int64(info.NumPending), nil
+}
+
+func (b *PostgresBus) Ping(ctx context.Context) error {
+	if err := b.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping bus database: %w", err)
+	}
+	return nil
+}
+
+func (b *PostgresBus) ConsumerLag(ctx context.Context, topic, consumer string) (int64, error) {
+	var lag int64
+	err := b.db.QueryRowContext(
+		ctx,
+		`select count(m.id)
+		 from events.bus_consumers c
+		 join events.bus_messages m
+		   on m.topic = c.topic and (m.tx_id, m.id) > (c.last_tx_id, c.last_message_id)
+		 where c.consumer = $1 and c.topic = $2`,
+		consumer, topic,
+	).Scan(&lag)
+	if err != nil {
+		return 0, fmt.Errorf("count bus consumer lag: %w", err)
+	}
+	return lag, nil
+}
@@ -0,0 +1,23 @@
+package queue
+
+import "strings"
+
+type BusOption func(*busConfig)
+
+type busConfig struct {
+	producer string
+}
+
+func newBusConfig(opts []BusOption) busConfig {
+	var cfg busConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+func WithProducer(producer string) BusOption {
+	return func(cfg *busConfig) {
+		cfg.producer = strings.TrimSpace(producer)
+	}
+}
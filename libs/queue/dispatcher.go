@@ -0,0 +1,63 @@
+package queue
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+type dispatcher struct {
+	lanes  []chan func()
+	shared chan func()
+	key    func(Event) string
+	wg     sync.WaitGroup
+}
+
+func newDispatcher(maxInFlight int, key func(Event) string) *dispatcher {
+	if maxInFlight <= 0 {
+		maxInFlight = 1
+	}
+	d := &dispatcher{key: key}
+	if key == nil {
+		d.shared = make(chan func())
+		for i := 0; i < maxInFlight; i++ {
+			d.start(d.shared)
+		}
+		return d
+	}
+	d.lanes = make([]chan func(), maxInFlight)
+	for i := range d.lanes {
+		d.lanes[i] = make(chan func(), 1)
+		d.start(d.lanes[i])
+	}
+	return d
+}
+
+func (d *dispatcher) start(jobs chan func()) {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		for job := range jobs {
+			job()
+		}
+	}()
+}
+
+func (d *dispatcher) submit(event Event, job func()) {
+	if d.key == nil {
+		d.shared <- job
+		return
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(d.key(event)))
+	d.lanes[hash.Sum32()%uint32(len(d.lanes))] <- job
+}
+
+func (d *dispatcher) stop() {
+	if d.shared != nil {
+		close(d.shared)
+	}
+	for _, lane := range d.lanes {
+		close(lane)
+	}
+	d.wg.Wait()
+}
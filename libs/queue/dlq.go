@@ -0,0 +1,92 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const dlqTopicSuffix = ".dlq.v1"
+
+type DLQEvent struct {
+	EventID       string          `json:"event_id"`
+	OriginalTopic string          `json:"original_topic"`
+	Consumer      string          `json:"consumer,omitempty"`
+	Error         string          `json:"error"`
+	Attempts      int             `json:"attempts"`
+	FailedAt      string          `json:"failed_at"`
+	Payload       json.RawMessage `json:"payload"`
+	TraceID       string          `json:"trace_id,omitempty"`
+}
+
+func DLQTopic(topic string) string {
+	return topic + dlqTopicSuffix
+}
+
+func IsDLQTopic(topic string) bool {
+	return strings.HasSuffix(topic, dlqTopicSuffix)
+}
+
+func DecodeDLQEvent(event Event) (DLQEvent, error) {
+	var decoded DLQEvent
+	if err := json.Unmarshal(event.Payload, &decoded); err != nil {
+		return DLQEvent{}, fmt.Errorf("decode dlq payload: %w", err)
+	}
+	if decoded.OriginalTopic == "" {
+		decoded.OriginalTopic = strings.TrimSuffix(event.Topic, dlqTopicSuffix)
+	}
+	if decoded.EventID == "" {
+		decoded.EventID = event.Headers.CausationID()
+	}
+	if decoded.TraceID == "" {
+		decoded.TraceID = event.Headers.TraceID()
+	}
+	return decoded, nil
+}
+
+func NewRedriveEvent(ctx context.Context, dead DLQEvent, eventID string) Event {
+	return stampHeaders(ctx, Event{
+		ID:      eventID,
+		Topic:   dead.OriginalTopic,
+		Payload: dead.Payload,
+		Headers: Headers{HeaderTraceID: dead.TraceID, HeaderCausationID: dead.EventID},
+	}, "")
+}
+
+func newDLQEvent(original Event, consumer string, cause error, attempts int) (Event, error) {
+	payload, err := json.Marshal(DLQEvent{
+		EventID:       original.ID,
+		OriginalTopic: original.Topic,
+		Consumer:      consumer,
+		Error:         cause.Error(),
+		Attempts:      attempts,
+		FailedAt:      time.Now().UTC().Format(time.RFC3339),
+		Payload:       dlqPayload(original.Payload),
+	})
+	if err != nil {
+		return Event{}, fmt.Errorf("marshal dlq payload: %w", err)
+	}
+	id := original.ID + "-dlq"
+	if consumer != "" {
+		id = original.ID + "-" + consumer + "-dlq"
+	}
+	return Event{
+		ID:      id,
+		Topic:   DLQTopic(original.Topic),
+		Payload: payload,
+		Headers: Headers{
+			HeaderTraceID:     original.Headers.TraceID(),
+			HeaderCausationID: original.ID,
+		},
+	}, nil
+}
+
+func dlqPayload(raw []byte) json.RawMessage {
+	if json.Valid(raw) {
+		return json.RawMessage(raw)
+	}
+	encoded, _ := json.Marshal(string(raw))
+	return json.RawMessage(encoded)
+}
@@ -0,0 +1,93 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewDLQEventKeepsOutboxEnvelopeShape(t *testing.T) {
+	event, err := newDLQEvent(
+		Event{ID: "evt-1", Topic: "media.transcoded.v1", Payload: []byte(`{"asset_id":"a-1"}`)},
+		"worker-policy",
+		errors.New("invalid renditions"),
+		5,
+	)
+	if err != nil {
+		t.Fatalf("build dlq event: %v", err)
+	}
+	if event.Topic != "media.transcoded.v1.dlq.v1" {
+		t.Fatalf("unexpected dlq topic: %s", event.Topic)
+	}
+	if event.ID != "evt-1-worker-policy-dlq" {
+		t.Fatalf("unexpected dlq event id: %s", event.ID)
+	}
+	var decoded DLQEvent
+	if err := json.Unmarshal(event.Payload, &decoded); err != nil {
+		t.Fatalf("decode dlq payload: %v", err)
+	}
+	if decoded.OriginalTopic != "media.transcoded.v1" || decoded.Attempts != 5 || decoded.Error != "invalid renditions" {
+		t.Fatalf("unexpected dlq envelope: %+v", decoded)
+	}
+	if string(decoded.Payload) != `{"asset_id":"a-1"}` {
+		t.Fatalf("unexpected original payload: %s", decoded.Payload)
+	}
+}
+
+func TestNewDLQEventWrapsNonJSONPayload(t *testing.T) {
+	event, err := newDLQEvent(Event{ID: "evt-2", Topic: "watch.event.v1", Payload: []byte("not-json")}, "", errors.New("boom"), 1)
+	if err != nil {
+		t.Fatalf("build dlq event: %v", err)
+	}
+	var decoded DLQEvent
+	if err := json.Unmarshal(event.Payload, &decoded); err != nil {
+		t.Fatalf("decode dlq payload: %v", err)
+	}
+	if string(decoded.Payload) != `"not-json"` {
+		t.Fatalf("expected quoted payload, got %s", decoded.Payload)
+	}
+}
+
+func TestDecodeDLQEventAndRedriveToOriginalTopic(t *testing.T) {
+	dead, err := newDLQEvent(
+		Event{ID: "evt-1", Topic: "media.uploaded.v1", Payload: []byte(`{"asset_id":"a-1"}`), Headers: Headers{HeaderTraceID: "trace-1"}},
+		"worker-ingest",
+		errors.New("storage down"),
+		5,
+	)
+	if err != nil {
+		t.Fatalf("build dlq event: %v", err)
+	}
+	if !IsDLQTopic(dead.Topic) || IsDLQTopic("media.uploaded.v1") {
+		t.Fatalf("unexpected dlq topic detection for %s", dead.Topic)
+	}
+	decoded, err := DecodeDLQEvent(dead)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if decoded.EventID != "evt-1" || decoded.Consumer != "worker-ingest" || decoded.Attempts != 5 {
+		t.Fatalf("unexpected decoded dlq event: %+v", decoded)
+	}
+	redrive := NewRedriveEvent(context.Background(), decoded, "evt-2")
+	if redrive.ID != "evt-2" || redrive.Topic != "media.uploaded.v1" || string(redrive.Payload) != `{"asset_id":"a-1"}` {
+		t.Fatalf("unexpected redrive event: %+v", redrive)
+	}
+	if redrive.Headers.CausationID() != "evt-1" || redrive.Headers.TraceID() != "trace-1" || redrive.Headers.SchemaVersion() != "v1" {
+		t.Fatalf("unexpected redrive headers: %+v", redrive.Headers)
+	}
+}
+
+func TestRedeliveryDelayBacksOffExponentiallyWithCap(t *testing.T) {
+	cfg := newSubscribeConfig([]SubscribeOption{WithRedeliveryBackoff(time.Second, 5*time.Second)})
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, want := range expected {
+		if got := cfg.redeliveryDelay(i + 1); got != want {
+			t.Fatalf("delivery %d: expected %s, got %s", i+1, want, got)
+		}
+	}
+	if cfg.maxDeliver != defaultMaxDeliver {
+		t.Fatalf("expected default max deliver, got %d", cfg.maxDeliver)
+	}
+}
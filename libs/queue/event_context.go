@@ -0,0 +1,72 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/libs/tracing"
+)
+
+type eventContextKey struct{}
+
+type producerContextKey struct{}
+
+type traceContextKey struct{}
+
+func ContextWithEvent(ctx context.Context, event Event) context.Context {
+	return context.WithValue(ctx, eventContextKey{}, event)
+}
+
+func EventFromContext(ctx context.Context) (Event, bool) {
+	event, ok := ctx.Value(eventContextKey{}).(Event)
+	return event, ok
+}
+
+func ContextWithProducer(ctx context.Context, producer string) context.Context {
+	return context.WithValue(ctx, producerContextKey{}, producer)
+}
+
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, traceID)
+}
+
+func messageContext(
+	ctx context.Context,
+	event Event,
+	timeout time.Duration,
+) (context.Context, context.CancelFunc) {
+	ctx = ContextWithEvent(ctx, event)
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func runHandler(
+	ctx context.Context,
+	handler Handler,
+	event Event,
+	consumer string,
+	timeout time.Duration,
+) error {
+	msgCtx, cancel := messageContext(tracing.Extract(ctx, event.Headers.Get), event, timeout)
+	defer cancel()
+	msgCtx, span := tracing.Start(msgCtx, "process "+event.Topic, tracing.SpanKindConsumer)
+	defer span.End()
+	span.SetAttribute("messaging.destination.name", event.Topic)
+	span.SetAttribute("messaging.consumer.group.name", consumer)
+	span.SetAttribute("messaging.message.id", event.ID)
+	err := handler(msgCtx, event)
+	span.RecordError(err)
+	return err
+}
+
+func producerFromContext(ctx context.Context) string {
+	producer, _ := ctx.Value(producerContextKey{}).(string)
+	return producer
+}
+
+func traceIDFromContext(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceContextKey{}).(string)
+	return traceID
+}
@@ -1,13 +1,63 @@
 package queue
 
 import (
+	"fmt"
 	"os"
+	"strings"
+
+	"github.com/delqhi/mikasmissions/platform/libs/runtimecfg"
+)
+
+const (
+	BusDriverMemory   = "memory"
+	BusDriverNATS     = "nats"
+	BusDriverPostgres = "postgres"
 )
 
-func NewBusFromEnv() (Bus, error) {
-	natsURL := os.Getenv("NATS_URL")
-	if natsURL == "" {
-		return NewInMemoryBus(), nil
+func NewBusFromEnv(opts ...BusOption) (Bus, error) {
+	return newBusFromEnv(os.Getenv, opts...)
+}
+
+func newBusFromEnv(getenv func(string) string, opts ...BusOption) (Bus, error) {
+	driver, err := busDriverFromEnv(getenv)
+	if err != nil {
+		return nil, err
+	}
+	switch driver {
+	case BusDriverNATS:
+		natsURL := getenv("NATS_URL")
+		if natsURL == "" {
+			return nil, fmt.Errorf("NATS_URL is required for bus driver %q", driver)
+		}
+		return NewNATSBus(natsURL, opts...)
+	case BusDriverPostgres:
+		databaseURL := getenv("BUS_DATABASE_URL")
+		if databaseURL == "" {
+			databaseURL = getenv("DATABASE_URL")
+		}
+		if databaseURL == "" {
+			return nil, fmt.Errorf("DATABASE_URL is required for bus driver %q", driver)
+		}
+		return NewPostgresBus(databaseURL, opts...)
+	default:
+		return NewInMemoryBus(opts...), nil
+	}
+}
+
+func busDriverFromEnv(getenv func(string) string) (string, error) {
+	driver := strings.ToLower(strings.TrimSpace(getenv("BUS_DRIVER")))
+	switch driver {
+	case BusDriverMemory, BusDriverNATS, BusDriverPostgres:
+		return driver, nil
+	case "":
+	default:
+		return "", fmt.Errorf("unsupported BUS_DRIVER %q", driver)
+	}
+	if getenv("NATS_URL") != "" {
+		return BusDriverNATS, nil
+	}
+	if runtimecfg.PersistentStorageRequiredFromEnv(getenv) {
+		return "", fmt.Errorf("BUS_DRIVER or NATS_URL is required in strict persistence mode")
 	}
-	return NewNATSBus(natsURL)
+	return BusDriverMemory, nil
 }
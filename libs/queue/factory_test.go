@@ -0,0 +1,61 @@
+package queue
+
+import (
+	"strings"
+	"testing"
+)
+
+func envMap(values map[string]string) func(string) string {
+	return func(key string) string {
+		return values[key]
+	}
+}
+
+func TestBusDriverFromEnvDefaultsToNATSWhenURLSet(t *testing.T) {
+	driver, err := busDriverFromEnv(envMap(map[string]string{"NATS_URL": "nats://nats:4222"}))
+	if err != nil || driver != BusDriverNATS {
+		t.Fatalf("expected nats driver, got %q (%v)", driver, err)
+	}
+}
+
+func TestBusDriverFromEnvFallsBackToMemoryOutsideStrictMode(t *testing.T) {
+	driver, err := busDriverFromEnv(envMap(map[string]string{}))
+	if err != nil || driver != BusDriverMemory {
+		t.Fatalf("expected memory driver, got %q (%v)", driver, err)
+	}
+}
+
+func TestBusDriverFromEnvRejectsSilentFallbackInStrictMode(t *testing.T) {
+	_, err := busDriverFromEnv(envMap(map[string]string{"GO_ENV": "production"}))
+	if err == nil || !strings.Contains(err.Error(), "strict persistence mode") {
+		t.Fatalf("expected strict mode error, got %v", err)
+	}
+}
+
+func TestBusDriverFromEnvHonorsExplicitDriver(t *testing.T) {
+	driver, err := busDriverFromEnv(envMap(map[string]string{"BUS_DRIVER": "Postgres", "NATS_URL": "nats://nats:4222"}))
+	if err != nil || driver != BusDriverPostgres {
+		t.Fatalf("expected postgres driver, got %q (%v)", driver, err)
+	}
+	if _, err := busDriverFromEnv(envMap(map[string]string{"BUS_DRIVER": "kafka"})); err == nil {
+		t.Fatalf("expected unsupported driver error")
+	}
+}
+
+func TestNewBusFromEnvPostgresRequiresDatabaseURL(t *testing.T) {
+	_, err := newBusFromEnv(envMap(map[string]string{"BUS_DRIVER": "postgres"}))
+	if err == nil || !strings.Contains(err.Error(), "DATABASE_URL is required") {
+		t.Fatalf("expected database url error, got %v", err)
+	}
+}
+
+func TestNewBusFromEnvStampsConfiguredProducer(t *testing.T) {
+	bus, err := newBusFromEnv(envMap(map[string]string{}), WithProducer("progress-service"))
+	if err != nil {
+		t.Fatalf("new bus: %v", err)
+	}
+	memory, ok := bus.(*InMemoryBus)
+	if !ok || memory.producer != "progress-service" {
+		t.Fatalf("expected in-memory bus stamped as progress-service, got %T", bus)
+	}
+}
@@ -0,0 +1,103 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInMemoryBusHandlerContextCarriesDeadlineAndEvent(t *testing.T) {
+	bus := NewInMemoryBus()
+	defer bus.Close()
+	type observed struct {
+		deadline time.Duration
+		eventID  string
+	}
+	seen := make(chan observed, 1)
+	_ = bus.Subscribe(context.Background(), "video.generation.requested.v1", "worker-gen-nim", func(ctx context.Context, event Event) error {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Errorf("handler context must carry a deadline")
+		}
+		parent, _ := EventFromContext(ctx)
+		seen <- observed{deadline: time.Until(deadline), eventID: parent.ID}
+		return nil
+	}, WithAckWait(time.Minute), WithHandlerTimeout(10*time.Second))
+	_ = bus.Publish(context.Background(), Event{ID: "evt-1", Topic: "video.generation.requested.v1"})
+	got := <-seen
+	if got.deadline <= 0 || got.deadline > 10*time.Second {
+		t.Fatalf("expected handler timeout to bound the deadline, got %s", got.deadline)
+	}
+	if got.eventID != "evt-1" {
+		t.Fatalf("unexpected handler context values: %+v", got)
+	}
+}
+
+func TestInMemoryBusSubscriptionCancelReachesHandler(t *testing.T) {
+	bus := NewInMemoryBus()
+	defer bus.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	var dead atomic.Int32
+	_ = bus.Subscribe(ctx, "video.generation.requested.v1", "worker-gen-nim", func(ctx context.Context, _ Event) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithMaxDeliver(1))
+	_ = bus.Subscribe(context.Background(), DLQTopic("video.generation.requested.v1"), "test-dlq", func(context.Context, Event) error {
+		dead.Add(1)
+		return nil
+	})
+	_ = bus.Publish(context.Background(), Event{ID: "evt-1", Topic: "video.generation.requested.v1"})
+	<-started
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		_ = bus.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("close must return once the cancelled handler exits")
+	}
+	if dead.Load() != 0 {
+		t.Fatalf("shutdown cancellation must not dead-letter the event")
+	}
+}
+
+func TestInMemoryBusCloseDrainsInFlightHandlers(t *testing.T) {
+	bus := NewInMemoryBus()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var finished atomic.Bool
+	_ = bus.Subscribe(context.Background(), "media.uploaded.v1", "worker-ingest", func(context.Context, Event) error {
+		close(started)
+		<-release
+		finished.Store(true)
+		return nil
+	})
+	_ = bus.Publish(context.Background(), Event{ID: "evt-1", Topic: "media.uploaded.v1"})
+	<-started
+	closed := make(chan error, 1)
+	go func() {
+		closed <- bus.Close()
+	}()
+	select {
+	case <-closed:
+		t.Fatalf("close returned before the in-flight handler finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	if err := <-closed; err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if !finished.Load() {
+		t.Fatalf("expected handler to finish before close returned")
+	}
+	if err := bus.Publish(context.Background(), Event{ID: "evt-2", Topic: "media.uploaded.v1"}); !errors.Is(err, errBusClosed) {
+		t.Fatalf("expected publish after close to fail, got %v", err)
+	}
+}
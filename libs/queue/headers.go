@@ -0,0 +1,105 @@
+package queue
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/libs/tracing"
+)
+
+const (
+	HeaderTraceID       = "Mm-Trace-Id"
+	HeaderCausationID   = "Mm-Causation-Id"
+	HeaderProducer      = "Mm-Producer"
+	HeaderSchemaVersion = "Mm-Schema-Version"
+	HeaderOccurredAt    = "Mm-Occurred-At"
+	HeaderOrderingKey   = "Mm-Ordering-Key"
+	HeaderTraceparent   = tracing.TraceparentHeader
+)
+
+type Headers map[string]string
+
+func (h Headers) Get(key string) string {
+	if h == nil {
+		return ""
+	}
+	return h[key]
+}
+
+func (h Headers) TraceID() string {
+	return h.Get(HeaderTraceID)
+}
+
+func (h Headers) Traceparent() string {
+	return h.Get(HeaderTraceparent)
+}
+
+func (h Headers) CausationID() string {
+	return h.Get(HeaderCausationID)
+}
+
+func (h Headers) Producer() string {
+	return h.Get(HeaderProducer)
+}
+
+func (h Headers) SchemaVersion() string {
+	return h.Get(HeaderSchemaVersion)
+}
+
+func (h Headers) OrderingKey() string {
+	return h.Get(HeaderOrderingKey)
+}
+
+func (h Headers) OccurredAt() (time.Time, bool) {
+	parsed, err := time.Parse(time.RFC3339Nano, h.Get(HeaderOccurredAt))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return parsed, true
+}
+
+func (h Headers) Clone() Headers {
+	cloned := make(Headers, len(h)+5)
+	for key, value := range h {
+		cloned[key] = value
+	}
+	return cloned
+}
+
+func (h Headers) setDefault(key, value string) {
+	if value != "" && h[key] == "" {
+		h[key] = value
+	}
+}
+
+func stampHeaders(ctx context.Context, event Event, producer string) Event {
+	headers := event.Headers.Clone()
+	tracing.Inject(ctx, headers.setDefault)
+	if parent, ok := EventFromContext(ctx); ok {
+		headers.setDefault(HeaderTraceparent, parent.Headers.Traceparent())
+		headers.setDefault(HeaderTraceID, parent.Headers.TraceID())
+		headers.setDefault(HeaderTraceID, parent.ID)
+		headers.setDefault(HeaderCausationID, parent.ID)
+	}
+	headers.setDefault(HeaderTraceID, traceIDFromContext(ctx))
+	headers.setDefault(HeaderTraceID, event.ID)
+	headers.setDefault(HeaderProducer, producerFromContext(ctx))
+	headers.setDefault(HeaderProducer, producer)
+	headers.setDefault(HeaderSchemaVersion, schemaVersionFromTopic(event.Topic))
+	headers.setDefault(HeaderOccurredAt, time.Now().UTC().Format(time.RFC3339Nano))
+	event.Headers = headers
+	return event
+}
+
+func schemaVersionFromTopic(topic string) string {
+	index := strings.LastIndex(topic, ".")
+	if index < 0 || index == len(topic)-1 {
+		return ""
+	}
+	suffix := topic[index+1:]
+	if len(suffix) < 2 || suffix[0] != 'v' || strings.Trim(suffix[1:], "0123456789") != "" {
+		return ""
+	}
+	return suffix
+}
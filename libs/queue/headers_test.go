@@ -0,0 +1,117 @@
+package queue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/delqhi/mikasmissions/platform/libs/tracing"
+)
+
+func TestInMemoryBusPropagatesHeadersDownstream(t *testing.T) {
+	bus := NewInMemoryBus(WithProducer("worker-gen-nim"))
+	var final Event
+	_ = bus.Subscribe(context.Background(), "video.run.requested.v1", "worker-gen-nim-steps", func(ctx context.Context, event Event) error {
+		return bus.Publish(ctx, Event{ID: "evt-ready", Topic: "video.asset.ready.v1", Payload: []byte(`{}`)})
+	})
+	_ = bus.Subscribe(context.Background(), "video.asset.ready.v1", "worker-gen-qc", func(_ context.Context, event Event) error {
+		final = event
+		return nil
+	})
+	ctx := ContextWithTraceID(context.Background(), "run-1")
+	if err := bus.Publish(ctx, Event{ID: "evt-run", Topic: "video.run.requested.v1", Payload: []byte(`{}`)}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if err := bus.WaitIdle(context.Background()); err != nil {
+		t.Fatalf("wait idle: %v", err)
+	}
+	if final.Headers.TraceID() != "run-1" {
+		t.Fatalf("expected trace id run-1, got %q", final.Headers.TraceID())
+	}
+	if final.Headers.CausationID() != "evt-run" {
+		t.Fatalf("expected causation id evt-run, got %q", final.Headers.CausationID())
+	}
+	if final.Headers.Producer() != "worker-gen-nim" {
+		t.Fatalf("expected producer worker-gen-nim, got %q", final.Headers.Producer())
+	}
+	if final.Headers.SchemaVersion() != "v1" {
+		t.Fatalf("expected schema version v1, got %q", final.Headers.SchemaVersion())
+	}
+	if _, ok := final.Headers.OccurredAt(); !ok {
+		t.Fatalf("expected occurred-at header")
+	}
+}
+
+func TestStampHeadersKeepsExplicitValues(t *testing.T) {
+	event := stampHeaders(context.Background(), Event{
+		ID:      "evt-1",
+		Topic:   "watch.event.v1",
+		Headers: Headers{HeaderTraceID: "trace-explicit", HeaderSchemaVersion: "v2", HeaderProducer: "event-probe"},
+	}, "worker-gen-nim")
+	if event.Headers.TraceID() != "trace-explicit" || event.Headers.SchemaVersion() != "v2" || event.Headers.Producer() != "event-probe" {
+		t.Fatalf("explicit headers overwritten: %+v", event.Headers)
+	}
+}
+
+func TestNATSHeadersRoundTrip(t *testing.T) {
+	msg := natsMsgFromEvent(Event{
+		ID:      "evt-1",
+		Topic:   "media.uploaded.v1",
+		Headers: Headers{HeaderTraceID: "trace-1", HeaderProducer: "creator-studio-service"},
+	})
+	if msg.Header.Get("Nats-Msg-Id") != "evt-1" {
+		t.Fatalf("expected msg id header")
+	}
+	headers := headersFromNATSMsg(msg)
+	if headers.TraceID() != "trace-1" || headers.Producer() != "creator-studio-service" {
+		t.Fatalf("unexpected decoded headers: %+v", headers)
+	}
+	if _, ok := headers["Nats-Msg-Id"]; ok {
+		t.Fatalf("nats internal headers must not leak into event headers")
+	}
+}
+
+func TestSchemaVersionFromTopic(t *testing.T) {
+	cases := map[string]string{
+		"episode.published.v1":     "v1",
+		"media.uploaded.v1.dlq.v1": "v1",
+		"analytics.rollup":         "",
+		"custom.vx":                "",
+	}
+	for topic, want := range cases {
+		if got := schemaVersionFromTopic(topic); got != want {
+			t.Fatalf("%s: expected %q, got %q", topic, want, got)
+		}
+	}
+}
+
+func TestTraceparentFollowsEventsAcrossHandlers(t *testing.T) {
+	bus := NewInMemoryBus()
+	defer bus.Close()
+	ctx, span := tracing.Start(context.Background(), "POST /v1/playback/sessions", tracing.SpanKindServer)
+	defer span.End()
+	received := make(chan Event, 1)
+	if err := bus.Subscribe(context.Background(), "playback.session.started.v1", "worker-analytics", func(ctx context.Context, event Event) error {
+		return bus.Publish(ctx, Event{ID: "evt-2", Topic: "analytics.rollup.v1"})
+	}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if err := bus.Subscribe(context.Background(), "analytics.rollup.v1", "worker-rollup", func(_ context.Context, event Event) error {
+		received <- event
+		return nil
+	}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if err := bus.Publish(ctx, Event{ID: "evt-1", Topic: "playback.session.started.v1"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	downstream := <-received
+	sc, ok := tracing.ParseTraceparent(downstream.Headers.Traceparent())
+	if !ok || sc.TraceID != span.SpanContext().TraceID || sc.SpanID == span.SpanContext().SpanID {
+		t.Fatalf("expected child span of %s, got %q", span.SpanContext().Traceparent(), downstream.Headers.Traceparent())
+	}
+
+	msg := natsMsgFromEvent(downstream)
+	if headersFromNATSMsg(msg).Traceparent() != downstream.Headers.Traceparent() {
+		t.Fatalf("traceparent lost in nats headers: %+v", msg.Header)
+	}
+}
@@ -1,30 +1,47 @@
 package queue
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
-	"sync"
 	"time"
 
 	"github.com/delqhi/mikasmissions/platform/libs/runtimecfg"
 )
 
+type IdempotencyState string
+
+const (
+	IdempotencyAcquired   IdempotencyState = "acquired"
+	IdempotencyInProgress IdempotencyState = "in_progress"
+	IdempotencyCompleted  IdempotencyState = "completed"
+)
+
+var ErrIdempotencyInProgress = errors.New("event is being processed by another handler")
+
 type idempotencyStore interface {
-	Seen(eventID string) bool
+	Begin(ctx context.Context, eventID string, lease time.Duration) (IdempotencyState, time.Time, error)
+	Extend(ctx context.Context, eventID string, lease time.Duration) error
+	Complete(ctx context.Context, eventID string) error
+	Release(ctx context.Context, eventID string) error
 }
 
 type IdempotencyGuard struct {
 	store idempotencyStore
+	cfg   idempotencyConfig
 }
 
-func NewIdempotencyGuard() *IdempotencyGuard {
-	return &IdempotencyGuard{store: newMemoryIdempotencyStore()}
+func NewIdempotencyGuard(opts ...IdempotencyOption) *IdempotencyGuard {
+	cfg := newIdempotencyConfig(opts)
+	return &IdempotencyGuard{store: newMemoryIdempotencyStore(cfg), cfg: cfg}
 }
 
-func NewScopedIdempotencyGuard(consumerScope string) *IdempotencyGuard {
+func NewScopedIdempotencyGuard(consumerScope string, opts ...IdempotencyOption) *IdempotencyGuard {
+	opts = append(IdempotencyOptionsFromEnv(os.Getenv), opts...)
 	if consumerScope == "" {
-		return NewIdempotencyGuard()
+		return NewIdempotencyGuard(opts...)
 	}
 	strict := runtimecfg.PersistentStorageRequired()
 	databaseURL := os.Getenv("DATABASE_URL")
@@ -32,45 +49,83 @@ func NewScopedIdempotencyGuard(consumerScope string) *IdempotencyGuard {
 		if strict {
 			panic("DATABASE_URL is required for scoped idempotency guard in strict persistence mode")
 		}
-		return NewIdempotencyGuard()
+		return NewIdempotencyGuard(opts...)
 	}
-	store, err := newPostgresIdempotencyStore(databaseURL, consumerScope, 7*24*time.Hour)
+	cfg := newIdempotencyConfig(opts)
+	store, err := newPostgresIdempotencyStore(databaseURL, consumerScope, cfg)
 	if err != nil {
 		if strict {
 			panic(fmt.Sprintf("open scoped idempotency store: %v", err))
 		}
-		return NewIdempotencyGuard()
+		return NewIdempotencyGuard(opts...)
 	}
-	return &IdempotencyGuard{store: store}
+	return &IdempotencyGuard{store: store, cfg: cfg}
 }
 
-func (g *IdempotencyGuard) Seen(eventID string) bool {
-	return g.store.Seen(eventID)
+func (g *IdempotencyGuard) Begin(ctx context.Context, eventID string) (IdempotencyState, error) {
+	if eventID == "" {
+		return IdempotencyAcquired, nil
+	}
+	state, _, err := g.store.Begin(ctx, eventID, g.leaseFor(ctx))
+	return state, err
 }
 
-func (g *IdempotencyGuard) Close() error {
-	closer, ok := g.store.(io.Closer)
-	if !ok {
+func (g *IdempotencyGuard) Complete(ctx context.Context, eventID string) error {
+	if eventID == "" {
 		return nil
 	}
-	return closer.Close()
+	return g.store.Complete(ctx, eventID)
 }
 
-type memoryIdempotencyStore struct {
-	mu   sync.Mutex
-	seen map[string]struct{}
+func (g *IdempotencyGuard) Fail(ctx context.Context, eventID string) error {
+	if eventID == "" {
+		return nil
+	}
+	return g.store.Release(ctx, eventID)
 }
 
-func newMemoryIdempotencyStore() *memoryIdempotencyStore {
-	return &memoryIdempotencyStore{seen: make(map[string]struct{})}
+func (g *IdempotencyGuard) Do(ctx context.Context, eventID string, handle func(context.Context) error) (bool, error) {
+	if eventID == "" {
+		return false, handle(ctx)
+	}
+	lease := g.leaseFor(ctx)
+	state, expiresAt, err := g.store.Begin(ctx, eventID, lease)
+	if err != nil {
+		return false, fmt.Errorf("begin idempotent handling of %s: %w", eventID, err)
+	}
+	switch state {
+	case IdempotencyCompleted:
+		return true, nil
+	case IdempotencyInProgress:
+		return false, &InProgressError{EventID: eventID, RetryIn: expiresAt.Sub(g.cfg.now())}
+	}
+	stop := g.keepAlive(ctx, eventID, lease)
+	err = handle(ctx)
+	stop()
+	if err != nil {
+		if releaseErr := g.Fail(context.WithoutCancel(ctx), eventID); releaseErr != nil {
+			return false, errors.Join(err, fmt.Errorf("release idempotency lease of %s: %w", eventID, releaseErr))
+		}
+		return false, err
+	}
+	if err := g.Complete(context.WithoutCancel(ctx), eventID); err != nil {
+		return false, fmt.Errorf("complete idempotent handling of %s: %w", eventID, err)
+	}
+	return false, nil
+}
+
+func (g *IdempotencyGuard) Close() error {
+	closer, ok := g.store.(io.Closer)
+	if !ok {
+		return nil
+	}
+	return closer.Close()
 }
 
-func (s *memoryIdempotencyStore) Seen(eventID string) bool {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	if _, ok := s.seen[eventID]; ok {
-		return true
+func (g *IdempotencyGuard) Ping(ctx context.Context) error {
+	checker, ok := g.store.(HealthChecker)
+	if !ok {
+		return nil
 	}
-	s.seen[eventID] = struct{}{}
-	return false
+	return checker.Ping(ctx)
 }
@@ -0,0 +1,112 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type InProgressError struct {
+	EventID string
+	RetryIn time.Duration
+}
+
+func (e *InProgressError) Error() string {
+	return fmt.Sprintf("handle %s: %v", e.EventID, ErrIdempotencyInProgress)
+}
+
+func (e *InProgressError) Unwrap() error {
+	return ErrIdempotencyInProgress
+}
+
+func inProgressDelay(err error, fallback time.Duration) (time.Duration, bool) {
+	if !errors.Is(err, ErrIdempotencyInProgress) {
+		return 0, false
+	}
+	var inProgress *InProgressError
+	if errors.As(err, &inProgress) && inProgress.RetryIn > 0 {
+		return inProgress.RetryIn, true
+	}
+	return fallback, true
+}
+
+type heartbeatContextKey struct{}
+
+type leaseHeartbeat struct {
+	mu      sync.Mutex
+	ackWait time.Duration
+	next    int
+	beats   map[int]func()
+}
+
+func newLeaseHeartbeat(ackWait time.Duration) *leaseHeartbeat {
+	return &leaseHeartbeat{ackWait: ackWait, beats: make(map[int]func())}
+}
+
+func contextWithHeartbeat(ctx context.Context, heartbeat *leaseHeartbeat) context.Context {
+	return context.WithValue(ctx, heartbeatContextKey{}, heartbeat)
+}
+
+func heartbeatFromContext(ctx context.Context) (*leaseHeartbeat, bool) {
+	heartbeat, ok := ctx.Value(heartbeatContextKey{}).(*leaseHeartbeat)
+	return heartbeat, ok && heartbeat != nil
+}
+
+func (h *leaseHeartbeat) register(beat func()) func() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	id := h.next
+	h.next++
+	h.beats[id] = beat
+	return func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.beats, id)
+	}
+}
+
+func (h *leaseHeartbeat) beat() {
+	h.mu.Lock()
+	beats := make([]func(), 0, len(h.beats))
+	for _, beat := range h.beats {
+		beats = append(beats, beat)
+	}
+	h.mu.Unlock()
+	for _, beat := range beats {
+		beat()
+	}
+}
+
+func (g *IdempotencyGuard) leaseFor(ctx context.Context) time.Duration {
+	if heartbeat, ok := heartbeatFromContext(ctx); ok && heartbeat.ackWait > 0 {
+		return heartbeat.ackWait
+	}
+	return g.cfg.lease
+}
+
+func (g *IdempotencyGuard) keepAlive(ctx context.Context, eventID string, lease time.Duration) func() {
+	extend := func() {
+		_ = g.store.Extend(context.WithoutCancel(ctx), eventID, lease)
+	}
+	if heartbeat, ok := heartbeatFromContext(ctx); ok {
+		return heartbeat.register(extend)
+	}
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(lease / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				extend()
+			}
+		}
+	}()
+	return func() {
+		close(done)
+	}
+}
@@ -0,0 +1,111 @@
+package queue
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryIdempotencyEntry struct {
+	eventID   string
+	state     IdempotencyState
+	expiresAt time.Time
+}
+
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	cfg     idempotencyConfig
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+func newMemoryIdempotencyStore(cfg idempotencyConfig) *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{cfg: cfg, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+func (s *memoryIdempotencyStore) Begin(
+	_ context.Context,
+	eventID string,
+	lease time.Duration,
+) (IdempotencyState, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.cfg.now()
+	s.evict(now)
+	if element, ok := s.entries[eventID]; ok {
+		entry := element.Value.(*memoryIdempotencyEntry)
+		if now.Before(entry.expiresAt) {
+			if entry.state == IdempotencyCompleted {
+				return IdempotencyCompleted, entry.expiresAt, nil
+			}
+			return IdempotencyInProgress, entry.expiresAt, nil
+		}
+		s.remove(element)
+	}
+	expiresAt := now.Add(s.leaseOrDefault(lease))
+	s.touch(eventID, IdempotencyInProgress, expiresAt)
+	for s.order.Len() > s.cfg.maxEntries {
+		s.remove(s.order.Front())
+	}
+	return IdempotencyAcquired, expiresAt, nil
+}
+
+func (s *memoryIdempotencyStore) Extend(_ context.Context, eventID string, lease time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, ok := s.entries[eventID]; ok && element.Value.(*memoryIdempotencyEntry).state == IdempotencyInProgress {
+		s.touch(eventID, IdempotencyInProgress, s.cfg.now().Add(s.leaseOrDefault(lease)))
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Complete(_ context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.touch(eventID, IdempotencyCompleted, s.cfg.now().Add(s.cfg.ttl))
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, ok := s.entries[eventID]; ok && element.Value.(*memoryIdempotencyEntry).state == IdempotencyInProgress {
+		s.remove(element)
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+func (s *memoryIdempotencyStore) touch(eventID string, state IdempotencyState, expiresAt time.Time) {
+	if element, ok := s.entries[eventID]; ok {
+		s.remove(element)
+	}
+	s.entries[eventID] = s.order.PushBack(&memoryIdempotencyEntry{eventID: eventID, state: state, expiresAt: expiresAt})
+}
+
+func (s *memoryIdempotencyStore) leaseOrDefault(lease time.Duration) time.Duration {
+	if lease > 0 {
+		return lease
+	}
+	return s.cfg.lease
+}
+
+func (s *memoryIdempotencyStore) evict(now time.Time) {
+	for element := s.order.Front(); element != nil; element = s.order.Front() {
+		if now.Before(element.Value.(*memoryIdempotencyEntry).expiresAt) {
+			return
+		}
+		s.remove(element)
+	}
+}
+
+func (s *memoryIdempotencyStore) remove(element *list.Element) {
+	delete(s.entries, element.Value.(*memoryIdempotencyEntry).eventID)
+	s.order.Remove(element)
+}
@@ -0,0 +1,69 @@
+package queue
+
+import "time"
+
+const (
+	defaultIdempotencyLease      = 5 * time.Minute
+	defaultIdempotencyTTL        = 7 * 24 * time.Hour
+	defaultIdempotencyMaxEntries = 100000
+)
+
+type IdempotencyOption func(*idempotencyConfig)
+
+type idempotencyConfig struct {
+	lease      time.Duration
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+}
+
+func newIdempotencyConfig(opts []IdempotencyOption) idempotencyConfig {
+	cfg := idempotencyConfig{
+		lease:      defaultIdempotencyLease,
+		ttl:        defaultIdempotencyTTL,
+		maxEntries: defaultIdempotencyMaxEntries,
+		now:        func() time.Time { return time.Now().UTC() },
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+func WithIdempotencyLease(lease time.Duration) IdempotencyOption {
+	return func(cfg *idempotencyConfig) {
+		if lease > 0 {
+			cfg.lease = lease
+		}
+	}
+}
+
+func WithIdempotencyTTL(ttl time.Duration) IdempotencyOption {
+	return func(cfg *idempotencyConfig) {
+		if ttl > 0 {
+			cfg.ttl = ttl
+		}
+	}
+}
+
+func WithIdempotencyMaxEntries(maxEntries int) IdempotencyOption {
+	return func(cfg *idempotencyConfig) {
+		if maxEntries > 0 {
+			cfg.maxEntries = maxEntries
+		}
+	}
+}
+
+func IdempotencyOptionsFromEnv(getenv func(string) string) []IdempotencyOption {
+	var opts []IdempotencyOption
+	if value, ok := positiveIntEnv(getenv, "IDEMPOTENCY_LEASE_MS"); ok {
+		opts = append(opts, WithIdempotencyLease(time.Duration(value)*time.Millisecond))
+	}
+	if value, ok := positiveIntEnv(getenv, "IDEMPOTENCY_TTL_MS"); ok {
+		opts = append(opts, WithIdempotencyTTL(time.Duration(value)*time.Millisecond))
+	}
+	if value, ok := positiveIntEnv(getenv, "IDEMPOTENCY_MAX_ENTRIES"); ok {
+		opts = append(opts, WithIdempotencyMaxEntries(value))
+	}
+	return opts
+}
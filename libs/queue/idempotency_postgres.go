@@ -1,7 +1,9 @@
 package queue
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,13 +13,13 @@ import (
 type postgresIdempotencyStore struct {
 	db            *sql.DB
 	consumerScope string
-	ttl           time.Duration
+	cfg           idempotencyConfig
 }
 
 func newPostgresIdempotencyStore(
 	databaseURL string,
 	consumerScope string,
-	ttl time.Duration,
+	cfg idempotencyConfig,
 ) (*postgresIdempotencyStore, error) {
 	db, err := sql.Open("pgx", databaseURL)
 	if err != nil {
@@ -30,32 +32,122 @@ func newPostgresIdempotencyStore(
 	return &postgresIdempotencyStore{
 		db:            db,
 		consumerScope: consumerScope,
-		ttl:           ttl,
+		cfg:           cfg,
 	}, nil
 }
 
-func (s *postgresIdempotencyStore) Seen(eventID string) bool {
-	if eventID == "" {
-		return false
+func (s *postgresIdempotencyStore) Begin(
+	ctx context.Context,
+	eventID string,
+	lease time.Duration,
+) (IdempotencyState, time.Time, error) {
+	now := s.cfg.now()
+	expiresAt := now.Add(s.leaseOrDefault(lease))
+	var acquired bool
+	err := s.db.QueryRowContext(
+		ctx,
+		`insert into events.idempotency_keys (consumer_scope, event_id, status, expires_at, updated_at)
+		 values ($1, $2, 'processing', $3, $4)
+		 on conflict (consumer_scope, event_id) do update
+		    set status = 'processing', expires_at = excluded.expires_at, updated_at = excluded.updated_at
+		  where events.idempotency_keys.expires_at <= excluded.updated_at
+		 returning true`,
+		s.consumerScope,
+		eventID,
+		expiresAt,
+		now,
+	).Scan(&acquired)
+	if err == nil {
+		return IdempotencyAcquired, expiresAt, nil
 	}
-	result, err := s.db.Exec(
-		`insert into events.idempotency_keys (consumer_scope, event_id, expires_at)
-		 values ($1, $2, $3)
-		 on conflict (consumer_scope, event_id) do nothing`,
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", time.Time{}, fmt.Errorf("claim idempotency key: %w", err)
+	}
+	var status string
+	var heldUntil time.Time
+	err = s.db.QueryRowContext(
+		ctx,
+		`select status, expires_at from events.idempotency_keys where consumer_scope = $1 and event_id = $2`,
 		s.consumerScope,
 		eventID,
-		time.Now().UTC().Add(s.ttl),
+	).Scan(&status, &heldUntil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return IdempotencyInProgress, time.Time{}, nil
+	}
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("read idempotency key: %w", err)
+	}
+	if status == "completed" {
+		return IdempotencyCompleted, heldUntil, nil
+	}
+	return IdempotencyInProgress, heldUntil, nil
+}
+
+func (s *postgresIdempotencyStore) Extend(ctx context.Context, eventID string, lease time.Duration) error {
+	now := s.cfg.now()
+	_, err := s.db.ExecContext(
+		ctx,
+		`update events.idempotency_keys
+		    set expires_at = $3, updated_at = $4
+		  where consumer_scope = $1 and event_id = $2 and status = 'processing'`,
+		s.consumerScope,
+		eventID,
+		now.Add(s.leaseOrDefault(lease)),
+		now,
 	)
 	if err != nil {
-		return false
+		return fmt.Errorf("extend idempotency lease: %w", err)
 	}
-	affected, err := result.RowsAffected()
+	return nil
+}
+
+func (s *postgresIdempotencyStore) leaseOrDefault(lease time.Duration) time.Duration {
+	if lease > 0 {
+		return lease
+	}
+	return s.cfg.lease
+}
+
+func (s *postgresIdempotencyStore) Complete(ctx context.Context, eventID string) error {
+	now := s.cfg.now()
+	_, err := s.db.ExecContext(
+		ctx,
+		`insert into events.idempotency_keys (consumer_scope, event_id, status, expires_at, updated_at)
+		 values ($1, $2, 'completed', $3, $4)
+		 on conflict (consumer_scope, event_id) do update
+		    set status = 'completed', expires_at = excluded.expires_at, updated_at = excluded.updated_at`,
+		s.consumerScope,
+		eventID,
+		now.Add(s.cfg.ttl),
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresIdempotencyStore) Release(ctx context.Context, eventID string) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		`delete from events.idempotency_keys
+		  where consumer_scope = $1 and event_id = $2 and status = 'processing'`,
+		s.consumerScope,
+		eventID,
+	)
 	if err != nil {
-		return false
+		return fmt.Errorf("release idempotency key: %w", err)
 	}
-	return affected == 0
+	return nil
 }
 
 func (s *postgresIdempotencyStore) Close() error {
 	return s.db.Close()
 }
+
+func (s *postgresIdempotencyStore) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping idempotency database: %w", err)
+	}
+	return nil
+}
@@ -1,24 +1,113 @@
 package queue
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
 
-func TestIdempotencyGuardSeenOnce(t *testing.T) {
+func TestIdempotencyGuardSkipsCompletedEvent(t *testing.T) {
 	guard := NewIdempotencyGuard()
-	if guard.Seen("evt-1") {
-		t.Fatalf("first occurrence should not be marked as duplicate")
+	calls := 0
+	handle := func(context.Context) error {
+		calls++
+		return nil
 	}
-	if !guard.Seen("evt-1") {
-		t.Fatalf("second occurrence should be marked as duplicate")
+	if duplicate, err := guard.Do(context.Background(), "evt-1", handle); duplicate || err != nil {
+		t.Fatalf("first occurrence: duplicate=%v err=%v", duplicate, err)
+	}
+	if duplicate, err := guard.Do(context.Background(), "evt-1", handle); !duplicate || err != nil {
+		t.Fatalf("second occurrence: duplicate=%v err=%v", duplicate, err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected one handler call, got %d", calls)
+	}
+}
+
+func TestIdempotencyGuardReleasesFailedEventForRetry(t *testing.T) {
+	guard := NewIdempotencyGuard()
+	boom := errors.New("catalog unavailable")
+	if _, err := guard.Do(context.Background(), "evt-1", func(context.Context) error { return boom }); !errors.Is(err, boom) {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+	calls := 0
+	duplicate, err := guard.Do(context.Background(), "evt-1", func(context.Context) error {
+		calls++
+		return nil
+	})
+	if duplicate || err != nil || calls != 1 {
+		t.Fatalf("retry should run the handler: duplicate=%v err=%v calls=%d", duplicate, err, calls)
+	}
+}
+
+func TestIdempotencyGuardRejectsConcurrentDelivery(t *testing.T) {
+	guard := NewIdempotencyGuard()
+	if state, _ := guard.Begin(context.Background(), "evt-1"); state != IdempotencyAcquired {
+		t.Fatalf("expected acquired, got %s", state)
+	}
+	_, err := guard.Do(context.Background(), "evt-1", func(context.Context) error { return nil })
+	if !errors.Is(err, ErrIdempotencyInProgress) {
+		t.Fatalf("expected in-progress error, got %v", err)
+	}
+}
+
+func TestMemoryIdempotencyStoreExpiresLeasesAndKeys(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := newMemoryIdempotencyStore(newIdempotencyConfig([]IdempotencyOption{
+		WithIdempotencyLease(time.Minute),
+		WithIdempotencyTTL(time.Hour),
+		func(cfg *idempotencyConfig) { cfg.now = func() time.Time { return now } },
+	}))
+	ctx := context.Background()
+	_, _, _ = store.Begin(ctx, "evt-1", 0)
+	now = now.Add(2 * time.Minute)
+	if state, _, _ := store.Begin(ctx, "evt-1", 0); state != IdempotencyAcquired {
+		t.Fatalf("expired lease should be reacquired, got %s", state)
+	}
+	_ = store.Complete(ctx, "evt-1")
+	now = now.Add(30 * time.Minute)
+	if state, _, _ := store.Begin(ctx, "evt-1", 0); state != IdempotencyCompleted {
+		t.Fatalf("expected completed within ttl, got %s", state)
+	}
+	now = now.Add(time.Hour)
+	if state, _, _ := store.Begin(ctx, "evt-2", 0); state != IdempotencyAcquired || store.Len() != 1 {
+		t.Fatalf("expected expired key evicted, state=%s len=%d", state, store.Len())
+	}
+}
+
+func TestMemoryIdempotencyStoreBoundsEntries(t *testing.T) {
+	store := newMemoryIdempotencyStore(newIdempotencyConfig([]IdempotencyOption{WithIdempotencyMaxEntries(2)}))
+	ctx := context.Background()
+	for _, eventID := range []string{"evt-1", "evt-2", "evt-3"} {
+		_, _, _ = store.Begin(ctx, eventID, 0)
+		_ = store.Complete(ctx, eventID)
+	}
+	if store.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", store.Len())
+	}
+	if state, _, _ := store.Begin(ctx, "evt-1", 0); state != IdempotencyAcquired {
+		t.Fatalf("oldest key should have been evicted, got %s", state)
+	}
+}
+
+func TestIdempotencyOptionsFromEnv(t *testing.T) {
+	env := map[string]string{"IDEMPOTENCY_LEASE_MS": "2000", "IDEMPOTENCY_TTL_MS": "60000", "IDEMPOTENCY_MAX_ENTRIES": "10"}
+	cfg := newIdempotencyConfig(IdempotencyOptionsFromEnv(func(key string) string { return env[key] }))
+	if cfg.lease != 2*time.Second || cfg.ttl != time.Minute || cfg.maxEntries != 10 {
+		t.Fatalf("unexpected config: %+v", cfg)
 	}
 }
 
 func TestScopedIdempotencyGuardFallsBackWhenDatabaseUnavailable(t *testing.T) {
 	t.Setenv("DATABASE_URL", "postgres://127.0.0.1:1/does_not_exist?sslmode=disable")
 	guard := NewScopedIdempotencyGuard("worker-test")
-	if guard.Seen("evt-2") {
+	handle := func(context.Context) error { return nil }
+	if duplicate, _ := guard.Do(context.Background(), "evt-2", handle); duplicate {
 		t.Fatalf("first occurrence should not be marked as duplicate")
 	}
-	if !guard.Seen("evt-2") {
+	if duplicate, _ := guard.Do(context.Background(), "evt-2", handle); !duplicate {
 		t.Fatalf("second occurrence should be marked as duplicate")
 	}
 }
@@ -33,3 +122,49 @@ func TestScopedIdempotencyGuardPanicsInStrictModeWithoutDatabaseURL(t *testing.T
 	}()
 	_ = NewScopedIdempotencyGuard("worker-test")
 }
+
+func TestIdempotencyGuardReportsRemainingLease(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	guard := NewIdempotencyGuard(
+		WithIdempotencyLease(time.Minute),
+		func(cfg *idempotencyConfig) { cfg.now = func() time.Time { return now } },
+	)
+	_, _ = guard.Begin(context.Background(), "evt-1")
+	now = now.Add(20 * time.Second)
+	_, err := guard.Do(context.Background(), "evt-1", func(context.Context) error { return nil })
+	delay, ok := inProgressDelay(err, time.Second)
+	if !ok || delay != 40*time.Second {
+		t.Fatalf("expected 40s retry delay, got %v ok=%v err=%v", delay, ok, err)
+	}
+}
+
+func TestIdempotencyGuardExtendsLeaseOnHeartbeat(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var mu sync.Mutex
+	clock := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(d)
+	}
+	guard := NewIdempotencyGuard(func(cfg *idempotencyConfig) { cfg.now = clock })
+	heartbeat := newLeaseHeartbeat(time.Minute)
+	ctx := contextWithHeartbeat(context.Background(), heartbeat)
+	_, err := guard.Do(ctx, "evt-1", func(context.Context) error {
+		advance(50 * time.Second)
+		heartbeat.beat()
+		advance(50 * time.Second)
+		state, _, _ := guard.store.Begin(context.Background(), "evt-1", time.Minute)
+		if state != IdempotencyInProgress {
+			t.Errorf("expected lease kept alive by heartbeat, got %s", state)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+}
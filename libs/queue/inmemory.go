@@ -2,37 +2,167 @@ package queue
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 )
 
+var errBusClosed = errors.New("bus is closed")
+
 type InMemoryBus struct {
-	mu       sync.RWMutex
-	handlers map[string][]Handler
+	mu          sync.Mutex
+	consumers   map[string]map[string]*inMemoryConsumer
+	outstanding int
+	idle        chan struct{}
+	done        chan struct{}
+	closed      bool
+	ephemeral   int
+	members     sync.WaitGroup
+	producer    string
 }
 
-func NewInMemoryBus() *InMemoryBus {
-	return &InMemoryBus{handlers: make(map[string][]Handler)}
+func NewInMemoryBus(opts ...BusOption) *InMemoryBus {
+	idle := make(chan struct{})
+	close(idle)
+	return &InMemoryBus{
+		consumers: make(map[string]map[string]*inMemoryConsumer),
+		idle:      idle,
+		done:      make(chan struct{}),
+		producer:  newBusConfig(opts).producer,
+	}
 }
 
-func (b *InMemoryBus) Subscribe(_ context.Context, topic string, _ string, handler Handler) error {
+func (b *InMemoryBus) Subscribe(
+	ctx context.Context,
+	topic string,
+	consumer string,
+	handler Handler,
+	opts ...SubscribeOption,
+) error {
 	b.mu.Lock()
-	b.handlers[topic] = append(b.handlers[topic], handler)
+	if b.closed {
+		b.mu.Unlock()
+		return errBusClosed
+	}
+	cfg := newSubscribeConfig(opts)
+	key := consumer
+	ephemeral := key == "" || cfg.broadcast
+	if ephemeral {
+		b.ephemeral++
+		key = fmt.Sprintf("ephemeral-%d", b.ephemeral)
+	}
+	if b.consumers[topic] == nil {
+		b.consumers[topic] = make(map[string]*inMemoryConsumer)
+	}
+	group, ok := b.consumers[topic][key]
+	if !ok {
+		group = newInMemoryConsumer(b, consumer, cfg)
+		group.topic, group.key, group.ephemeral = topic, key, ephemeral
+		b.consumers[topic][key] = group
+	}
+	if group.members == 0 {
+		b.acquireLocked(group.pending)
+	}
+	group.members++
+	b.members.Add(1)
 	b.mu.Unlock()
+	go func() {
+		defer b.members.Done()
+		defer b.detach(group)
+		group.run(ctx, handler)
+	}()
 	return nil
 }
 
+func (b *InMemoryBus) detach(group *inMemoryConsumer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	group.members--
+	if group.members > 0 {
+		return
+	}
+	b.releaseLocked(group.pending)
+	if !group.ephemeral {
+		return
+	}
+	group.removed = true
+	group.queue = nil
+	group.pending = 0
+	delete(b.consumers[group.topic], group.key)
+	if len(b.consumers[group.topic]) == 0 {
+		delete(b.consumers, group.topic)
+	}
+}
+
 func (b *InMemoryBus) Publish(ctx context.Context, event Event) error {
-	b.mu.RLock()
-	handlers := append([]Handler{}, b.handlers[event.Topic]...)
-	b.mu.RUnlock()
-	for _, handler := range handlers {
-		if err := handler(ctx, event); err != nil {
-			return err
-		}
+	event = stampHeaders(ctx, event, b.producer)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return errBusClosed
+	}
+	for _, group := range b.consumers[event.Topic] {
+		group.add(inMemoryDelivery{event: event})
 	}
 	return nil
 }
 
+func (b *InMemoryBus) WaitIdle(ctx context.Context) error {
+	b.mu.Lock()
+	idle := b.idle
+	b.mu.Unlock()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (b *InMemoryBus) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.done)
+	b.mu.Unlock()
+	b.members.Wait()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.outstanding > 0 {
+		b.outstanding = 0
+		close(b.idle)
+	}
 	return nil
 }
+
+func (b *InMemoryBus) acquireLocked(n int) {
+	if n <= 0 {
+		return
+	}
+	if b.outstanding == 0 {
+		b.idle = make(chan struct{})
+	}
+	b.outstanding += n
+}
+
+func (b *InMemoryBus) releaseLocked(n int) {
+	if n <= 0 || b.outstanding == 0 {
+		return
+	}
+	b.outstanding = max(b.outstanding-n, 0)
+	if b.outstanding == 0 {
+		close(b.idle)
+	}
+}
+
+func (b *InMemoryBus) isClosed() bool {
+	select {
+	case <-b.done:
+		return true
+	default:
+		return false
+	}
+}
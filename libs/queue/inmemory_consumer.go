@@ -0,0 +1,140 @@
+package queue
+
+import (
+	"context"
+	"time"
+)
+
+type inMemoryDelivery struct {
+	event     Event
+	delivered int
+}
+
+type inMemoryConsumer struct {
+	bus       *InMemoryBus
+	name      string
+	topic     string
+	key       string
+	ephemeral bool
+	cfg       subscribeConfig
+	queue     []inMemoryDelivery
+	ready     chan struct{}
+	members   int
+	pending   int
+	removed   bool
+}
+
+func newInMemoryConsumer(bus *InMemoryBus, name string, cfg subscribeConfig) *inMemoryConsumer {
+	return &inMemoryConsumer{bus: bus, name: name, cfg: cfg, ready: make(chan struct{}, 1)}
+}
+
+func (c *inMemoryConsumer) add(delivery inMemoryDelivery) {
+	c.pending++
+	if c.members > 0 {
+		c.bus.acquireLocked(1)
+	}
+	c.push(delivery)
+}
+
+func (c *inMemoryConsumer) settle() {
+	c.bus.mu.Lock()
+	defer c.bus.mu.Unlock()
+	if c.removed || c.pending == 0 {
+		return
+	}
+	c.pending--
+	if c.members > 0 {
+		c.bus.releaseLocked(1)
+	}
+}
+
+func (c *inMemoryConsumer) push(delivery inMemoryDelivery) {
+	c.queue = append(c.queue, delivery)
+	c.signal()
+}
+
+func (c *inMemoryConsumer) signal() {
+	select {
+	case c.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (c *inMemoryConsumer) next() (inMemoryDelivery, bool) {
+	c.bus.mu.Lock()
+	defer c.bus.mu.Unlock()
+	if len(c.queue) == 0 {
+		return inMemoryDelivery{}, false
+	}
+	delivery := c.queue[0]
+	c.queue = c.queue[1:]
+	return delivery, true
+}
+
+func (c *inMemoryConsumer) run(ctx context.Context, handler Handler) {
+	pool := newDispatcher(c.cfg.maxInFlight, c.cfg.orderingKey)
+	defer c.signal()
+	defer pool.stop()
+	for {
+		if ctx.Err() != nil || c.bus.isClosed() {
+			return
+		}
+		delivery, ok := c.next()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.bus.done:
+				return
+			case <-c.ready:
+			}
+			continue
+		}
+		pool.submit(delivery.event, func() {
+			c.deliver(ctx, handler, delivery)
+		})
+	}
+}
+
+func (c *inMemoryConsumer) deliver(ctx context.Context, handler Handler, delivery inMemoryDelivery) {
+	event := delivery.event
+	event.Headers = delivery.event.Headers.Clone()
+	handlerErr := runHandler(ctx, handler, event, c.name, c.cfg.handlerTimeout())
+	if handlerErr == nil {
+		c.settle()
+		return
+	}
+	if ctx.Err() != nil {
+		c.redeliverAfter(delivery, 0)
+		return
+	}
+	if delay, ok := inProgressDelay(handlerErr, c.cfg.backoffBase); ok {
+		c.redeliverAfter(delivery, delay)
+		return
+	}
+	delivery.delivered++
+	if delivery.delivered < c.cfg.maxDeliver {
+		c.redeliverAfter(delivery, c.cfg.redeliveryDelay(delivery.delivered))
+		return
+	}
+	dlq, err := newDLQEvent(event, c.name, handlerErr, delivery.delivered)
+	if err == nil {
+		err = c.bus.Publish(context.Background(), dlq)
+	}
+	if err != nil {
+		c.redeliverAfter(delivery, c.cfg.backoffMax)
+		return
+	}
+	c.settle()
+}
+
+func (c *inMemoryConsumer) redeliverAfter(delivery inMemoryDelivery, delay time.Duration) {
+	time.AfterFunc(delay, func() {
+		c.bus.mu.Lock()
+		defer c.bus.mu.Unlock()
+		if c.bus.closed || c.removed {
+			return
+		}
+		c.push(delivery)
+	})
+}
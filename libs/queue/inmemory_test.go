@@ -0,0 +1,174 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func waitIdle(t *testing.T, bus *InMemoryBus) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := bus.WaitIdle(ctx); err != nil {
+		t.Fatalf("wait idle: %v", err)
+	}
+}
+
+func TestInMemoryBusPublishDoesNotSurfaceHandlerErrors(t *testing.T) {
+	bus := NewInMemoryBus()
+	defer bus.Close()
+	var attempts atomic.Int32
+	_ = bus.Subscribe(context.Background(), "media.uploaded.v1", "worker-ingest", func(context.Context, Event) error {
+		if attempts.Add(1) < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	}, WithRedeliveryBackoff(time.Millisecond, time.Millisecond))
+	if err := bus.Publish(context.Background(), Event{ID: "evt-1", Topic: "media.uploaded.v1"}); err != nil {
+		t.Fatalf("publish must not fail on consumer error: %v", err)
+	}
+	waitIdle(t, bus)
+	if attempts.Load() != 3 {
+		t.Fatalf("expected 3 deliveries, got %d", attempts.Load())
+	}
+}
+
+func TestInMemoryBusDeadLettersAfterMaxDeliver(t *testing.T) {
+	bus := NewInMemoryBus()
+	defer bus.Close()
+	var attempts atomic.Int32
+	_ = bus.Subscribe(context.Background(), "media.transcoded.v1", "worker-policy", func(context.Context, Event) error {
+		attempts.Add(1)
+		return errors.New("poison payload")
+	}, WithMaxDeliver(2), WithRedeliveryBackoff(time.Millisecond, time.Millisecond))
+	var dead DLQEvent
+	_ = bus.Subscribe(context.Background(), "media.transcoded.v1.dlq.v1", "test-dlq", func(_ context.Context, event Event) error {
+		return json.Unmarshal(event.Payload, &dead)
+	})
+	_ = bus.Publish(context.Background(), Event{ID: "evt-1", Topic: "media.transcoded.v1", Payload: []byte(`{}`)})
+	waitIdle(t, bus)
+	if attempts.Load() != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", attempts.Load())
+	}
+	if dead.EventID != "evt-1" || dead.Consumer != "worker-policy" || dead.Attempts != 2 {
+		t.Fatalf("unexpected dlq envelope: %+v", dead)
+	}
+}
+
+func TestInMemoryBusConsumerGroupsShareDurableQueue(t *testing.T) {
+	bus := NewInMemoryBus()
+	defer bus.Close()
+	var mu sync.Mutex
+	counts := map[string]int{}
+	record := func(name string) Handler {
+		return func(context.Context, Event) error {
+			mu.Lock()
+			counts[name]++
+			mu.Unlock()
+			return nil
+		}
+	}
+	_ = bus.Subscribe(context.Background(), "episode.published.v1", "worker-social-snippet", record("snippet"))
+	_ = bus.Subscribe(context.Background(), "episode.published.v1", "worker-social-snippet", record("snippet"))
+	_ = bus.Subscribe(context.Background(), "episode.published.v1", "worker-reco-rail", record("rail"))
+	for i := 0; i < 10; i++ {
+		_ = bus.Publish(context.Background(), Event{ID: "evt", Topic: "episode.published.v1"})
+	}
+	waitIdle(t, bus)
+	if counts["snippet"] != 10 || counts["rail"] != 10 {
+		t.Fatalf("expected each group to process every event once, got %+v", counts)
+	}
+}
+
+func TestInMemoryBusKeepsQueueForDurableConsumerAfterUnsubscribe(t *testing.T) {
+	bus := NewInMemoryBus()
+	defer bus.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	_ = bus.Subscribe(ctx, "watch.event.v1", "worker-reco-feature", func(context.Context, Event) error { return nil })
+	cancel()
+	time.Sleep(5 * time.Millisecond)
+	_ = bus.Publish(context.Background(), Event{ID: "evt-1", Topic: "watch.event.v1"})
+	var delivered atomic.Int32
+	_ = bus.Subscribe(context.Background(), "watch.event.v1", "worker-reco-feature", func(context.Context, Event) error {
+		delivered.Add(1)
+		return nil
+	})
+	waitIdle(t, bus)
+	if delivered.Load() != 1 {
+		t.Fatalf("expected queued event to reach resubscribed consumer, got %d", delivered.Load())
+	}
+}
+
+func TestInMemoryBusDefersInProgressDeliveriesWithoutDeadLettering(t *testing.T) {
+	bus := NewInMemoryBus()
+	defer bus.Close()
+	var attempts atomic.Int32
+	_ = bus.Subscribe(context.Background(), "media.transcoded.v1", "worker-policy", func(context.Context, Event) error {
+		if attempts.Add(1) <= 3 {
+			return &InProgressError{EventID: "evt-1", RetryIn: time.Millisecond}
+		}
+		return nil
+	}, WithMaxDeliver(2), WithRedeliveryBackoff(time.Millisecond, time.Millisecond))
+	var dead atomic.Int32
+	_ = bus.Subscribe(context.Background(), "media.transcoded.v1.dlq.v1", "test-dlq", func(context.Context, Event) error {
+		dead.Add(1)
+		return nil
+	})
+	_ = bus.Publish(context.Background(), Event{ID: "evt-1", Topic: "media.transcoded.v1", Payload: []byte(`{}`)})
+	waitIdle(t, bus)
+	if attempts.Load() != 4 || dead.Load() != 0 {
+		t.Fatalf("expected in-progress deliveries to be deferred, attempts=%d dead=%d", attempts.Load(), dead.Load())
+	}
+}
+
+func TestInMemoryBusBroadcastDeliversToEveryInstance(t *testing.T) {
+	bus := NewInMemoryBus()
+	defer bus.Close()
+	var first, second atomic.Int32
+	_ = bus.Subscribe(context.Background(), "video.run.cancelled.v1", "worker-gen-nim-cancellations", func(context.Context, Event) error {
+		first.Add(1)
+		return nil
+	}, WithBroadcast())
+	_ = bus.Subscribe(context.Background(), "video.run.cancelled.v1", "worker-gen-nim-cancellations", func(context.Context, Event) error {
+		second.Add(1)
+		return nil
+	}, WithBroadcast())
+	_ = bus.Publish(context.Background(), Event{ID: "evt-1", Topic: "video.run.cancelled.v1"})
+	waitIdle(t, bus)
+	if first.Load() != 1 || second.Load() != 1 {
+		t.Fatalf("expected every instance to receive the event, got %d and %d", first.Load(), second.Load())
+	}
+}
+
+func TestInMemoryBusStopsCountingDetachedConsumers(t *testing.T) {
+	bus := NewInMemoryBus()
+	defer bus.Close()
+	durableCtx, cancelDurable := context.WithCancel(context.Background())
+	_ = bus.Subscribe(durableCtx, "watch.event.v1", "worker-reco-feature", func(context.Context, Event) error { return nil })
+	ephemeralCtx, cancelEphemeral := context.WithCancel(context.Background())
+	_ = bus.Subscribe(ephemeralCtx, "watch.event.v1", "", func(context.Context, Event) error { return nil })
+	cancelDurable()
+	cancelEphemeral()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		bus.mu.Lock()
+		groups := len(bus.consumers["watch.event.v1"])
+		durable := bus.consumers["watch.event.v1"]["worker-reco-feature"]
+		detached := durable != nil && durable.members == 0
+		bus.mu.Unlock()
+		if groups == 1 && detached {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected only the detached durable group to stay registered, got %d groups", groups)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	_ = bus.Publish(context.Background(), Event{ID: "evt-1", Topic: "watch.event.v1"})
+	waitIdle(t, bus)
+}
@@ -4,21 +4,31 @@ import (
 	"context"
 	"fmt"
 	"sync"
-	"time"
 
 	"github.com/nats-io/nats.go"
 )
 
-const streamName = "MM_EVENTS"
-
 type NATSBus struct {
-	conn *nats.Conn
-	js   nats.JetStreamContext
-	mu   sync.Mutex
-	subs []*nats.Subscription
+	conn     *nats.Conn
+	js       nats.JetStreamContext
+	mu       sync.Mutex
+	closed   bool
+	closing  context.Context
+	shutdown context.CancelFunc
+	loops    sync.WaitGroup
+	deferred natsDeferrals
+	producer string
+}
+
+func NewNATSBus(url string, opts ...BusOption) (*NATSBus, error) {
+	specs, err := StreamTopologyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return NewNATSBusWithStreams(url, specs, opts...)
 }
 
-func NewNATSBus(url string) (*NATSBus, error) {
+func NewNATSBusWithStreams(url string, specs []StreamSpec, opts ...BusOption) (*NATSBus, error) {
 	conn, err := nats.Connect(url, nats.Name("mikasmissions-platform"), nats.RetryOnFailedConnect(true))
 	if err != nil {
 		return nil, err
@@ -28,85 +38,66 @@ func NewNATSBus(url string) (*NATSBus, error) {
 		conn.Close()
 		return nil, err
 	}
-	bus := &NATSBus{conn: conn, js: js}
-	if err := bus.ensureStream(); err != nil {
+	closing, shutdown := context.WithCancel(context.Background())
+	bus := &NATSBus{conn: conn, js: js, closing: closing, shutdown: shutdown, producer: newBusConfig(opts).producer}
+	if err := bus.reconcileStreams(specs); err != nil {
 		_ = bus.Close()
 		return nil, err
 	}
 	return bus, nil
 }
 
-func (b *NATSBus) ensureStream() error {
-	if _, err := b.js.StreamInfo(streamName); err == nil {
-		return nil
-	}
-	_, err := b.js.AddStream(&nats.StreamConfig{
-		Name:      streamName,
-		Subjects:  []string{">"},
-		Storage:   nats.FileStorage,
-		Retention: nats.LimitsPolicy,
-		MaxAge:    24 * time.Hour,
-	})
-	if err != nil {
-		if _, infoErr := b.js.StreamInfo(streamName); infoErr == nil {
-			return nil
-		}
-	}
+func (b *NATSBus) Publish(ctx context.Context, event Event) error {
+	_, err := b.js.PublishMsg(natsMsgFromEvent(stampHeaders(ctx, event, b.producer)))
 	return err
 }
 
-func (b *NATSBus) Publish(_ context.Context, event Event) error {
-	msg := nats.NewMsg(event.Topic)
-	msg.Data = event.Payload
-	if event.ID != "" {
-		msg.Header.Set("Nats-Msg-Id", event.ID)
+func (b *NATSBus) Subscribe(
+	ctx context.Context,
+	topic string,
+	consumer string,
+	handler Handler,
+	opts ...SubscribeOption,
+) error {
+	cfg := newSubscribeConfig(opts)
+	if cfg.broadcast {
+		consumer = instanceConsumer(consumer)
 	}
-	_, err := b.js.PublishMsg(msg)
-	return err
-}
-
-func (b *NATSBus) Subscribe(ctx context.Context, topic string, consumer string, handler Handler) error {
-	sub, err := b.js.Subscribe(topic, func(msg *nats.Msg) {
-		eventID := msg.Header.Get("Nats-Msg-Id")
-		if eventID == "" {
-			meta, metaErr := msg.Metadata()
-			if metaErr == nil {
-				eventID = fmt.Sprintf("%s-%d", topic, meta.Sequence.Stream)
-			}
-		}
-		event := Event{ID: eventID, Topic: msg.Subject, Payload: msg.Data}
-		if err := handler(context.Background(), event); err != nil {
-			_ = msg.Nak()
-			return
-		}
-		_ = msg.Ack()
-	},
-		nats.Durable(consumer),
-		nats.ManualAck(),
-		nats.DeliverNew(),
-		nats.AckExplicit(),
-	)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return errBusClosed
+	}
+	stream, err := b.ensureConsumer(topic, consumer, cfg)
 	if err != nil {
 		return err
 	}
-	b.mu.Lock()
-	b.subs = append(b.subs, sub)
-	b.mu.Unlock()
+	sub, err := b.js.PullSubscribe(topic, consumer, nats.Bind(stream, consumer))
+	if err != nil {
+		return fmt.Errorf("bind consumer %s/%s: %w", stream, consumer, err)
+	}
+	b.loops.Add(1)
 	go func() {
-		<-ctx.Done()
-		_ = sub.Drain()
+		defer b.loops.Done()
+		b.pull(ctx, sub, topic, consumer, handler, cfg)
+		if cfg.broadcast {
+			_ = b.js.DeleteConsumer(stream, consumer)
+		}
 	}()
 	return nil
 }
 
 func (b *NATSBus) Close() error {
 	b.mu.Lock()
-	for _, sub := range b.subs {
-		_ = sub.Drain()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
 	}
-	b.subs = nil
+	b.closed = true
+	b.shutdown()
 	b.mu.Unlock()
-	b.conn.Drain()
+	b.loops.Wait()
+	_ = b.conn.Drain()
 	b.conn.Close()
 	return nil
 }
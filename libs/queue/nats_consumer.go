@@ -0,0 +1,57 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+func (b *NATSBus) ensureConsumer(topic, consumer string, cfg subscribeConfig) (string, error) {
+	stream, err := b.js.StreamNameBySubject(topic)
+	if err != nil {
+		return "", fmt.Errorf("resolve stream for %s: %w", topic, err)
+	}
+	info, err := b.js.ConsumerInfo(stream, consumer)
+	if errors.Is(err, nats.ErrConsumerNotFound) {
+		created := newConsumerConfig(topic, consumer, cfg)
+		if _, err := b.js.AddConsumer(stream, &created); err != nil {
+			return "", fmt.Errorf("create consumer %s/%s: %w", stream, consumer, err)
+		}
+		return stream, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("consumer info %s/%s: %w", stream, consumer, err)
+	}
+	if updated, changed := reconcileConsumerConfig(info.Config, cfg); changed {
+		if _, err := b.js.UpdateConsumer(stream, &updated); err != nil {
+			return "", fmt.Errorf("update consumer %s/%s: %w", stream, consumer, err)
+		}
+	}
+	return stream, nil
+}
+
+func reconcileConsumerConfig(current nats.ConsumerConfig, cfg subscribeConfig) (nats.ConsumerConfig, bool) {
+	desired := newConsumerConfig(current.FilterSubject, current.Durable, cfg)
+	if current.AckWait == desired.AckWait && current.MaxAckPending == desired.MaxAckPending {
+		return current, false
+	}
+	current.AckWait = desired.AckWait
+	current.MaxAckPending = desired.MaxAckPending
+	return current, true
+}
+
+func newConsumerConfig(topic, consumer string, cfg subscribeConfig) nats.ConsumerConfig {
+	created := nats.ConsumerConfig{
+		Durable:       consumer,
+		FilterSubject: topic,
+		AckPolicy:     nats.AckExplicitPolicy,
+		AckWait:       cfg.ackWait,
+		MaxAckPending: cfg.maxInFlight + cfg.fetchBatch,
+	}
+	if cfg.broadcast {
+		created.InactiveThreshold = broadcastInactiveThreshold
+	}
+	cfg.deliver.applyNATS(&created)
+	return created
+}
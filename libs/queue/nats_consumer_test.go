@@ -0,0 +1,50 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+func TestNewConsumerConfigAppliesDeliverPositionOnCreate(t *testing.T) {
+	created := newConsumerConfig("media.uploaded.v1", "worker-media", newSubscribeConfig([]SubscribeOption{WithDeliverFromSequence(42), WithMaxInFlight(4)}))
+	if created.Durable != "worker-media" || created.FilterSubject != "media.uploaded.v1" || created.AckPolicy != nats.AckExplicitPolicy {
+		t.Fatalf("unexpected consumer identity: %+v", created)
+	}
+	if created.DeliverPolicy != nats.DeliverByStartSequencePolicy || created.OptStartSeq != 42 || created.MaxAckPending != 8 {
+		t.Fatalf("unexpected deliver settings: %+v", created)
+	}
+	since := time.Date(2026, 3, 11, 10, 0, 0, 0, time.UTC)
+	fromTime := newConsumerConfig("media.uploaded.v1", "worker-media", newSubscribeConfig([]SubscribeOption{WithDeliverFromTime(since)}))
+	if fromTime.DeliverPolicy != nats.DeliverByStartTimePolicy || fromTime.OptStartTime == nil || !fromTime.OptStartTime.Equal(since) {
+		t.Fatalf("unexpected time deliver settings: %+v", fromTime)
+	}
+	if fresh := newConsumerConfig("media.uploaded.v1", "worker-media", newSubscribeConfig(nil)); fresh.DeliverPolicy != nats.DeliverNewPolicy {
+		t.Fatalf("expected deliver new by default, got %v", fresh.DeliverPolicy)
+	}
+}
+
+func TestReconcileConsumerConfigUpdatesTuningOnly(t *testing.T) {
+	current := newConsumerConfig("video.run.step.requested.v1", "worker-gen-nim", newSubscribeConfig([]SubscribeOption{WithDeliverAll()}))
+	if _, changed := reconcileConsumerConfig(current, newSubscribeConfig(nil)); changed {
+		t.Fatal("expected unchanged tuning to skip the update")
+	}
+	updated, changed := reconcileConsumerConfig(current, newSubscribeConfig([]SubscribeOption{WithAckWait(2 * time.Minute), WithMaxInFlight(4), WithFetchBatch(2)}))
+	if !changed || updated.AckWait != 2*time.Minute || updated.MaxAckPending != 6 {
+		t.Fatalf("expected ack wait and max ack pending to be reconciled, got %+v", updated)
+	}
+	if updated.DeliverPolicy != nats.DeliverAllPolicy || updated.Durable != "worker-gen-nim" || updated.FilterSubject != "video.run.step.requested.v1" {
+		t.Fatalf("expected deliver policy and identity to be preserved, got %+v", updated)
+	}
+}
+
+func TestNewConsumerConfigExpiresBroadcastConsumers(t *testing.T) {
+	if durable := newConsumerConfig("video.run.cancelled.v1", "worker-gen-nim-cancellations", newSubscribeConfig(nil)); durable.InactiveThreshold != 0 {
+		t.Fatalf("expected shared consumers to never expire, got %v", durable.InactiveThreshold)
+	}
+	broadcast := newConsumerConfig("video.run.cancelled.v1", "worker-gen-nim-cancellations-1", newSubscribeConfig([]SubscribeOption{WithBroadcast()}))
+	if broadcast.InactiveThreshold != broadcastInactiveThreshold || broadcast.DeliverPolicy != nats.DeliverNewPolicy {
+		t.Fatalf("unexpected broadcast consumer settings: %+v", broadcast)
+	}
+}
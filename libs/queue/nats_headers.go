@@ -0,0 +1,40 @@
+package queue
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+)
+
+const natsHeaderPrefix = "Nats-"
+
+func NewNATSMsg(ctx context.Context, event Event) *nats.Msg {
+	return natsMsgFromEvent(stampHeaders(ctx, event, ""))
+}
+
+func natsMsgFromEvent(event Event) *nats.Msg {
+	msg := nats.NewMsg(event.Topic)
+	msg.Data = event.Payload
+	for key, value := range event.Headers {
+		if value == "" || strings.HasPrefix(key, natsHeaderPrefix) {
+			continue
+		}
+		msg.Header.Set(key, value)
+	}
+	if event.ID != "" {
+		msg.Header.Set(nats.MsgIdHdr, event.ID)
+	}
+	return msg
+}
+
+func headersFromNATSMsg(msg *nats.Msg) Headers {
+	headers := make(Headers, len(msg.Header))
+	for key, values := range msg.Header {
+		if len(values) == 0 || strings.HasPrefix(key, natsHeaderPrefix) {
+			continue
+		}
+		headers[key] = values[0]
+	}
+	return headers
+}
@@ -0,0 +1,115 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	natsFetchWait       = 5 * time.Second
+	natsFetchErrorPause = 1 * time.Second
+)
+
+func (b *NATSBus) pull(
+	ctx context.Context,
+	sub *nats.Subscription,
+	topic string,
+	consumer string,
+	handler Handler,
+	cfg subscribeConfig,
+) {
+	loopCtx, stopLoop := context.WithCancel(ctx)
+	defer stopLoop()
+	defer context.AfterFunc(b.closing, stopLoop)()
+	pool := newDispatcher(cfg.maxInFlight, cfg.orderingKey)
+	defer func() {
+		_ = sub.Drain()
+	}()
+	defer pool.stop()
+	for loopCtx.Err() == nil && sub.IsValid() {
+		fetchCtx, cancel := context.WithTimeout(loopCtx, natsFetchWait)
+		msgs, err := sub.Fetch(cfg.fetchBatch, nats.Context(fetchCtx))
+		cancel()
+		if err != nil && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, nats.ErrTimeout) {
+			if loopCtx.Err() != nil || errors.Is(err, nats.ErrBadSubscription) || errors.Is(err, nats.ErrConnectionClosed) {
+				return
+			}
+			time.Sleep(natsFetchErrorPause)
+			continue
+		}
+		for _, msg := range msgs {
+			msg := msg
+			if loopCtx.Err() != nil {
+				_ = msg.Nak()
+				continue
+			}
+			event := eventFromNATSMsg(msg, topic)
+			lease := newLeaseHeartbeat(cfg.ackWait)
+			stop := heartbeat(msg, cfg.heartbeatInterval(), lease)
+			pool.submit(event, func() {
+				b.process(contextWithHeartbeat(ctx, lease), msg, event, consumer, handler, cfg, stop)
+			})
+		}
+	}
+}
+
+func (b *NATSBus) process(
+	ctx context.Context,
+	msg *nats.Msg,
+	event Event,
+	consumer string,
+	handler Handler,
+	cfg subscribeConfig,
+	stopHeartbeat func(),
+) {
+	err := runHandler(ctx, handler, event, consumer, cfg.handlerTimeout())
+	stopHeartbeat()
+	if err != nil && ctx.Err() != nil {
+		_ = msg.Nak()
+		return
+	}
+	if err != nil {
+		b.handleFailure(msg, event, consumer, cfg, err)
+		return
+	}
+	b.deferred.clear(consumer, event.ID)
+	_ = msg.Ack()
+}
+
+func heartbeat(msg *nats.Msg, interval time.Duration, lease *leaseHeartbeat) func() {
+	done := make(chan struct{})
+	if interval <= 0 {
+		return func() {}
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = msg.InProgress()
+				lease.beat()
+			}
+		}
+	}()
+	return func() {
+		close(done)
+	}
+}
+
+func eventFromNATSMsg(msg *nats.Msg, topic string) Event {
+	eventID := msg.Header.Get(nats.MsgIdHdr)
+	if eventID == "" {
+		meta, err := msg.Metadata()
+		if err == nil {
+			eventID = fmt.Sprintf("%s-%d", topic, meta.Sequence.Stream)
+		}
+	}
+	return Event{ID: eventID, Topic: msg.Subject, Payload: msg.Data, Headers: headersFromNATSMsg(msg)}
+}
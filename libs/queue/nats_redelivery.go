@@ -0,0 +1,69 @@
+package queue
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+)
+
+type natsDeferrals struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (d *natsDeferrals) add(consumer string, eventID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.counts == nil {
+		d.counts = make(map[string]int)
+	}
+	d.counts[consumer+"/"+eventID]++
+}
+
+func (d *natsDeferrals) count(consumer string, eventID string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.counts[consumer+"/"+eventID]
+}
+
+func (d *natsDeferrals) clear(consumer string, eventID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.counts, consumer+"/"+eventID)
+}
+
+func (b *NATSBus) handleFailure(msg *nats.Msg, event Event, consumer string, cfg subscribeConfig, handlerErr error) {
+	if delay, ok := inProgressDelay(handlerErr, cfg.backoffBase); ok {
+		b.deferred.add(consumer, event.ID)
+		_ = msg.NakWithDelay(delay)
+		return
+	}
+	delivered := max(deliveryCount(msg)-b.deferred.count(consumer, event.ID), 1)
+	if delivered < cfg.maxDeliver {
+		_ = msg.NakWithDelay(cfg.redeliveryDelay(delivered))
+		return
+	}
+	if err := b.publishDLQ(event, consumer, handlerErr, delivered); err != nil {
+		_ = msg.NakWithDelay(cfg.backoffMax)
+		return
+	}
+	b.deferred.clear(consumer, event.ID)
+	_ = msg.Term()
+}
+
+func (b *NATSBus) publishDLQ(event Event, consumer string, handlerErr error, delivered int) error {
+	dlq, err := newDLQEvent(event, consumer, handlerErr, delivered)
+	if err != nil {
+		return err
+	}
+	return b.Publish(context.Background(), dlq)
+}
+
+func deliveryCount(msg *nats.Msg) int {
+	meta, err := msg.Metadata()
+	if err != nil || meta.NumDelivered == 0 {
+		return 1
+	}
+	return int(meta.NumDelivered)
+}
@@ -0,0 +1,148 @@
+package queue
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+const legacyStreamName = "MM_EVENTS"
+
+type StreamSpec struct {
+	Name      string
+	Subjects  []string
+	MaxAge    time.Duration
+	MaxBytes  int64
+	Replicas  int
+	Storage   string
+	Retention string
+	Discard   string
+}
+
+func DefaultStreamTopology() []StreamSpec {
+	return []StreamSpec{
+		{
+			Name:     "MM_MEDIA",
+			Subjects: []string{"media.>", "episode.>"},
+			MaxAge:   7 * 24 * time.Hour,
+			MaxBytes: 2 << 30,
+			Discard:  "old",
+		},
+		{
+			Name:     "MM_GENERATION",
+			Subjects: []string{"video.>"},
+			MaxAge:   14 * 24 * time.Hour,
+			MaxBytes: 1 << 30,
+			Discard:  "new",
+		},
+		{
+			Name:     "MM_IDENTITY",
+			Subjects: []string{"consent.>", "parent.>", "child.>"},
+			MaxAge:   30 * 24 * time.Hour,
+			MaxBytes: 512 << 20,
+			Discard:  "new",
+		},
+		{
+			Name:     "MM_ANALYTICS",
+			Subjects: []string{"watch.>", "playback.>", "ux.>", "safety.>", "analytics.>", "reco.>"},
+			MaxAge:   24 * time.Hour,
+			MaxBytes: 4 << 30,
+			Discard:  "old",
+		},
+	}
+}
+
+func (s StreamSpec) natsConfig() (*nats.StreamConfig, error) {
+	if s.Name == "" || len(s.Subjects) == 0 {
+		return nil, fmt.Errorf("stream spec requires name and subjects")
+	}
+	storage, err := parseStorage(s.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("stream %s: %w", s.Name, err)
+	}
+	retention, err := parseRetention(s.Retention)
+	if err != nil {
+		return nil, fmt.Errorf("stream %s: %w", s.Name, err)
+	}
+	discard, err := parseDiscard(s.Discard)
+	if err != nil {
+		return nil, fmt.Errorf("stream %s: %w", s.Name, err)
+	}
+	replicas := s.Replicas
+	if replicas <= 0 {
+		replicas = 1
+	}
+	maxBytes := s.MaxBytes
+	if maxBytes == 0 {
+		maxBytes = -1
+	}
+	return &nats.StreamConfig{
+		Name:      s.Name,
+		Subjects:  append([]string{}, s.Subjects...),
+		Storage:   storage,
+		Retention: retention,
+		Discard:   discard,
+		MaxAge:    s.MaxAge,
+		MaxBytes:  maxBytes,
+		Replicas:  replicas,
+	}, nil
+}
+
+func parseStorage(raw string) (nats.StorageType, error) {
+	switch strings.ToLower(raw) {
+	case "", "file":
+		return nats.FileStorage, nil
+	case "memory":
+		return nats.MemoryStorage, nil
+	default:
+		return 0, fmt.Errorf("unsupported storage %q", raw)
+	}
+}
+
+func parseRetention(raw string) (nats.RetentionPolicy, error) {
+	switch strings.ToLower(raw) {
+	case "", "limits":
+		return nats.LimitsPolicy, nil
+	case "interest":
+		return nats.InterestPolicy, nil
+	case "workqueue":
+		return nats.WorkQueuePolicy, nil
+	default:
+		return 0, fmt.Errorf("unsupported retention %q", raw)
+	}
+}
+
+func parseDiscard(raw string) (nats.DiscardPolicy, error) {
+	switch strings.ToLower(raw) {
+	case "", "old":
+		return nats.DiscardOld, nil
+	case "new":
+		return nats.DiscardNew, nil
+	default:
+		return 0, fmt.Errorf("unsupported discard policy %q", raw)
+	}
+}
+
+func validateTopology(specs []StreamSpec) error {
+	if len(specs) == 0 {
+		return fmt.Errorf("stream topology is empty")
+	}
+	owners := make(map[string]string)
+	for _, spec := range specs {
+		if _, err := spec.natsConfig(); err != nil {
+			return err
+		}
+		for _, subject := range spec.Subjects {
+			if owner, ok := owners[subject]; ok {
+				return fmt.Errorf("subject %q declared by streams %s and %s", subject, owner, spec.Name)
+			}
+			if subject == ">" && len(specs) > 1 {
+				return fmt.Errorf("stream %s: catch-all subject overlaps every other stream", spec.Name)
+			}
+			owners[subject] = spec.Name
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+type streamSpecFile struct {
+	Name      string   `json:"name"`
+	Subjects  []string `json:"subjects"`
+	MaxAge    string   `json:"max_age"`
+	MaxBytes  int64    `json:"max_bytes"`
+	Replicas  int      `json:"replicas"`
+	Storage   string   `json:"storage"`
+	Retention string   `json:"retention"`
+	Discard   string   `json:"discard"`
+}
+
+func StreamTopologyFromEnv() ([]StreamSpec, error) {
+	return streamTopologyFromEnv(os.Getenv, os.ReadFile)
+}
+
+func streamTopologyFromEnv(getenv func(string) string, readFile func(string) ([]byte, error)) ([]StreamSpec, error) {
+	path := strings.TrimSpace(getenv("NATS_STREAMS_FILE"))
+	if path == "" {
+		return DefaultStreamTopology(), nil
+	}
+	raw, err := readFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read NATS_STREAMS_FILE: %w", err)
+	}
+	return ParseStreamTopology(raw)
+}
+
+func ParseStreamTopology(raw []byte) ([]StreamSpec, error) {
+	var entries []streamSpecFile
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("decode stream topology: %w", err)
+	}
+	specs := make([]StreamSpec, 0, len(entries))
+	for _, entry := range entries {
+		var maxAge time.Duration
+		if entry.MaxAge != "" {
+			parsed, err := time.ParseDuration(entry.MaxAge)
+			if err != nil {
+				return nil, fmt.Errorf("stream %s: parse max_age: %w", entry.Name, err)
+			}
+			maxAge = parsed
+		}
+		specs = append(specs, StreamSpec{
+			Name:      entry.Name,
+			Subjects:  entry.Subjects,
+			MaxAge:    maxAge,
+			MaxBytes:  entry.MaxBytes,
+			Replicas:  entry.Replicas,
+			Storage:   entry.Storage,
+			Retention: entry.Retention,
+			Discard:   entry.Discard,
+		})
+	}
+	if err := validateTopology(specs); err != nil {
+		return nil, err
+	}
+	return specs, nil
+}
@@ -0,0 +1,86 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/nats-io/nats.go"
+)
+
+func (b *NATSBus) reconcileStreams(specs []StreamSpec) error {
+	if err := validateTopology(specs); err != nil {
+		return err
+	}
+	if err := b.retireLegacyStream(specs); err != nil {
+		return err
+	}
+	for _, spec := range specs {
+		desired, err := spec.natsConfig()
+		if err != nil {
+			return err
+		}
+		if err := b.reconcileStream(desired); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *NATSBus) reconcileStream(desired *nats.StreamConfig) error {
+	info, err := b.js.StreamInfo(desired.Name)
+	if errors.Is(err, nats.ErrStreamNotFound) {
+		if _, err := b.js.AddStream(desired); err != nil {
+			if _, infoErr := b.js.StreamInfo(desired.Name); infoErr == nil {
+				return nil
+			}
+			return fmt.Errorf("add stream %s: %w", desired.Name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("stream info %s: %w", desired.Name, err)
+	}
+	if streamConfigMatches(info.Config, *desired) {
+		return nil
+	}
+	if info.Config.Storage != desired.Storage || info.Config.Retention != desired.Retention {
+		return fmt.Errorf("stream %s: storage and retention cannot be changed in place", desired.Name)
+	}
+	if _, err := b.js.UpdateStream(desired); err != nil {
+		return fmt.Errorf("update stream %s: %w", desired.Name, err)
+	}
+	return nil
+}
+
+func (b *NATSBus) retireLegacyStream(specs []StreamSpec) error {
+	for _, spec := range specs {
+		if spec.Name == legacyStreamName {
+			return nil
+		}
+	}
+	if _, err := b.js.StreamInfo(legacyStreamName); err != nil {
+		return nil
+	}
+	if os.Getenv("NATS_DELETE_LEGACY_STREAM") != "true" {
+		return fmt.Errorf(
+			"legacy stream %s overlaps the configured topology; drain it and set NATS_DELETE_LEGACY_STREAM=true",
+			legacyStreamName,
+		)
+	}
+	if err := b.js.DeleteStream(legacyStreamName); err != nil {
+		return fmt.Errorf("delete legacy stream %s: %w", legacyStreamName, err)
+	}
+	return nil
+}
+
+func streamConfigMatches(current, desired nats.StreamConfig) bool {
+	return slices.Equal(current.Subjects, desired.Subjects) &&
+		current.Storage == desired.Storage &&
+		current.Retention == desired.Retention &&
+		current.Discard == desired.Discard &&
+		current.MaxAge == desired.MaxAge &&
+		current.MaxBytes == desired.MaxBytes &&
+		current.Replicas == desired.Replicas
+}
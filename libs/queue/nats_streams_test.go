@@ -0,0 +1,87 @@
+package queue
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+func subjectMatches(pattern, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+	for i, token := range patternTokens {
+		if token == ">" {
+			return len(subjectTokens) > i
+		}
+		if i >= len(subjectTokens) || (token != "*" && token != subjectTokens[i]) {
+			return false
+		}
+	}
+	return len(patternTokens) == len(subjectTokens)
+}
+
+func TestDefaultStreamTopologyCoversEveryPlatformTopic(t *testing.T) {
+	specs := DefaultStreamTopology()
+	if err := validateTopology(specs); err != nil {
+		t.Fatalf("default topology invalid: %v", err)
+	}
+	topics := []string{
+		"analytics.rollup.v1", "consent.verified.v1", "episode.published.v1", "media.approved.v1",
+		"media.reviewed.v1", "media.transcode.requested.v1", "media.transcoded.v1", "media.uploaded.v1",
+		"parent.controls.updated.v1", "parent.gate.challenge.v1", "child.session.capped.v1",
+		"playback.session.ended.v1", "playback.session.started.v1", "reco.refresh.requested.v1",
+		"safety.filter.applied.v1", "ux.flow.completed.v1", "video.asset.ready.v1", "video.run.failed.v1",
+		"video.run.requested.v1", "video.run.step.completed.v1", "video.workflow.created.v1", "watch.event.v1",
+	}
+	for _, topic := range append(topics, DLQTopic("video.run.requested.v1")) {
+		owners := 0
+		for _, spec := range specs {
+			for _, subject := range spec.Subjects {
+				if subjectMatches(subject, topic) {
+					owners++
+				}
+			}
+		}
+		if owners != 1 {
+			t.Fatalf("topic %s must belong to exactly one stream, got %d", topic, owners)
+		}
+	}
+}
+
+func TestParseStreamTopologyFromJSON(t *testing.T) {
+	specs, err := ParseStreamTopology([]byte(`[
+		{"name":"MM_GENERATION","subjects":["video.>"],"max_age":"336h","max_bytes":1024,"replicas":3,"discard":"new"},
+		{"name":"MM_ANALYTICS","subjects":["watch.>"],"max_age":"2h","storage":"memory"}
+	]`))
+	if err != nil {
+		t.Fatalf("parse topology: %v", err)
+	}
+	cfg, err := specs[0].natsConfig()
+	if err != nil {
+		t.Fatalf("nats config: %v", err)
+	}
+	if cfg.MaxAge != 336*time.Hour || cfg.Replicas != 3 || cfg.Discard != nats.DiscardNew || cfg.MaxBytes != 1024 {
+		t.Fatalf("unexpected generation stream config: %+v", cfg)
+	}
+	analytics, _ := specs[1].natsConfig()
+	if analytics.Storage != nats.MemoryStorage || analytics.MaxBytes != -1 || analytics.Replicas != 1 {
+		t.Fatalf("unexpected analytics stream config: %+v", analytics)
+	}
+}
+
+func TestParseStreamTopologyRejectsOverlapsAndBadPolicies(t *testing.T) {
+	cases := []string{
+		`[{"name":"A","subjects":["media.>"]},{"name":"B","subjects":["media.>"]}]`,
+		`[{"name":"A","subjects":[">"]},{"name":"B","subjects":["video.>"]}]`,
+		`[{"name":"A","subjects":["media.>"],"discard":"sometimes"}]`,
+		`[{"name":"A","subjects":["media.>"],"max_age":"forever"}]`,
+		`[]`,
+	}
+	for _, raw := range cases {
+		if _, err := ParseStreamTopology([]byte(raw)); err == nil {
+			t.Fatalf("expected error for topology %s", raw)
+		}
+	}
+}
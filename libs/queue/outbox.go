@@ -1,6 +1,9 @@
 package queue
 
-import "context"
+import (
+	"context"
+	"sync"
+)
 
 type OutboxItem struct {
 	Event Event
@@ -8,6 +11,7 @@ type OutboxItem struct {
 }
 
 type Outbox struct {
+	mu    sync.Mutex
 	items []OutboxItem
 }
 
@@ -15,11 +19,18 @@ func NewOutbox() *Outbox {
 	return &Outbox{items: []OutboxItem{}}
 }
 
-func (o *Outbox) Add(event Event) {
-	o.items = append(o.items, OutboxItem{Event: event})
+func (o *Outbox) Add(events ...Event) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, event := range events {
+		o.items = append(o.items, OutboxItem{Event: event})
+	}
 }
 
 func (o *Outbox) Flush(ctx context.Context, bus Bus) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	defer o.compact()
 	for i := range o.items {
 		if o.items[i].Sent {
 			continue
@@ -31,3 +42,13 @@ func (o *Outbox) Flush(ctx context.Context, bus Bus) error {
 	}
 	return nil
 }
+
+func (o *Outbox) compact() {
+	pending := o.items[:0]
+	for _, item := range o.items {
+		if !item.Sent {
+			pending = append(pending, item)
+		}
+	}
+	o.items = pending
+}
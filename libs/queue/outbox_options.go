@@ -0,0 +1,112 @@
+package queue
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultOutboxLease = 30 * time.Second
+)
+
+type OutboxOption func(*outboxConfig)
+
+type outboxConfig struct {
+	owner     string
+	lease     time.Duration
+	batchSize int
+	topics    []string
+	retry     OutboxRetryPolicies
+}
+
+func newOutboxConfig(opts []OutboxOption) outboxConfig {
+	cfg := outboxConfig{lease: defaultOutboxLease, batchSize: outboxBatchSize, retry: DefaultOutboxRetryPolicies()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.owner == "" {
+		cfg.owner = defaultOutboxOwner()
+	}
+	return cfg
+}
+
+func WithOutboxOwner(owner string) OutboxOption {
+	return func(cfg *outboxConfig) {
+		cfg.owner = strings.TrimSpace(owner)
+	}
+}
+
+func WithOutboxLease(lease time.Duration) OutboxOption {
+	return func(cfg *outboxConfig) {
+		if lease > 0 {
+			cfg.lease = lease
+		}
+	}
+}
+
+func WithOutboxBatchSize(size int) OutboxOption {
+	return func(cfg *outboxConfig) {
+		if size > 0 {
+			cfg.batchSize = size
+		}
+	}
+}
+
+func WithOutboxTopics(prefixes ...string) OutboxOption {
+	return func(cfg *outboxConfig) {
+		cfg.topics = append(cfg.topics, prefixes...)
+	}
+}
+
+func WithOutboxRetryPolicies(policies OutboxRetryPolicies) OutboxOption {
+	return func(cfg *outboxConfig) {
+		cfg.retry = policies
+	}
+}
+
+func OutboxOptionsFromEnv(getenv func(string) string) []OutboxOption {
+	opts := []OutboxOption{WithOutboxOwner(getenv("OUTBOX_RELAY_ID"))}
+	if value, ok := positiveIntEnv(getenv, "OUTBOX_RELAY_LEASE_MS"); ok {
+		opts = append(opts, WithOutboxLease(time.Duration(value)*time.Millisecond))
+	}
+	if value, ok := positiveIntEnv(getenv, "OUTBOX_RELAY_BATCH_SIZE"); ok {
+		opts = append(opts, WithOutboxBatchSize(value))
+	}
+	var topics []string
+	for _, prefix := range strings.Split(getenv("OUTBOX_RELAY_TOPICS"), ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			topics = append(topics, prefix)
+		}
+	}
+	if len(topics) > 0 {
+		opts = append(opts, WithOutboxTopics(topics...))
+	}
+	return opts
+}
+
+func (c outboxConfig) topicPatterns() []string {
+	patterns := make([]string, 0, len(c.topics))
+	for _, prefix := range c.topics {
+		escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix)
+		if strings.HasSuffix(escaped, ".>") {
+			escaped = strings.TrimSuffix(escaped, ">")
+		}
+		if strings.HasSuffix(escaped, ".") {
+			patterns = append(patterns, escaped+"%")
+			continue
+		}
+		patterns = append(patterns, escaped)
+	}
+	return patterns
+}
+
+func defaultOutboxOwner() string {
+	host, _ := os.Hostname()
+	if host == "" {
+		host = "outbox-relay"
+	}
+	return host + "-" + uuid.NewString()[:8]
+}
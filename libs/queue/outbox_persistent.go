@@ -1,6 +1,7 @@
 package queue
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -8,10 +9,11 @@ import (
 )
 
 type PersistentOutbox struct {
-	db *sql.DB
+	db  *sql.DB
+	cfg outboxConfig
 }
 
-func NewPersistentOutbox(databaseURL string) (*PersistentOutbox, error) {
+func NewPersistentOutbox(databaseURL string, opts ...OutboxOption) (*PersistentOutbox, error) {
 	db, err := sql.Open("pgx", databaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("open postgres: %w", err)
@@ -20,25 +22,20 @@ func NewPersistentOutbox(databaseURL string) (*PersistentOutbox, error) {
 		_ = db.Close()
 		return nil, fmt.Errorf("ping postgres: %w", err)
 	}
-	return &PersistentOutbox{db: db}, nil
+	return &PersistentOutbox{db: db, cfg: newOutboxConfig(opts)}, nil
 }
 
-func (o *PersistentOutbox) Add(event Event) error {
-	if event.ID == "" {
-		return fmt.Errorf("event id is required for persistent outbox")
-	}
-	_, err := o.db.Exec(
-		`insert into events.outbox (event_id, topic, payload, status, available_at)
-		 values ($1, $2, $3, 'pending', now())
-		 on conflict (event_id) do nothing`,
-		event.ID, event.Topic, event.Payload,
-	)
-	if err != nil {
-		return fmt.Errorf("insert outbox event: %w", err)
-	}
-	return nil
+func (o *PersistentOutbox) Add(ctx context.Context, event Event) error {
+	return insertOutboxEvent(ctx, o.db, event)
 }
 
 func (o *PersistentOutbox) Close() error {
 	return o.db.Close()
 }
+
+func (o *PersistentOutbox) Ping(ctx context.Context) error {
+	if err := o.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping outbox database: %w", err)
+	}
+	return nil
+}
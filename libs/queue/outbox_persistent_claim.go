@@ -0,0 +1,79 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+const outboxClaimLockKey = 7415001
+
+func (o *PersistentOutbox) claim(ctx context.Context) ([]pendingOutboxRow, error) {
+	tx, err := o.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin outbox claim tx: %w", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `select pg_advisory_xact_lock($1)`, outboxClaimLockKey); err != nil {
+		return nil, fmt.Errorf("lock outbox claim: %w", err)
+	}
+	rows, err := tx.QueryContext(
+		ctx,
+		`with claimable as (
+		   select o.id
+		   from events.outbox o
+		   where o.status = 'pending'
+		     and o.available_at <= now()
+		     and (o.locked_until is null or o.locked_until < now())
+		     and (cardinality($4::text[]) = 0 or o.topic like any($4::text[]))
+		     and not exists (
+		       select 1
+		       from events.outbox prior
+		       where o.partition_key is not null
+		         and prior.partition_key = o.partition_key
+		         and prior.status = 'pending'
+		         and prior.id < o.id
+		         and (
+		           prior.available_at > now()
+		           or prior.locked_until >= now()
+		           or not (cardinality($4::text[]) = 0 or prior.topic like any($4::text[]))
+		         )
+		     )
+		   order by o.id asc
+		   limit $3
+		   for update skip locked
+		 )
+		 update events.outbox o
+		 set locked_by = $1,
+		     locked_until = now() + $2 * interval '1 millisecond'
+		 from claimable
+		 where o.id = claimable.id
+		 returning o.id, o.event_id, o.topic, o.payload, o.headers, o.attempts, coalesce(o.partition_key, ''), o.created_at`,
+		o.cfg.owner, o.cfg.lease.Milliseconds(), o.cfg.batchSize, o.cfg.topicPatterns(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("claim outbox events: %w", err)
+	}
+	defer rows.Close()
+	claimed := make([]pendingOutboxRow, 0, 32)
+	for rows.Next() {
+		var row pendingOutboxRow
+		var headers []byte
+		if err := rows.Scan(&row.ID, &row.EventID, &row.Topic, &row.Payload, &headers, &row.Attempts, &row.PartitionKey, &row.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan claimed outbox event: %w", err)
+		}
+		if err := json.Unmarshal(headers, &row.Headers); err != nil {
+			return nil, fmt.Errorf("decode claimed outbox headers: %w", err)
+		}
+		claimed = append(claimed, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate claimed outbox events: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit outbox claim: %w", err)
+	}
+	sort.Slice(claimed, func(i, j int) bool { return claimed[i].ID < claimed[j].ID })
+	return claimed, nil
+}
@@ -2,7 +2,6 @@ package queue
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -14,153 +13,143 @@ const (
 )
 
 type pendingOutboxRow struct {
-	ID       int64
-	EventID  string
-	Topic    string
-	Payload  []byte
-	Attempts int
+	ID           int64
+	EventID      string
+	Topic        string
+	Payload      []byte
+	Headers      Headers
+	Attempts     int
+	PartitionKey string
+	CreatedAt    time.Time
+}
+
+type outboxRowStore interface {
+	markPublished(ctx context.Context, rowID int64) error
+	markRetryPending(ctx context.Context, rowID int64, publishErr error, availableAt time.Time) error
+	markTerminalFailed(ctx context.Context, rowID int64, publishErr error) error
+	release(ctx context.Context, rowID int64) error
 }
 
 func (o *PersistentOutbox) Flush(ctx context.Context, bus Bus) error {
-	pending, err := o.pendingEvents(ctx)
+	claimed, err := o.claim(ctx)
 	if err != nil {
 		return err
 	}
-	for _, row := range pending {
-		if publishErr := bus.Publish(ctx, Event{
-			ID:      row.EventID,
-			Topic:   row.Topic,
-			Payload: row.Payload,
-		}); publishErr != nil {
-			if row.Attempts+1 >= outboxMaxAttempts {
-				if err := o.markTerminalFailed(ctx, row.ID, publishErr); err != nil {
-					return err
-				}
-				if err := o.publishDLQ(ctx, bus, row, publishErr); err != nil {
-					return err
-				}
-				continue
+	return relayRows(ctx, o, bus, claimed, o.cfg.retry)
+}
+
+func relayRows(ctx context.Context, store outboxRowStore, bus Bus, rows []pendingOutboxRow, policies OutboxRetryPolicies) error {
+	blocked := map[string]bool{}
+	for _, row := range rows {
+		if row.PartitionKey != "" && blocked[row.PartitionKey] {
+			if err := store.release(ctx, row.ID); err != nil {
+				return err
 			}
-			if err := o.markRetryPending(ctx, row.ID, publishErr); err != nil {
+			continue
+		}
+		publishErr := bus.Publish(ctx, row.event())
+		if publishErr == nil {
+			if err := store.markPublished(ctx, row.ID); err != nil {
 				return err
 			}
 			continue
 		}
-		if err := o.markPublished(ctx, row.ID); err != nil {
+		availableAt, retry := policies.For(row.Topic).NextRetry(row.Attempts+1, row.CreatedAt, time.Now().UTC())
+		if !retry {
+			if err := store.markTerminalFailed(ctx, row.ID, publishErr); err != nil {
+				return err
+			}
+			if err := publishOutboxDLQ(ctx, bus, row, publishErr); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := store.markRetryPending(ctx, row.ID, publishErr, availableAt); err != nil {
 			return err
 		}
+		if row.PartitionKey != "" {
+			blocked[row.PartitionKey] = true
+		}
 	}
 	return nil
 }
 
-func (o *PersistentOutbox) pendingEvents(ctx context.Context) ([]pendingOutboxRow, error) {
-	rows, err := o.db.QueryContext(
-		ctx,
-		`select id, event_id, topic, payload, attempts
-		 from events.outbox
-		 where status = 'pending'
-		   and available_at <= now()
-		 order by id asc
-		 limit $1`,
-		outboxBatchSize,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("query pending outbox events: %w", err)
-	}
-	defer rows.Close()
-	result := make([]pendingOutboxRow, 0, 32)
-	for rows.Next() {
-		var row pendingOutboxRow
-		if err := rows.Scan(&row.ID, &row.EventID, &row.Topic, &row.Payload, &row.Attempts); err != nil {
-			return nil, fmt.Errorf("scan pending outbox event: %w", err)
-		}
-		result = append(result, row)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate pending outbox events: %w", err)
-	}
-	return result, nil
+func (r pendingOutboxRow) event() Event {
+	return Event{ID: r.EventID, Topic: r.Topic, Payload: r.Payload, Headers: r.Headers}
 }
 
 func (o *PersistentOutbox) markPublished(ctx context.Context, rowID int64) error {
-	_, err := o.db.ExecContext(
+	return o.settle(
 		ctx,
+		"mark outbox published",
 		`update events.outbox
 		 set status = 'published',
 		     attempts = attempts + 1,
 		     last_error = null,
-		     published_at = now()
-		 where id = $1`,
-		rowID,
+		     published_at = now(),
+		     locked_by = null,
+		     locked_until = null
+		 where id = $1 and locked_by = $2`,
+		rowID, o.cfg.owner,
 	)
-	if err != nil {
-		return fmt.Errorf("mark outbox published: %w", err)
-	}
-	return nil
 }
 
-func (o *PersistentOutbox) markRetryPending(ctx context.Context, rowID int64, publishErr error) error {
-	_, err := o.db.ExecContext(
+func (o *PersistentOutbox) markRetryPending(ctx context.Context, rowID int64, publishErr error, availableAt time.Time) error {
+	return o.settle(
 		ctx,
+		"mark outbox retry pending",
 		`update events.outbox
 		 set status = 'pending',
 		     attempts = attempts + 1,
-		     last_error = $2,
-		     available_at = $3
-		 where id = $1`,
-		rowID,
-		publishErr.Error(),
-		time.Now().UTC().Add(outboxRetryDelay),
+		     last_error = $3,
+		     available_at = $4,
+		     locked_by = null,
+		     locked_until = null
+		 where id = $1 and locked_by = $2`,
+		rowID, o.cfg.owner, publishErr.Error(), availableAt,
 	)
-	if err != nil {
-		return fmt.Errorf("mark outbox retry pending: %w", err)
-	}
-	return nil
 }
 
 func (o *PersistentOutbox) markTerminalFailed(ctx context.Context, rowID int64, publishErr error) error {
-	_, err := o.db.ExecContext(
+	return o.settle(
 		ctx,
+		"mark outbox terminal failed",
 		`update events.outbox
 		 set status = 'failed',
 		     attempts = attempts + 1,
-		     last_error = $2
-		 where id = $1`,
-		rowID,
-		publishErr.Error(),
+		     last_error = $3,
+		     locked_by = null,
+		     locked_until = null
+		 where id = $1 and locked_by = $2`,
+		rowID, o.cfg.owner, publishErr.Error(),
 	)
-	if err != nil {
-		return fmt.Errorf("mark outbox terminal failed: %w", err)
-	}
-	return nil
 }
 
-type outboxDLQEvent struct {
-	EventID       string          `json:"event_id"`
-	OriginalTopic string          `json:"original_topic"`
-	Error         string          `json:"error"`
-	Attempts      int             `json:"attempts"`
-	FailedAt      string          `json:"failed_at"`
-	Payload       json.RawMessage `json:"payload"`
+func (o *PersistentOutbox) release(ctx context.Context, rowID int64) error {
+	return o.settle(
+		ctx,
+		"release outbox claim",
+		`update events.outbox
+		 set locked_by = null,
+		     locked_until = null
+		 where id = $1 and locked_by = $2`,
+		rowID, o.cfg.owner,
+	)
 }
 
-func (o *PersistentOutbox) publishDLQ(ctx context.Context, bus Bus, row pendingOutboxRow, publishErr error) error {
-	payload, err := json.Marshal(outboxDLQEvent{
-		EventID:       row.EventID,
-		OriginalTopic: row.Topic,
-		Error:         publishErr.Error(),
-		Attempts:      row.Attempts + 1,
-		FailedAt:      time.Now().UTC().Format(time.RFC3339),
-		Payload:       json.RawMessage(row.Payload),
-	})
+func (o *PersistentOutbox) settle(ctx context.Context, action, query string, args ...any) error {
+	if _, err := o.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	return nil
+}
+
+func publishOutboxDLQ(ctx context.Context, bus Bus, row pendingOutboxRow, publishErr error) error {
+	event, err := newDLQEvent(row.event(), "", publishErr, row.Attempts+1)
 	if err != nil {
-		return fmt.Errorf("marshal outbox dlq payload: %w", err)
+		return err
 	}
-	if err := bus.Publish(ctx, Event{
-		ID:      row.EventID + "-dlq",
-		Topic:   row.Topic + ".dlq.v1",
-		Payload: payload,
-	}); err != nil {
+	if err := bus.Publish(ctx, event); err != nil {
 		return fmt.Errorf("publish outbox dlq event: %w", err)
 	}
 	return nil
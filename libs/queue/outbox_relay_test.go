@@ -0,0 +1,124 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type recordingRowStore struct {
+	published []int64
+	retried   []int64
+	failed    []int64
+	released  []int64
+	nextAt    []time.Time
+}
+
+func (s *recordingRowStore) markPublished(_ context.Context, rowID int64) error {
+	s.published = append(s.published, rowID)
+	return nil
+}
+
+func (s *recordingRowStore) markRetryPending(_ context.Context, rowID int64, _ error, availableAt time.Time) error {
+	s.retried = append(s.retried, rowID)
+	s.nextAt = append(s.nextAt, availableAt)
+	return nil
+}
+
+func (s *recordingRowStore) markTerminalFailed(_ context.Context, rowID int64, _ error) error {
+	s.failed = append(s.failed, rowID)
+	return nil
+}
+
+func (s *recordingRowStore) release(_ context.Context, rowID int64) error {
+	s.released = append(s.released, rowID)
+	return nil
+}
+
+type eventFailingBus struct {
+	*InMemoryBus
+	failID string
+}
+
+func (b eventFailingBus) Publish(ctx context.Context, event Event) error {
+	if event.ID == b.failID {
+		return errors.New("bus unavailable")
+	}
+	return b.InMemoryBus.Publish(ctx, event)
+}
+
+func TestRelayRowsHoldsBackLaterRowsOfFailedKey(t *testing.T) {
+	bus := eventFailingBus{InMemoryBus: NewInMemoryBus(), failID: "evt-1"}
+	defer bus.Close()
+	store := &recordingRowStore{}
+	rows := []pendingOutboxRow{
+		{ID: 1, EventID: "evt-1", Topic: "video.run.requested.v1", PartitionKey: "run-1"},
+		{ID: 2, EventID: "evt-2", Topic: "video.run.requested.v1", PartitionKey: "run-2"},
+		{ID: 3, EventID: "evt-3", Topic: "video.run.requested.v1", PartitionKey: "run-1"},
+		{ID: 4, EventID: "evt-4", Topic: "video.run.requested.v1"},
+	}
+	if err := relayRows(context.Background(), store, bus, rows, DefaultOutboxRetryPolicies()); err != nil {
+		t.Fatalf("relay rows: %v", err)
+	}
+	if !reflect.DeepEqual(store.published, []int64{2, 4}) {
+		t.Fatalf("expected rows 2 and 4 published, got %v", store.published)
+	}
+	if !reflect.DeepEqual(store.retried, []int64{1}) || !reflect.DeepEqual(store.released, []int64{3}) {
+		t.Fatalf("expected row 1 retried and row 3 released, got retried=%v released=%v", store.retried, store.released)
+	}
+}
+
+func TestRelayRowsDeadLettersAfterMaxAttempts(t *testing.T) {
+	bus := eventFailingBus{InMemoryBus: NewInMemoryBus(), failID: "evt-1"}
+	defer bus.Close()
+	store := &recordingRowStore{}
+	rows := []pendingOutboxRow{
+		{ID: 1, EventID: "evt-1", Topic: "media.uploaded.v1", PartitionKey: "asset-1", Attempts: outboxMaxAttempts - 1},
+		{ID: 2, EventID: "evt-2", Topic: "media.uploaded.v1", PartitionKey: "asset-1"},
+	}
+	if err := relayRows(context.Background(), store, bus, rows, DefaultOutboxRetryPolicies()); err != nil {
+		t.Fatalf("relay rows: %v", err)
+	}
+	if !reflect.DeepEqual(store.failed, []int64{1}) || !reflect.DeepEqual(store.published, []int64{2}) {
+		t.Fatalf("expected row 1 dead-lettered and row 2 published, got failed=%v published=%v", store.failed, store.published)
+	}
+}
+
+func TestOutboxOptionsFromEnv(t *testing.T) {
+	cfg := newOutboxConfig(OutboxOptionsFromEnv(envMap(map[string]string{
+		"OUTBOX_RELAY_ID":         "relay-a",
+		"OUTBOX_RELAY_LEASE_MS":   "5000",
+		"OUTBOX_RELAY_BATCH_SIZE": "25",
+		"OUTBOX_RELAY_TOPICS":     "video.>, media., consent.verified.v1,,",
+	})))
+	if cfg.owner != "relay-a" || cfg.lease != 5*time.Second || cfg.batchSize != 25 {
+		t.Fatalf("unexpected outbox config: %+v", cfg)
+	}
+	want := []string{"video.%", "media.%", `consent.verified.v1`}
+	if !reflect.DeepEqual(cfg.topicPatterns(), want) {
+		t.Fatalf("expected patterns %v, got %v", want, cfg.topicPatterns())
+	}
+}
+
+func TestOutboxConfigDefaultsOwnerAndBatch(t *testing.T) {
+	cfg := newOutboxConfig(nil)
+	if cfg.owner == "" || cfg.batchSize != outboxBatchSize || cfg.lease != defaultOutboxLease {
+		t.Fatalf("unexpected defaults: %+v", cfg)
+	}
+	if len(cfg.topicPatterns()) != 0 {
+		t.Fatalf("expected no topic filter by default")
+	}
+}
+
+func TestEventWithOrderingKeyDoesNotMutateOriginal(t *testing.T) {
+	original := Event{ID: "evt-1", Headers: Headers{HeaderTraceID: "trace-1"}}
+	keyed := original.WithOrderingKey("run-1")
+	if keyed.Headers.OrderingKey() != "run-1" || keyed.Headers.TraceID() != "trace-1" {
+		t.Fatalf("unexpected keyed headers: %+v", keyed.Headers)
+	}
+	if original.Headers.OrderingKey() != "" {
+		t.Fatalf("original headers must not change")
+	}
+}
@@ -0,0 +1,144 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+type OutboxRetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Multiplier  float64
+	Jitter      float64
+	MaxAge      time.Duration
+}
+
+type TopicRetryPolicy struct {
+	Topic  string
+	Policy OutboxRetryPolicy
+}
+
+type OutboxRetryPolicies struct {
+	Default OutboxRetryPolicy
+	Topics  []TopicRetryPolicy
+}
+
+func DefaultOutboxRetryPolicy() OutboxRetryPolicy {
+	return OutboxRetryPolicy{
+		MaxAttempts: outboxMaxAttempts,
+		BaseDelay:   outboxRetryDelay,
+		MaxDelay:    30 * time.Minute,
+		Multiplier:  2,
+		Jitter:      0.2,
+	}
+}
+
+func DefaultOutboxRetryPolicies() OutboxRetryPolicies {
+	return OutboxRetryPolicies{Default: DefaultOutboxRetryPolicy()}
+}
+
+func (p OutboxRetryPolicies) For(topic string) OutboxRetryPolicy {
+	policy, matched := p.Default, -1
+	for _, entry := range p.Topics {
+		if matchTopicPattern(entry.Topic, topic) && len(entry.Topic) > matched {
+			policy, matched = entry.Policy, len(entry.Topic)
+		}
+	}
+	return policy
+}
+
+func (p OutboxRetryPolicy) Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := float64(p.BaseDelay) * math.Pow(p.Multiplier, float64(attempts-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	if delay > math.MaxInt64/2 {
+		return time.Duration(math.MaxInt64 / 2)
+	}
+	return time.Duration(delay)
+}
+
+func (p OutboxRetryPolicy) BackoffWindow(attempts int) (time.Duration, time.Duration) {
+	delay := p.Backoff(attempts)
+	spread := time.Duration(float64(delay) * p.Jitter)
+	return delay - spread, delay + spread
+}
+
+func (p OutboxRetryPolicy) Expires(createdAt time.Time) (time.Time, bool) {
+	if p.MaxAge <= 0 {
+		return time.Time{}, false
+	}
+	return createdAt.Add(p.MaxAge), true
+}
+
+func (p OutboxRetryPolicy) NextRetry(attempts int, createdAt, now time.Time) (time.Time, bool) {
+	return p.nextRetry(attempts, createdAt, now, rand.Float64())
+}
+
+func (p OutboxRetryPolicy) nextRetry(attempts int, createdAt, now time.Time, random float64) (time.Time, bool) {
+	if p.MaxAttempts > 0 && attempts >= p.MaxAttempts {
+		return time.Time{}, false
+	}
+	low, high := p.BackoffWindow(attempts)
+	next := now.Add(low + time.Duration(float64(high-low)*random))
+	if expires, ok := p.Expires(createdAt); ok && next.After(expires) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+func (p OutboxRetryPolicy) validate() error {
+	switch {
+	case p.MaxAttempts < 1:
+		return errors.New("max_attempts must be >= 1")
+	case p.BaseDelay <= 0:
+		return errors.New("base_delay must be > 0")
+	case p.MaxDelay < p.BaseDelay:
+		return errors.New("max_delay must be >= base_delay")
+	case p.Multiplier < 1:
+		return errors.New("multiplier must be >= 1")
+	case p.Jitter < 0 || p.Jitter >= 1:
+		return errors.New("jitter must be in [0, 1)")
+	case p.MaxAge < 0:
+		return errors.New("max_age must be >= 0")
+	}
+	return nil
+}
+
+func (p OutboxRetryPolicies) validate() error {
+	if err := p.Default.validate(); err != nil {
+		return fmt.Errorf("default retry policy: %w", err)
+	}
+	seen := map[string]bool{}
+	for _, entry := range p.Topics {
+		if strings.TrimSpace(entry.Topic) == "" {
+			return errors.New("retry policy topic is required")
+		}
+		if seen[entry.Topic] {
+			return fmt.Errorf("duplicate retry policy for %s", entry.Topic)
+		}
+		seen[entry.Topic] = true
+		if err := entry.Policy.validate(); err != nil {
+			return fmt.Errorf("retry policy %s: %w", entry.Topic, err)
+		}
+	}
+	return nil
+}
+
+func matchTopicPattern(pattern, topic string) bool {
+	if strings.HasSuffix(pattern, ".>") {
+		pattern = strings.TrimSuffix(pattern, ">")
+	}
+	if strings.HasSuffix(pattern, ".") {
+		return strings.HasPrefix(topic, pattern)
+	}
+	return pattern == topic
+}
@@ -0,0 +1,95 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+type retryPolicyFile struct {
+	Topic       string   `json:"topic"`
+	MaxAttempts int      `json:"max_attempts"`
+	BaseDelay   string   `json:"base_delay"`
+	MaxDelay    string   `json:"max_delay"`
+	Multiplier  float64  `json:"multiplier"`
+	Jitter      *float64 `json:"jitter"`
+	MaxAge      string   `json:"max_age"`
+}
+
+type retryPoliciesFile struct {
+	Default retryPolicyFile   `json:"default"`
+	Topics  []retryPolicyFile `json:"topics"`
+}
+
+func OutboxRetryPoliciesFromEnv() (OutboxRetryPolicies, error) {
+	return outboxRetryPoliciesFromEnv(os.Getenv, os.ReadFile)
+}
+
+func outboxRetryPoliciesFromEnv(getenv func(string) string, readFile func(string) ([]byte, error)) (OutboxRetryPolicies, error) {
+	path := strings.TrimSpace(getenv("OUTBOX_RETRY_POLICIES_FILE"))
+	if path == "" {
+		return DefaultOutboxRetryPolicies(), nil
+	}
+	raw, err := readFile(path)
+	if err != nil {
+		return OutboxRetryPolicies{}, fmt.Errorf("read OUTBOX_RETRY_POLICIES_FILE: %w", err)
+	}
+	return ParseOutboxRetryPolicies(raw)
+}
+
+func ParseOutboxRetryPolicies(raw []byte) (OutboxRetryPolicies, error) {
+	var file retryPoliciesFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return OutboxRetryPolicies{}, fmt.Errorf("decode outbox retry policies: %w", err)
+	}
+	base, err := file.Default.apply(DefaultOutboxRetryPolicy())
+	if err != nil {
+		return OutboxRetryPolicies{}, fmt.Errorf("default retry policy: %w", err)
+	}
+	policies := OutboxRetryPolicies{Default: base, Topics: make([]TopicRetryPolicy, 0, len(file.Topics))}
+	for _, entry := range file.Topics {
+		policy, err := entry.apply(base)
+		if err != nil {
+			return OutboxRetryPolicies{}, fmt.Errorf("retry policy %s: %w", entry.Topic, err)
+		}
+		policies.Topics = append(policies.Topics, TopicRetryPolicy{Topic: strings.TrimSpace(entry.Topic), Policy: policy})
+	}
+	if err := policies.validate(); err != nil {
+		return OutboxRetryPolicies{}, err
+	}
+	return policies, nil
+}
+
+func (f retryPolicyFile) apply(policy OutboxRetryPolicy) (OutboxRetryPolicy, error) {
+	if f.MaxAttempts != 0 {
+		policy.MaxAttempts = f.MaxAttempts
+	}
+	if f.Multiplier != 0 {
+		policy.Multiplier = f.Multiplier
+	}
+	if f.Jitter != nil {
+		policy.Jitter = *f.Jitter
+	}
+	durations := []struct {
+		name   string
+		raw    string
+		target *time.Duration
+	}{
+		{"base_delay", f.BaseDelay, &policy.BaseDelay},
+		{"max_delay", f.MaxDelay, &policy.MaxDelay},
+		{"max_age", f.MaxAge, &policy.MaxAge},
+	}
+	for _, field := range durations {
+		if field.raw == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(field.raw)
+		if err != nil {
+			return OutboxRetryPolicy{}, fmt.Errorf("parse %s: %w", field.name, err)
+		}
+		*field.target = parsed
+	}
+	return policy, nil
+}
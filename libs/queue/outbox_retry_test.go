@@ -0,0 +1,119 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOutboxRetryPolicyBacksOffExponentiallyUpToMaxDelay(t *testing.T) {
+	policy := OutboxRetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: 10 * time.Second, Multiplier: 2}
+	var got []time.Duration
+	for attempts := 1; attempts <= 6; attempts++ {
+		got = append(got, policy.Backoff(attempts))
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected backoff %v, got %v", want, got)
+	}
+}
+
+func TestOutboxRetryPolicyNextRetryHonorsJitterAttemptsAndAge(t *testing.T) {
+	policy := OutboxRetryPolicy{MaxAttempts: 5, BaseDelay: 10 * time.Second, MaxDelay: time.Minute, Multiplier: 2, Jitter: 0.5, MaxAge: time.Minute}
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	low, _ := policy.nextRetry(2, created, created, 0)
+	high, _ := policy.nextRetry(2, created, created, 1)
+	if low != created.Add(10*time.Second) || high != created.Add(30*time.Second) {
+		t.Fatalf("expected jitter window [10s, 30s], got [%s, %s]", low.Sub(created), high.Sub(created))
+	}
+	if _, ok := policy.nextRetry(5, created, created, 0); ok {
+		t.Fatalf("expected no retry once max attempts is reached")
+	}
+	if _, ok := policy.nextRetry(1, created, created.Add(56*time.Second), 0); ok {
+		t.Fatalf("expected no retry past max age")
+	}
+}
+
+func TestParseOutboxRetryPoliciesInheritsDefaultAndMatchesLongestPattern(t *testing.T) {
+	policies, err := ParseOutboxRetryPolicies([]byte(`{
+		"default": {"max_attempts": 6, "base_delay": "5s", "jitter": 0},
+		"topics": [
+			{"topic": "video.>", "max_attempts": 20, "max_age": "24h"},
+			{"topic": "video.run.requested.v1", "base_delay": "1s", "max_delay": "10s"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := policies.For("media.uploaded.v1"); got.MaxAttempts != 6 || got.BaseDelay != 5*time.Second || got.Jitter != 0 {
+		t.Fatalf("unexpected default policy: %+v", got)
+	}
+	if got := policies.For("video.generation.requested.v1"); got.MaxAttempts != 20 || got.MaxAge != 24*time.Hour || got.BaseDelay != 5*time.Second {
+		t.Fatalf("unexpected prefix policy: %+v", got)
+	}
+	if got := policies.For("video.run.requested.v1"); got.MaxAttempts != 6 || got.BaseDelay != time.Second || got.MaxDelay != 10*time.Second {
+		t.Fatalf("unexpected exact policy: %+v", got)
+	}
+}
+
+func TestParseOutboxRetryPoliciesRejectsInvalidPolicies(t *testing.T) {
+	for _, raw := range []string{
+		`{"default": {"base_delay": "soon"}}`,
+		`{"default": {"jitter": 1.5}}`,
+		`{"topics": [{"topic": "video.>", "base_delay": "2h"}]}`,
+		`{"topics": [{"topic": "video.>"}, {"topic": "video.>"}]}`,
+		`{"topics": [{"max_attempts": 3}]}`,
+	} {
+		if _, err := ParseOutboxRetryPolicies([]byte(raw)); err == nil {
+			t.Fatalf("expected %s to be rejected", raw)
+		}
+	}
+}
+
+func TestOutboxRetryPoliciesFromEnvDefaultsWithoutFile(t *testing.T) {
+	readFile := func(string) ([]byte, error) { return nil, errors.New("unexpected read") }
+	policies, err := outboxRetryPoliciesFromEnv(envMap(map[string]string{}), readFile)
+	if err != nil || !reflect.DeepEqual(policies, DefaultOutboxRetryPolicies()) {
+		t.Fatalf("expected default policies, got %+v err=%v", policies, err)
+	}
+}
+
+func TestRelayRowsSchedulesRetryFromTopicPolicy(t *testing.T) {
+	bus := eventFailingBus{InMemoryBus: NewInMemoryBus(), failID: "evt-1"}
+	defer bus.Close()
+	store := &recordingRowStore{}
+	policies := OutboxRetryPolicies{
+		Default: DefaultOutboxRetryPolicy(),
+		Topics: []TopicRetryPolicy{{Topic: "media.>", Policy: OutboxRetryPolicy{
+			MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: time.Hour, Multiplier: 3,
+		}}},
+	}
+	rows := []pendingOutboxRow{{ID: 1, EventID: "evt-1", Topic: "media.uploaded.v1", Attempts: 2, CreatedAt: time.Now()}}
+	before := time.Now()
+	if err := relayRows(context.Background(), store, bus, rows, policies); err != nil {
+		t.Fatalf("relay rows: %v", err)
+	}
+	if len(store.nextAt) != 1 {
+		t.Fatalf("expected row to be scheduled for retry, got %+v", store)
+	}
+	if delay := store.nextAt[0].Sub(before); delay < 9*time.Second || delay > 10*time.Second {
+		t.Fatalf("expected third attempt to back off 9s, got %s", delay)
+	}
+}
+
+func TestRelayRowsDeadLettersRowsPastMaxAge(t *testing.T) {
+	bus := eventFailingBus{InMemoryBus: NewInMemoryBus(), failID: "evt-1"}
+	defer bus.Close()
+	store := &recordingRowStore{}
+	policy := DefaultOutboxRetryPolicy()
+	policy.MaxAge = time.Minute
+	rows := []pendingOutboxRow{{ID: 1, EventID: "evt-1", Topic: "media.uploaded.v1", CreatedAt: time.Now().Add(-time.Hour)}}
+	if err := relayRows(context.Background(), store, bus, rows, OutboxRetryPolicies{Default: policy}); err != nil {
+		t.Fatalf("relay rows: %v", err)
+	}
+	if !reflect.DeepEqual(store.failed, []int64{1}) || len(store.retried) != 0 {
+		t.Fatalf("expected expired row to dead-letter, got failed=%v retried=%v", store.failed, store.retried)
+	}
+}
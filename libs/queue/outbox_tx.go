@@ -0,0 +1,46 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+func NewJSONEvent(ctx context.Context, topic, eventID string, payload any) (Event, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, fmt.Errorf("marshal %s payload: %w", topic, err)
+	}
+	return stampHeaders(ctx, Event{ID: eventID, Topic: topic, Payload: encoded}, ""), nil
+}
+
+func EnqueueOutboxTx(ctx context.Context, tx *sql.Tx, events ...Event) error {
+	for _, event := range events {
+		if err := insertOutboxEvent(ctx, tx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertOutboxEvent(ctx context.Context, execer sqlExecer, event Event) error {
+	if event.ID == "" {
+		return fmt.Errorf("event id is required for persistent outbox")
+	}
+	headers, err := json.Marshal(stampHeaders(ctx, event, "").Headers)
+	if err != nil {
+		return fmt.Errorf("marshal outbox headers: %w", err)
+	}
+	_, err = execer.ExecContext(
+		ctx,
+		`insert into events.outbox (event_id, topic, payload, headers, partition_key, status, available_at)
+		 values ($1, $2, $3, $4, nullif($5, ''), 'pending', now())
+		 on conflict (event_id) do nothing`,
+		event.ID, event.Topic, event.Payload, headers, event.Headers.OrderingKey(),
+	)
+	if err != nil {
+		return fmt.Errorf("insert outbox event: %w", err)
+	}
+	return nil
+}
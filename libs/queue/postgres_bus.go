@@ -0,0 +1,165 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+const (
+	postgresBusChannel      = "mm_bus"
+	postgresBusPollInterval = 1 * time.Second
+)
+
+type sqlExecer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+type PostgresBus struct {
+	db       *sql.DB
+	listener *postgresBusListener
+	done     chan struct{}
+	wg       sync.WaitGroup
+	once     sync.Once
+	producer string
+}
+
+func NewPostgresBus(databaseURL string, opts ...BusOption) (*PostgresBus, error) {
+	db, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("open postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("ping postgres: %w", err)
+	}
+	bus := &PostgresBus{db: db, done: make(chan struct{}), producer: newBusConfig(opts).producer}
+	bus.listener = newPostgresBusListener(databaseURL, bus.done)
+	bus.wg.Add(1)
+	go func() {
+		defer bus.wg.Done()
+		bus.listener.run()
+	}()
+	return bus, nil
+}
+
+func (b *PostgresBus) Publish(ctx context.Context, event Event) error {
+	return publishPostgres(ctx, b.db, stampHeaders(ctx, event, b.producer))
+}
+
+func publishPostgres(ctx context.Context, execer sqlExecer, event Event) error {
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	headers, err := json.Marshal(event.Headers)
+	if err != nil {
+		return fmt.Errorf("marshal bus headers: %w", err)
+	}
+	_, err = execer.ExecContext(
+		ctx,
+		`with inserted as (
+			insert into events.bus_messages (event_id, topic, payload, headers)
+			values ($1, $2, $3, $4)
+			on conflict (event_id) do nothing
+			returning topic
+		)
+		select pg_notify($5, topic) from inserted`,
+		event.ID, event.Topic, event.Payload, headers, postgresBusChannel,
+	)
+	if err != nil {
+		return fmt.Errorf("insert bus message: %w", err)
+	}
+	return nil
+}
+
+func (b *PostgresBus) Subscribe(
+	ctx context.Context,
+	topic string,
+	consumer string,
+	handler Handler,
+	opts ...SubscribeOption,
+) error {
+	cfg := newSubscribeConfig(opts)
+	if consumer == "" || cfg.broadcast {
+		consumer = instanceConsumer(consumer)
+	}
+	if err := b.registerConsumer(ctx, topic, consumer, cfg.deliver); err != nil {
+		return err
+	}
+	member := &postgresConsumer{
+		bus:      b,
+		topic:    topic,
+		consumer: consumer,
+		handler:  handler,
+		cfg:      cfg,
+		wake:     b.listener.register(topic),
+	}
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		member.run(ctx)
+		if cfg.broadcast {
+			b.dropConsumer(topic, consumer)
+		}
+	}()
+	return nil
+}
+
+func (b *PostgresBus) registerConsumer(ctx context.Context, topic, consumer string, position deliverPosition) error {
+	start, args := postgresStartPosition(position)
+	_, err := b.db.ExecContext(
+		ctx,
+		`insert into events.bus_consumers (consumer, topic, last_tx_id, last_message_id)
+		 select $1, $2, coalesce(start.tx_id, pg_snapshot_xmin(pg_current_snapshot())), coalesce(start.id - 1, 0)
+		 from (select 1) seed
+		 left join lateral (`+start+`) start on true
+		 on conflict (consumer, topic) do nothing`,
+		append([]any{consumer, topic}, args...)...,
+	)
+	if err != nil {
+		return fmt.Errorf("register bus consumer: %w", err)
+	}
+	return nil
+}
+
+func (b *PostgresBus) dropConsumer(topic, consumer string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, _ = b.db.ExecContext(
+		ctx,
+		`with redeliveries as (
+		   delete from events.bus_redeliveries where consumer = $1 and topic = $2
+		 )
+		 delete from events.bus_consumers where consumer = $1 and topic = $2`,
+		consumer, topic,
+	)
+}
+
+func postgresStartPosition(position deliverPosition) (string, []any) {
+	switch position.policy {
+	case deliverAll:
+		return `select '0'::xid8 as tx_id, 1::bigint as id`, nil
+	case deliverFromSequence:
+		return `select tx_id, id from events.bus_messages
+			where topic = $2 and id >= $3 order by id limit 1`, []any{int64(position.sequence)}
+	case deliverFromTime:
+		return `select tx_id, id from events.bus_messages
+			where topic = $2 and created_at >= $3 order by created_at, id limit 1`, []any{position.since}
+	default:
+		return `select null::xid8 as tx_id, null::bigint as id`, nil
+	}
+}
+
+func (b *PostgresBus) Close() error {
+	b.once.Do(func() {
+		close(b.done)
+		b.wg.Wait()
+	})
+	return b.db.Close()
+}
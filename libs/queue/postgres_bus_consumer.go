@@ -0,0 +1,214 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type postgresConsumer struct {
+	bus      *PostgresBus
+	topic    string
+	consumer string
+	handler  Handler
+	cfg      subscribeConfig
+	wake     chan struct{}
+}
+
+type postgresDelivery struct {
+	messageID int64
+	txID      string
+	event     Event
+	attempts  int
+}
+
+func (c *postgresConsumer) run(ctx context.Context) {
+	pool := newDispatcher(c.cfg.maxInFlight, c.cfg.orderingKey)
+	defer pool.stop()
+	ticker := time.NewTicker(postgresBusPollInterval)
+	defer ticker.Stop()
+	for {
+		for c.active(ctx) {
+			claimed, err := c.claim(ctx)
+			if err != nil || len(claimed) == 0 {
+				break
+			}
+			c.dispatch(ctx, pool, claimed)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.bus.done:
+			return
+		case <-c.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *postgresConsumer) active(ctx context.Context) bool {
+	select {
+	case <-c.bus.done:
+		return false
+	default:
+		return ctx.Err() == nil
+	}
+}
+
+func (c *postgresConsumer) dispatch(ctx context.Context, pool *dispatcher, claimed []postgresDelivery) {
+	for _, delivery := range claimed {
+		delivery := delivery
+		if !c.active(ctx) {
+			_ = c.reschedule(context.WithoutCancel(ctx), delivery, delivery.attempts, 0, "")
+			continue
+		}
+		lease := newLeaseHeartbeat(c.cfg.ackWait)
+		stop := c.heartbeat(ctx, delivery, lease)
+		pool.submit(delivery.event, func() {
+			c.deliver(contextWithHeartbeat(ctx, lease), delivery, stop)
+		})
+	}
+}
+
+func (c *postgresConsumer) claim(ctx context.Context) ([]postgresDelivery, error) {
+	tx, err := c.bus.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin bus claim tx: %w", err)
+	}
+	defer tx.Rollback()
+	var lastTxID string
+	var lastMessageID int64
+	err = tx.QueryRowContext(
+		ctx,
+		`select last_tx_id::text, last_message_id
+		 from events.bus_consumers
+		 where consumer = $1 and topic = $2
+		 for update skip locked`,
+		c.consumer, c.topic,
+	).Scan(&lastTxID, &lastMessageID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lock bus consumer: %w", err)
+	}
+	claimed, err := c.fetchRedeliveries(ctx, tx, c.cfg.fetchBatch)
+	if err != nil {
+		return nil, err
+	}
+	var fresh []postgresDelivery
+	if remaining := c.cfg.fetchBatch - len(claimed); remaining > 0 {
+		if fresh, err = c.fetchNew(ctx, tx, lastTxID, lastMessageID, remaining); err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, fresh...)
+	}
+	leaseUntil := time.Now().UTC().Add(c.cfg.ackWait)
+	for _, delivery := range claimed {
+		if err := c.lease(ctx, tx, delivery, leaseUntil); err != nil {
+			return nil, err
+		}
+	}
+	if len(fresh) > 0 {
+		last := fresh[len(fresh)-1]
+		if _, err := tx.ExecContext(
+			ctx,
+			`update events.bus_consumers
+			 set last_tx_id = $3::xid8, last_message_id = $4, updated_at = now()
+			 where consumer = $1 and topic = $2`,
+			c.consumer, c.topic, last.txID, last.messageID,
+		); err != nil {
+			return nil, fmt.Errorf("advance bus consumer offset: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit bus claim tx: %w", err)
+	}
+	return claimed, nil
+}
+
+func (c *postgresConsumer) lease(ctx context.Context, tx *sql.Tx, delivery postgresDelivery, until time.Time) error {
+	if _, err := tx.ExecContext(
+		ctx,
+		`insert into events.bus_redeliveries (consumer, topic, message_id, attempts, available_at)
+		 values ($1, $2, $3, $4, $5)
+		 on conflict (consumer, topic, message_id) do update
+		 set available_at = excluded.available_at`,
+		c.consumer, c.topic, delivery.messageID, delivery.attempts, until,
+	); err != nil {
+		return fmt.Errorf("lease bus message: %w", err)
+	}
+	return nil
+}
+
+func (c *postgresConsumer) fetchNew(
+	ctx context.Context,
+	tx *sql.Tx,
+	lastTxID string,
+	lastMessageID int64,
+	limit int,
+) ([]postgresDelivery, error) {
+	rows, err := tx.QueryContext(
+		ctx,
+		`select id, tx_id::text, event_id, topic, payload, headers, 0
+		 from events.bus_messages
+		 where topic = $1
+		   and (tx_id, id) > ($2::xid8, $3)
+		   and tx_id < pg_snapshot_xmin(pg_current_snapshot())
+		 order by tx_id, id
+		 limit $4`,
+		c.topic, lastTxID, lastMessageID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query new bus messages: %w", err)
+	}
+	return scanPostgresDeliveries(rows)
+}
+
+func (c *postgresConsumer) fetchRedeliveries(ctx context.Context, tx *sql.Tx, limit int) ([]postgresDelivery, error) {
+	rows, err := tx.QueryContext(
+		ctx,
+		`select m.id, m.tx_id::text, m.event_id, m.topic, m.payload, m.headers, r.attempts
+		 from events.bus_redeliveries r
+		 join events.bus_messages m on m.id = r.message_id
+		 where r.consumer = $1 and r.topic = $2 and r.available_at <= now()
+		 order by r.message_id
+		 limit $3`,
+		c.consumer, c.topic, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query bus redeliveries: %w", err)
+	}
+	return scanPostgresDeliveries(rows)
+}
+
+func scanPostgresDeliveries(rows *sql.Rows) ([]postgresDelivery, error) {
+	defer rows.Close()
+	result := make([]postgresDelivery, 0, 16)
+	for rows.Next() {
+		var delivery postgresDelivery
+		var headers []byte
+		if err := rows.Scan(
+			&delivery.messageID,
+			&delivery.txID,
+			&delivery.event.ID,
+			&delivery.event.Topic,
+			&delivery.event.Payload,
+			&headers,
+			&delivery.attempts,
+		); err != nil {
+			return nil, fmt.Errorf("scan bus message: %w", err)
+		}
+		if err := json.Unmarshal(headers, &delivery.event.Headers); err != nil {
+			return nil, fmt.Errorf("decode bus message headers: %w", err)
+		}
+		result = append(result, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate bus messages: %w", err)
+	}
+	return result, nil
+}
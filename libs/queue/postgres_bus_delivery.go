@@ -0,0 +1,127 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func (c *postgresConsumer) deliver(ctx context.Context, delivery postgresDelivery, stopHeartbeat func()) {
+	handlerErr := runHandler(ctx, c.handler, delivery.event, c.consumer, c.cfg.handlerTimeout())
+	stopHeartbeat()
+	settleCtx := context.WithoutCancel(ctx)
+	if handlerErr == nil {
+		_ = c.settle(settleCtx, delivery)
+		return
+	}
+	if ctx.Err() != nil {
+		_ = c.reschedule(settleCtx, delivery, delivery.attempts, 0, handlerErr.Error())
+		return
+	}
+	if delay, ok := inProgressDelay(handlerErr, c.cfg.backoffBase); ok {
+		_ = c.reschedule(settleCtx, delivery, delivery.attempts, delay, handlerErr.Error())
+		return
+	}
+	delivered := delivery.attempts + 1
+	if delivered < c.cfg.maxDeliver {
+		_ = c.reschedule(settleCtx, delivery, delivered, c.cfg.redeliveryDelay(delivered), handlerErr.Error())
+		return
+	}
+	if err := c.deadLetter(settleCtx, delivery, delivered, handlerErr); err != nil {
+		_ = c.reschedule(settleCtx, delivery, delivery.attempts, c.cfg.backoffMax, handlerErr.Error())
+	}
+}
+
+func (c *postgresConsumer) heartbeat(ctx context.Context, delivery postgresDelivery, lease *leaseHeartbeat) func() {
+	done := make(chan struct{})
+	interval := c.cfg.heartbeatInterval()
+	if interval <= 0 {
+		return func() {}
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = c.extend(context.WithoutCancel(ctx), delivery)
+				lease.beat()
+			}
+		}
+	}()
+	return func() {
+		close(done)
+	}
+}
+
+func (c *postgresConsumer) extend(ctx context.Context, delivery postgresDelivery) error {
+	if _, err := c.bus.db.ExecContext(
+		ctx,
+		`update events.bus_redeliveries set available_at = $4
+		 where consumer = $1 and topic = $2 and message_id = $3`,
+		c.consumer, c.topic, delivery.messageID, time.Now().UTC().Add(c.cfg.ackWait),
+	); err != nil {
+		return fmt.Errorf("extend bus message lease: %w", err)
+	}
+	return nil
+}
+
+func (c *postgresConsumer) settle(ctx context.Context, delivery postgresDelivery) error {
+	if _, err := c.bus.db.ExecContext(
+		ctx,
+		`delete from events.bus_redeliveries
+		 where consumer = $1 and topic = $2 and message_id = $3`,
+		c.consumer, c.topic, delivery.messageID,
+	); err != nil {
+		return fmt.Errorf("settle bus message: %w", err)
+	}
+	return nil
+}
+
+func (c *postgresConsumer) reschedule(
+	ctx context.Context,
+	delivery postgresDelivery,
+	attempts int,
+	delay time.Duration,
+	lastError string,
+) error {
+	if _, err := c.bus.db.ExecContext(
+		ctx,
+		`update events.bus_redeliveries
+		 set attempts = $4, available_at = $5, last_error = nullif($6, '')
+		 where consumer = $1 and topic = $2 and message_id = $3`,
+		c.consumer, c.topic, delivery.messageID, attempts, time.Now().UTC().Add(delay), lastError,
+	); err != nil {
+		return fmt.Errorf("schedule bus redelivery: %w", err)
+	}
+	return nil
+}
+
+func (c *postgresConsumer) deadLetter(ctx context.Context, delivery postgresDelivery, delivered int, handlerErr error) error {
+	dlq, err := newDLQEvent(delivery.event, c.consumer, handlerErr, delivered)
+	if err != nil {
+		return err
+	}
+	tx, err := c.bus.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin bus dead-letter tx: %w", err)
+	}
+	defer tx.Rollback()
+	if err := publishPostgres(ctx, tx, stampHeaders(ctx, dlq, c.bus.producer)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(
+		ctx,
+		`delete from events.bus_redeliveries
+		 where consumer = $1 and topic = $2 and message_id = $3`,
+		c.consumer, c.topic, delivery.messageID,
+	); err != nil {
+		return fmt.Errorf("settle dead-lettered bus message: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit bus dead-letter tx: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const postgresBusReconnectDelay = 2 * time.Second
+
+type postgresBusListener struct {
+	databaseURL string
+	done        <-chan struct{}
+	mu          sync.Mutex
+	waiters     map[string][]chan struct{}
+}
+
+func newPostgresBusListener(databaseURL string, done <-chan struct{}) *postgresBusListener {
+	return &postgresBusListener{
+		databaseURL: databaseURL,
+		done:        done,
+		waiters:     make(map[string][]chan struct{}),
+	}
+}
+
+func (l *postgresBusListener) register(topic string) chan struct{} {
+	wake := make(chan struct{}, 1)
+	l.mu.Lock()
+	l.waiters[topic] = append(l.waiters[topic], wake)
+	l.mu.Unlock()
+	return wake
+}
+
+func (l *postgresBusListener) run() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-l.done
+		cancel()
+	}()
+	for {
+		_ = l.listen(ctx)
+		select {
+		case <-l.done:
+			return
+		case <-time.After(postgresBusReconnectDelay):
+		}
+	}
+}
+
+func (l *postgresBusListener) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, l.databaseURL)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+	if _, err := conn.Exec(ctx, "listen "+postgresBusChannel); err != nil {
+		return err
+	}
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		l.notify(notification.Payload)
+	}
+}
+
+func (l *postgresBusListener) notify(topic string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, wake := range l.waiters[topic] {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
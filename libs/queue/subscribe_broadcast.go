@@ -0,0 +1,22 @@
+package queue
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const broadcastInactiveThreshold = 5 * time.Minute
+
+func WithBroadcast() SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		cfg.broadcast = true
+	}
+}
+
+func instanceConsumer(consumer string) string {
+	if consumer == "" {
+		return "ephemeral-" + uuid.NewString()
+	}
+	return consumer + "-" + uuid.NewString()
+}
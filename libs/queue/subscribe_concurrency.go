@@ -0,0 +1,111 @@
+package queue
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxInFlight = 1
+	defaultAckWait     = 30 * time.Second
+)
+
+func WithMaxInFlight(maxInFlight int) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		if maxInFlight > 0 {
+			cfg.maxInFlight = maxInFlight
+		}
+	}
+}
+
+func WithFetchBatch(batch int) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		if batch > 0 {
+			cfg.fetchBatch = batch
+		}
+	}
+}
+
+func WithAckWait(ackWait time.Duration) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		if ackWait > 0 {
+			cfg.ackWait = ackWait
+		}
+	}
+}
+
+func WithHandlerTimeout(timeout time.Duration) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		if timeout > 0 {
+			cfg.timeout = timeout
+		}
+	}
+}
+
+func WithOrderingKey(key func(Event) string) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		cfg.orderingKey = key
+	}
+}
+
+func OrderingKeyFromJSONField(field string) func(Event) string {
+	return func(event Event) string {
+		var decoded map[string]json.RawMessage
+		if err := json.Unmarshal(event.Payload, &decoded); err != nil {
+			return ""
+		}
+		var value string
+		if err := json.Unmarshal(decoded[field], &value); err != nil {
+			return string(decoded[field])
+		}
+		return value
+	}
+}
+
+func SubscribeOptionsFromEnv(getenv func(string) string) []SubscribeOption {
+	opts := make([]SubscribeOption, 0, 6)
+	if value, ok := positiveIntEnv(getenv, "BUS_MAX_IN_FLIGHT"); ok {
+		opts = append(opts, WithMaxInFlight(value))
+	}
+	if value, ok := positiveIntEnv(getenv, "BUS_FETCH_BATCH"); ok {
+		opts = append(opts, WithFetchBatch(value))
+	}
+	if value, ok := positiveIntEnv(getenv, "BUS_ACK_WAIT_MS"); ok {
+		opts = append(opts, WithAckWait(time.Duration(value)*time.Millisecond))
+	}
+	if value, ok := positiveIntEnv(getenv, "BUS_HANDLER_TIMEOUT_MS"); ok {
+		opts = append(opts, WithHandlerTimeout(time.Duration(value)*time.Millisecond))
+	}
+	if value, ok := positiveIntEnv(getenv, "BUS_MAX_DELIVER"); ok {
+		opts = append(opts, WithMaxDeliver(value))
+	}
+	if field := strings.TrimSpace(getenv("BUS_ORDERING_KEY")); field != "" {
+		opts = append(opts, WithOrderingKey(OrderingKeyFromJSONField(field)))
+	}
+	return opts
+}
+
+func positiveIntEnv(getenv func(string) string, key string) (int, bool) {
+	parsed, err := strconv.Atoi(strings.TrimSpace(getenv(key)))
+	if err != nil || parsed <= 0 {
+		return 0, false
+	}
+	return parsed, true
+}
+
+func (c subscribeConfig) heartbeatInterval() time.Duration {
+	return c.ackWait / 2
+}
+
+func HandlerTimeout(opts ...SubscribeOption) time.Duration {
+	return newSubscribeConfig(opts).handlerTimeout()
+}
+
+func (c subscribeConfig) handlerTimeout() time.Duration {
+	if c.timeout > 0 {
+		return c.timeout
+	}
+	return c.ackWait
+}
@@ -0,0 +1,106 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInMemoryBusRunsUpToMaxInFlightHandlersConcurrently(t *testing.T) {
+	bus := NewInMemoryBus()
+	defer bus.Close()
+	var current, peak atomic.Int32
+	release := make(chan struct{})
+	_ = bus.Subscribe(context.Background(), "video.run.requested.v1", "worker-gen-nim", func(context.Context, Event) error {
+		now := current.Add(1)
+		for {
+			seen := peak.Load()
+			if now <= seen || peak.CompareAndSwap(seen, now) {
+				break
+			}
+		}
+		<-release
+		current.Add(-1)
+		return nil
+	}, WithMaxInFlight(3))
+	for i := 0; i < 6; i++ {
+		_ = bus.Publish(context.Background(), Event{ID: fmt.Sprintf("evt-%d", i), Topic: "video.run.requested.v1"})
+	}
+	deadline := time.Now().Add(time.Second)
+	for peak.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	waitIdle(t, bus)
+	if peak.Load() != 3 {
+		t.Fatalf("expected 3 concurrent handlers, got %d", peak.Load())
+	}
+}
+
+func TestInMemoryBusPreservesOrderPerKey(t *testing.T) {
+	bus := NewInMemoryBus()
+	defer bus.Close()
+	var mu sync.Mutex
+	seen := map[string][]int{}
+	_ = bus.Subscribe(context.Background(), "video.run.step.completed.v1", "worker-gen-orchestrator", func(_ context.Context, event Event) error {
+		var decoded struct {
+			RunID string `json:"run_id"`
+			Seq   int    `json:"seq"`
+		}
+		_ = json.Unmarshal(event.Payload, &decoded)
+		time.Sleep(time.Duration((20-decoded.Seq)%3) * time.Millisecond)
+		mu.Lock()
+		seen[decoded.RunID] = append(seen[decoded.RunID], decoded.Seq)
+		mu.Unlock()
+		return nil
+	}, WithMaxInFlight(4), WithOrderingKey(OrderingKeyFromJSONField("run_id")))
+	for i := 0; i < 20; i++ {
+		runID := fmt.Sprintf("run-%d", i%5)
+		_ = bus.Publish(context.Background(), Event{
+			ID:      fmt.Sprintf("evt-%d", i),
+			Topic:   "video.run.step.completed.v1",
+			Payload: []byte(fmt.Sprintf(`{"run_id":%q,"seq":%d}`, runID, i)),
+		})
+	}
+	waitIdle(t, bus)
+	for runID, order := range seen {
+		for i := 1; i < len(order); i++ {
+			if order[i] < order[i-1] {
+				t.Fatalf("run %s processed out of order: %v", runID, order)
+			}
+		}
+	}
+	if len(seen) != 5 {
+		t.Fatalf("expected 5 run ids, got %d", len(seen))
+	}
+}
+
+func TestSubscribeOptionsFromEnv(t *testing.T) {
+	opts := SubscribeOptionsFromEnv(envMap(map[string]string{
+		"BUS_MAX_IN_FLIGHT":      "8",
+		"BUS_ACK_WAIT_MS":        "120000",
+		"BUS_HANDLER_TIMEOUT_MS": "90000",
+		"BUS_MAX_DELIVER":        "bogus",
+		"BUS_ORDERING_KEY":       "asset_id",
+	}))
+	cfg := newSubscribeConfig(opts)
+	if cfg.maxInFlight != 8 || cfg.fetchBatch != 8 || cfg.ackWait != 2*time.Minute {
+		t.Fatalf("unexpected concurrency config: %+v", cfg)
+	}
+	if cfg.handlerTimeout() != 90*time.Second {
+		t.Fatalf("expected handler timeout 90s, got %s", cfg.handlerTimeout())
+	}
+	if newSubscribeConfig(nil).handlerTimeout() != defaultAckWait {
+		t.Fatalf("handler timeout must default to the ack wait")
+	}
+	if cfg.maxDeliver != defaultMaxDeliver {
+		t.Fatalf("invalid max deliver must be ignored, got %d", cfg.maxDeliver)
+	}
+	if cfg.orderingKey == nil || cfg.orderingKey(Event{Payload: []byte(`{"asset_id":"a-1"}`)}) != "a-1" {
+		t.Fatalf("expected asset_id ordering key")
+	}
+}
@@ -0,0 +1,60 @@
+package queue
+
+import (
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+type deliverPolicy int
+
+const (
+	deliverNew deliverPolicy = iota
+	deliverAll
+	deliverFromSequence
+	deliverFromTime
+)
+
+type deliverPosition struct {
+	policy   deliverPolicy
+	sequence uint64
+	since    time.Time
+}
+
+func WithDeliverAll() SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		cfg.deliver = deliverPosition{policy: deliverAll}
+	}
+}
+
+func WithDeliverFromSequence(sequence uint64) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		if sequence == 0 {
+			cfg.deliver = deliverPosition{policy: deliverAll}
+			return
+		}
+		cfg.deliver = deliverPosition{policy: deliverFromSequence, sequence: sequence}
+	}
+}
+
+func WithDeliverFromTime(since time.Time) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		cfg.deliver = deliverPosition{policy: deliverFromTime, since: since.UTC()}
+	}
+}
+
+func (p deliverPosition) applyNATS(cfg *nats.ConsumerConfig) {
+	switch p.policy {
+	case deliverAll:
+		cfg.DeliverPolicy = nats.DeliverAllPolicy
+	case deliverFromSequence:
+		cfg.DeliverPolicy = nats.DeliverByStartSequencePolicy
+		cfg.OptStartSeq = p.sequence
+	case deliverFromTime:
+		since := p.since
+		cfg.DeliverPolicy = nats.DeliverByStartTimePolicy
+		cfg.OptStartTime = &since
+	default:
+		cfg.DeliverPolicy = nats.DeliverNewPolicy
+	}
+}
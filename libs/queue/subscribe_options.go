@@ -0,0 +1,74 @@
+package queue
+
+import "time"
+
+const (
+	defaultMaxDeliver      = 5
+	defaultRedeliveryDelay = 1 * time.Second
+	defaultRedeliveryMax   = 1 * time.Minute
+)
+
+type SubscribeOption func(*subscribeConfig)
+
+type subscribeConfig struct {
+	maxDeliver  int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	deliver     deliverPosition
+	maxInFlight int
+	fetchBatch  int
+	ackWait     time.Duration
+	timeout     time.Duration
+	orderingKey func(Event) string
+	broadcast   bool
+}
+
+func newSubscribeConfig(opts []SubscribeOption) subscribeConfig {
+	cfg := subscribeConfig{
+		maxDeliver:  defaultMaxDeliver,
+		backoffBase: defaultRedeliveryDelay,
+		backoffMax:  defaultRedeliveryMax,
+		maxInFlight: defaultMaxInFlight,
+		ackWait:     defaultAckWait,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.backoffMax < cfg.backoffBase {
+		cfg.backoffMax = cfg.backoffBase
+	}
+	if cfg.fetchBatch <= 0 {
+		cfg.fetchBatch = cfg.maxInFlight
+	}
+	return cfg
+}
+
+func WithMaxDeliver(maxDeliver int) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		if maxDeliver > 0 {
+			cfg.maxDeliver = maxDeliver
+		}
+	}
+}
+
+func WithRedeliveryBackoff(base, max time.Duration) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		if base > 0 {
+			cfg.backoffBase = base
+		}
+		if max > 0 {
+			cfg.backoffMax = max
+		}
+	}
+}
+
+func (c subscribeConfig) redeliveryDelay(delivered int) time.Duration {
+	delay := c.backoffBase
+	for i := 1; i < delivered; i++ {
+		delay *= 2
+		if delay >= c.backoffMax {
+			return c.backoffMax
+		}
+	}
+	return delay
+}
@@ -6,12 +6,19 @@ type Event struct {
 	ID      string
 	Topic   string
 	Payload []byte
+	Headers Headers
+}
+
+func (e Event) WithOrderingKey(key string) Event {
+	e.Headers = e.Headers.Clone()
+	e.Headers[HeaderOrderingKey] = key
+	return e
 }
 
 type Handler func(ctx context.Context, event Event) error
 
 type Bus interface {
 	Publish(ctx context.Context, event Event) error
-	Subscribe(ctx context.Context, topic string, consumer string, handler Handler) error
+	Subscribe(ctx context.Context, topic string, consumer string, handler Handler, opts ...SubscribeOption) error
 	Close() error
 }
@@ -0,0 +1,18 @@
+package testkit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+)
+
+func WaitBusIdle(t testing.TB, bus *queue.InMemoryBus) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := bus.WaitIdle(ctx); err != nil {
+		t.Fatalf("wait for in-memory bus to drain: %v", err)
+	}
+}
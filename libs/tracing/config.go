@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const defaultOTLPEndpoint = "http://localhost:4318"
+
+func InitFromEnv(service string, getenv func(string) string) (*Tracer, error) {
+	exporter, err := ExporterFromEnv(getenv)
+	if err != nil {
+		return nil, err
+	}
+	if name := getenv("OTEL_SERVICE_NAME"); name != "" {
+		service = name
+	}
+	tracer := NewTracer(service, exporter)
+	SetDefault(tracer)
+	return tracer, nil
+}
+
+func ExporterFromEnv(getenv func(string) string) (Exporter, error) {
+	if strings.EqualFold(getenv("OTEL_SDK_DISABLED"), "true") {
+		return nil, nil
+	}
+	switch kind := strings.ToLower(strings.TrimSpace(getenv("OTEL_TRACES_EXPORTER"))); kind {
+	case "", "none":
+		return nil, nil
+	case "otlp":
+		return NewOTLPExporter(otlpEndpoint(getenv), parseHeaders(getenv("OTEL_EXPORTER_OTLP_HEADERS"))), nil
+	case "file":
+		path := getenv("OTEL_TRACES_FILE")
+		if path == "" {
+			return nil, errors.New("OTEL_TRACES_FILE is required when OTEL_TRACES_EXPORTER=file")
+		}
+		exporter, err := NewFileExporter(path)
+		if err != nil {
+			return nil, err
+		}
+		return exporter, nil
+	default:
+		return nil, fmt.Errorf("unsupported OTEL_TRACES_EXPORTER %q", kind)
+	}
+}
+
+func otlpEndpoint(getenv func(string) string) string {
+	if endpoint := getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+	base := getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if base == "" {
+		base = defaultOTLPEndpoint
+	}
+	return strings.TrimRight(base, "/") + "/v1/traces"
+}
+
+func parseHeaders(raw string) map[string]string {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			continue
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return headers
+}
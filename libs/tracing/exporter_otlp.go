@@ -0,0 +1,144 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type OTLPExporter struct {
+	endpoint string
+	client   *http.Client
+	headers  map[string]string
+}
+
+func NewOTLPExporter(endpoint string, headers map[string]string) *OTLPExporter {
+	return &OTLPExporter{endpoint: endpoint, client: &http.Client{Timeout: exportTimeout}, headers: headers}
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              SpanKind       `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+func (e *OTLPExporter) Export(ctx context.Context, spans []SpanData) error {
+	body, err := json.Marshal(map[string]any{"resourceSpans": otlpResources(spans)})
+	if err != nil {
+		return fmt.Errorf("encode otlp spans: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build otlp request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post otlp spans: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("post otlp spans: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (e *OTLPExporter) Close() error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+func otlpResources(spans []SpanData) []otlpResourceSpans {
+	byService := map[string][]otlpSpan{}
+	for _, span := range spans {
+		byService[span.Service] = append(byService[span.Service], toOTLPSpan(span))
+	}
+	services := make([]string, 0, len(byService))
+	for service := range byService {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	resources := make([]otlpResourceSpans, 0, len(services))
+	for _, service := range services {
+		var resource otlpResourceSpans
+		resource.Resource.Attributes = []otlpKeyValue{{Key: "service.name", Value: otlpAnyValue{StringValue: service}}}
+		scope := otlpScopeSpans{Spans: byService[service]}
+		scope.Scope.Name = "github.com/delqhi/mikasmissions/platform/libs/tracing"
+		resource.ScopeSpans = []otlpScopeSpans{scope}
+		resources = append(resources, resource)
+	}
+	return resources
+}
+
+func toOTLPSpan(span SpanData) otlpSpan {
+	converted := otlpSpan{
+		TraceID:           span.TraceID,
+		SpanID:            span.SpanID,
+		ParentSpanID:      span.ParentSpanID,
+		Name:              span.Name,
+		Kind:              span.Kind,
+		StartTimeUnixNano: unixNano(span.Start),
+		EndTimeUnixNano:   unixNano(span.End),
+		Status:            otlpStatus{Code: 1},
+	}
+	keys := make([]string, 0, len(span.Attributes))
+	for key := range span.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		converted.Attributes = append(converted.Attributes, otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: span.Attributes[key]}})
+	}
+	if span.Error != "" {
+		converted.Status = otlpStatus{Code: 2, Message: span.Error}
+	}
+	return converted
+}
+
+func unixNano(value time.Time) string {
+	return strconv.FormatInt(value.UnixNano(), 10)
+}
@@ -0,0 +1,165 @@
+package tracing
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultBatchSize     = 256
+	defaultQueueSize     = 4096
+	defaultFlushInterval = 5 * time.Second
+	exportTimeout        = 10 * time.Second
+)
+
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+	Close() error
+}
+
+type Tracer struct {
+	service  string
+	exporter Exporter
+	now      func() time.Time
+	queue    chan SpanData
+	flush    chan chan struct{}
+	done     chan struct{}
+	wg       sync.WaitGroup
+	once     sync.Once
+	closeErr error
+	dropped  atomic.Int64
+	onError  func(error)
+}
+
+var defaultTracer atomic.Pointer[Tracer]
+
+var noopTracer = NewTracer("", nil)
+
+func NewTracer(service string, exporter Exporter) *Tracer {
+	t := &Tracer{service: service, exporter: exporter, now: time.Now, onError: func(err error) {
+		log.Printf("export spans: %v", err)
+	}}
+	if exporter == nil {
+		return t
+	}
+	t.queue = make(chan SpanData, defaultQueueSize)
+	t.flush = make(chan chan struct{})
+	t.done = make(chan struct{})
+	t.wg.Add(1)
+	go t.run()
+	return t
+}
+
+func Default() *Tracer {
+	if tracer := defaultTracer.Load(); tracer != nil {
+		return tracer
+	}
+	return noopTracer
+}
+
+func SetDefault(tracer *Tracer) {
+	defaultTracer.Store(tracer)
+}
+
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	parent, hasParent := SpanContextFromContext(ctx)
+	sc := SpanContext{TraceID: newTraceID(), SpanID: newSpanID(), Sampled: true}
+	data := SpanData{Service: t.service, Name: name, Kind: kind, Start: t.now()}
+	if hasParent {
+		sc.TraceID, sc.Sampled = parent.TraceID, parent.Sampled
+		data.ParentSpanID = parent.SpanID.String()
+	}
+	data.TraceID, data.SpanID = sc.TraceID.String(), sc.SpanID.String()
+	return ContextWithSpanContext(ctx, sc), &Span{tracer: t, sc: sc, data: data}
+}
+
+func (t *Tracer) Dropped() int64 {
+	return t.dropped.Load()
+}
+
+func (t *Tracer) export(data SpanData) {
+	if t.exporter == nil {
+		return
+	}
+	select {
+	case <-t.done:
+		t.dropped.Add(1)
+	case t.queue <- data:
+	default:
+		t.dropped.Add(1)
+	}
+}
+
+func (t *Tracer) run() {
+	defer t.wg.Done()
+	ticker := time.NewTicker(defaultFlushInterval)
+	defer ticker.Stop()
+	batch := make([]SpanData, 0, defaultBatchSize)
+	send := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		if err := t.exporter.Export(ctx, batch); err != nil {
+			t.onError(err)
+		}
+		cancel()
+		batch = batch[:0]
+	}
+	drain := func() {
+		for {
+			select {
+			case data := <-t.queue:
+				batch = append(batch, data)
+			default:
+				return
+			}
+		}
+	}
+	for {
+		select {
+		case data := <-t.queue:
+			batch = append(batch, data)
+			if len(batch) >= defaultBatchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case ack := <-t.flush:
+			drain()
+			send()
+			close(ack)
+		case <-t.done:
+			drain()
+			send()
+			return
+		}
+	}
+}
+
+func (t *Tracer) Flush() {
+	if t.exporter == nil {
+		return
+	}
+	ack := make(chan struct{})
+	select {
+	case t.flush <- ack:
+		<-ack
+	case <-t.done:
+	}
+}
+
+func (t *Tracer) Close() error {
+	if t.exporter == nil {
+		return nil
+	}
+	t.once.Do(func() {
+		close(t.done)
+		t.wg.Wait()
+		t.closeErr = t.exporter.Close()
+	})
+	return t.closeErr
+}
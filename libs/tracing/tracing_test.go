@@ -0,0 +1,132 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	raw := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, ok := ParseTraceparent(raw)
+	if !ok || !sc.Sampled || sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("unexpected span context %+v ok=%v", sc, ok)
+	}
+	if sc.Traceparent() != raw {
+		t.Fatalf("expected %q, got %q", raw, sc.Traceparent())
+	}
+	for _, invalid := range []string{
+		"",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		if _, ok := ParseTraceparent(invalid); ok {
+			t.Fatalf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestStartContinuesExtractedTrace(t *testing.T) {
+	tracer := NewTracer("playback-service", nil)
+	headers := http.Header{}
+	headers.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := Extract(context.Background(), headers.Get)
+	ctx, span := tracer.Start(ctx, "POST /v1/playback/sessions", SpanKindServer)
+	if span.data.ParentSpanID != "00f067aa0ba902b7" || span.SpanContext().TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("expected child of remote parent, got %+v", span.data)
+	}
+	outgoing := http.Header{}
+	Inject(ctx, outgoing.Set)
+	if outgoing.Get(TraceparentHeader) != span.SpanContext().Traceparent() {
+		t.Fatalf("expected injected child traceparent, got %q", outgoing.Get(TraceparentHeader))
+	}
+}
+
+func TestFileExporterWritesEndedSpans(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.ndjson")
+	exporter, err := ExporterFromEnv(func(key string) string {
+		return map[string]string{"OTEL_TRACES_EXPORTER": "file", "OTEL_TRACES_FILE": path}[key]
+	})
+	if err != nil {
+		t.Fatalf("exporter: %v", err)
+	}
+	tracer := NewTracer("worker-test", exporter)
+	ctx, parent := tracer.Start(context.Background(), "process media.ingested.v1", SpanKindConsumer)
+	_, child := tracer.Start(ctx, "publish media.transcode.requested.v1", SpanKindProducer)
+	child.RecordError(errors.New("bus down"))
+	child.End()
+	parent.SetAttribute("messaging.destination.name", "media.ingested.v1")
+	parent.End()
+	parent.End()
+	if err := tracer.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open spans: %v", err)
+	}
+	defer file.Close()
+	var spans []SpanData
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var span SpanData
+		if err := json.Unmarshal(scanner.Bytes(), &span); err != nil {
+			t.Fatalf("decode span: %v", err)
+		}
+		spans = append(spans, span)
+	}
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Error != "bus down" || spans[0].ParentSpanID != spans[1].SpanID || spans[0].TraceID != spans[1].TraceID {
+		t.Fatalf("unexpected span linkage %+v", spans)
+	}
+	if spans[1].Attributes["messaging.destination.name"] != "media.ingested.v1" || spans[1].Service != "worker-test" {
+		t.Fatalf("unexpected parent span %+v", spans[1])
+	}
+}
+
+func TestOTLPExporterPostsJSONTraces(t *testing.T) {
+	var body string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Authorization") != "Bearer local" {
+			t.Errorf("unexpected request %s %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		raw, _ := io.ReadAll(r.Body)
+		body = string(raw)
+	}))
+	defer collector.Close()
+	exporter, err := ExporterFromEnv(func(key string) string {
+		return map[string]string{
+			"OTEL_TRACES_EXPORTER":        "otlp",
+			"OTEL_EXPORTER_OTLP_ENDPOINT": collector.URL + "/",
+			"OTEL_EXPORTER_OTLP_HEADERS":  "Authorization=Bearer local",
+		}[key]
+	})
+	if err != nil {
+		t.Fatalf("exporter: %v", err)
+	}
+	tracer := NewTracer("api-gateway-service", exporter)
+	_, span := tracer.Start(context.Background(), "GET /v1/kids/home", SpanKindServer)
+	span.End()
+	tracer.Flush()
+	for _, want := range []string{`"service.name"`, `"stringValue":"api-gateway-service"`, `"name":"GET /v1/kids/home"`, `"kind":2`, `"traceId":"` + span.SpanContext().TraceID.String()} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("missing %s in %s", want, body)
+		}
+	}
+	if err := tracer.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
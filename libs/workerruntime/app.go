@@ -0,0 +1,164 @@
+package workerruntime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/tracing"
+)
+
+type Subscriber interface {
+	Topic() string
+	Consumer() string
+	Handle(ctx context.Context, event queue.Event) error
+}
+
+type App struct {
+	Name      string
+	Logger    *slog.Logger
+	Bus       queue.Bus
+	Telemetry *Telemetry
+	Health    HealthConfig
+
+	ctx           context.Context
+	cancel        context.CancelFunc
+	subscriptions []subscription
+	checks        []readinessCheck
+	closers       []io.Closer
+	errs          chan error
+}
+
+func Main(name string, setup func(app *App) error) {
+	if err := Run(name, setup); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func Run(name string, setup func(app *App) error) error {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	bus, err := queue.NewBusFromEnv(queue.WithProducer(name))
+	if err != nil {
+		return fmt.Errorf("connect bus: %w", err)
+	}
+	tracer, err := tracing.InitFromEnv(name, os.Getenv)
+	if err != nil {
+		_ = bus.Close()
+		return fmt.Errorf("init tracing: %w", err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	app := NewApp(ctx, name, logger, bus)
+	app.Health = HealthConfigFromEnv(os.Getenv)
+	app.OnClose(tracer)
+	return app.Run(setup)
+}
+
+func NewApp(ctx context.Context, name string, logger *slog.Logger, bus queue.Bus) *App {
+	ctx, cancel := context.WithCancel(ctx)
+	app := &App{Name: name, Logger: logger, Bus: bus, Telemetry: NewTelemetry(), ctx: ctx, cancel: cancel, errs: make(chan error, 1)}
+	app.Telemetry.Registry().OnCollect(func() {
+		collectCtx, cancel := context.WithTimeout(app.ctx, app.checkTimeout())
+		defer cancel()
+		app.refreshLag(collectCtx)
+	})
+	return app
+}
+
+func (a *App) Context() context.Context {
+	return a.ctx
+}
+
+func (a *App) Options() []Option {
+	return []Option{WithBus(a.Bus), WithLogger(a.Logger), WithObserver(a.Telemetry)}
+}
+
+func (a *App) Subscribe(subscriber Subscriber, opts ...queue.SubscribeOption) error {
+	if closer, ok := subscriber.(io.Closer); ok {
+		a.OnClose(closer)
+	}
+	if checker, ok := subscriber.(queue.HealthChecker); ok {
+		a.AddReadinessCheck("subscriber:"+subscriber.Consumer(), checker.Ping)
+	}
+	return a.subscribe(subscriber.Topic(), subscriber.Consumer(), subscriber.Handle, opts)
+}
+
+func (a *App) SubscribeFunc(topic, consumer string, handler queue.Handler, opts ...queue.SubscribeOption) error {
+	return a.subscribe(topic, consumer, func(ctx context.Context, event queue.Event) error {
+		finish := a.Telemetry.StartEvent(consumer, event.Topic)
+		err := handler(ctx, event)
+		if err != nil {
+			finish(OutcomeFailed)
+			return err
+		}
+		finish(OutcomeProcessed)
+		return nil
+	}, opts)
+}
+
+func (a *App) subscribe(topic, consumer string, handler queue.Handler, defaults []queue.SubscribeOption) error {
+	opts := slices.Concat(defaults, queue.SubscribeOptionsFromEnv(os.Getenv))
+	if err := a.Bus.Subscribe(a.ctx, topic, consumer, handler, opts...); err != nil {
+		return fmt.Errorf("subscribe %s to %s: %w", consumer, topic, err)
+	}
+	a.Health.coverHandlerTimeout(queue.HandlerTimeout(opts...))
+	a.subscriptions = append(a.subscriptions, subscription{topic: topic, consumer: consumer})
+	return nil
+}
+
+func (a *App) Go(run func(ctx context.Context) error) {
+	go func() {
+		if err := run(a.ctx); err != nil && !errors.Is(err, context.Canceled) {
+			select {
+			case a.errs <- err:
+			default:
+			}
+		}
+	}()
+}
+
+func (a *App) OnClose(closer io.Closer) {
+	a.closers = append(a.closers, closer)
+}
+
+func (a *App) Run(setup func(app *App) error) error {
+	defer a.shutdown()
+	if err := setup(a); err != nil {
+		return fmt.Errorf("set up %s: %w", a.Name, err)
+	}
+	if err := a.serveHealth(); err != nil {
+		return fmt.Errorf("start %s health server: %w", a.Name, err)
+	}
+	topics := make([]string, 0, len(a.subscriptions))
+	for _, sub := range a.subscriptions {
+		topics = append(topics, sub.topic)
+	}
+	a.Logger.Info("worker started", "worker", a.Name, "topics", topics, "health_addr", a.Health.Addr)
+	select {
+	case <-a.ctx.Done():
+		return nil
+	case err := <-a.errs:
+		return fmt.Errorf("run %s: %w", a.Name, err)
+	}
+}
+
+func (a *App) shutdown() {
+	a.cancel()
+	if err := a.Bus.Close(); err != nil {
+		a.Logger.Warn("close bus failed", "worker", a.Name, "error", err.Error())
+	}
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		if err := a.closers[i].Close(); err != nil {
+			a.Logger.Warn("close resource failed", "worker", a.Name, "error", err.Error())
+		}
+	}
+	a.Logger.Info("worker stopped", "worker", a.Name)
+}
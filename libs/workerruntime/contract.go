@@ -0,0 +1,48 @@
+package workerruntime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type Contract interface {
+	Validate() error
+}
+
+type Output struct {
+	Topic   string
+	ID      string
+	payload Contract
+}
+
+func Emit[Out Contract](topic string, payload Out) Output {
+	return Output{Topic: topic, payload: payload}
+}
+
+func (o Output) WithID(id string) Output {
+	o.ID = id
+	return o
+}
+
+func (o Output) encode() ([]byte, error) {
+	if err := o.payload.Validate(); err != nil {
+		return nil, fmt.Errorf("validate %s: %w", o.Topic, err)
+	}
+	payload, err := json.Marshal(o.payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", o.Topic, err)
+	}
+	return payload, nil
+}
+
+type Raw struct {
+	json.RawMessage
+}
+
+func (r Raw) Validate() error {
+	if !json.Valid(r.RawMessage) {
+		return errors.New("payload is not valid json")
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package workerruntime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/libs/httpx"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+)
+
+const (
+	defaultHealthAddr     = ":9090"
+	defaultStallTimeout   = 15 * time.Minute
+	stallGrace            = 5 * time.Minute
+	defaultMaxConsumerLag = 10000
+	defaultCheckTimeout   = 2 * time.Second
+)
+
+type HealthConfig struct {
+	Addr           string
+	StallTimeout   time.Duration
+	MaxConsumerLag int64
+	CheckTimeout   time.Duration
+	stallPinned    bool
+}
+
+type readinessCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+type subscription struct {
+	topic    string
+	consumer string
+}
+
+func HealthConfigFromEnv(getenv func(string) string) HealthConfig {
+	cfg := HealthConfig{
+		Addr:           defaultHealthAddr,
+		StallTimeout:   defaultStallTimeout,
+		MaxConsumerLag: defaultMaxConsumerLag,
+		CheckTimeout:   defaultCheckTimeout,
+	}
+	switch addr := getenv("WORKER_HTTP_ADDR"); addr {
+	case "":
+	case "off":
+		cfg.Addr = ""
+	default:
+		cfg.Addr = addr
+	}
+	if ms, ok := nonNegativeIntEnv(getenv, "BUS_HANDLER_TIMEOUT_MS"); ok {
+		cfg.StallTimeout = max(cfg.StallTimeout, time.Duration(ms)*time.Millisecond+stallGrace)
+	}
+	if ms, ok := nonNegativeIntEnv(getenv, "WORKER_STALL_TIMEOUT_MS"); ok {
+		cfg.StallTimeout = time.Duration(ms) * time.Millisecond
+		cfg.stallPinned = true
+	}
+	if lag, ok := nonNegativeIntEnv(getenv, "WORKER_MAX_CONSUMER_LAG"); ok {
+		cfg.MaxConsumerLag = lag
+	}
+	return cfg
+}
+
+func (c *HealthConfig) coverHandlerTimeout(timeout time.Duration) {
+	if c.stallPinned || c.StallTimeout <= 0 {
+		return
+	}
+	c.StallTimeout = max(c.StallTimeout, timeout+stallGrace)
+}
+
+func nonNegativeIntEnv(getenv func(string) string, key string) (int64, bool) {
+	parsed, err := strconv.ParseInt(getenv(key), 10, 64)
+	if err != nil || parsed < 0 {
+		return 0, false
+	}
+	return parsed, true
+}
+
+func (a *App) AddReadinessCheck(name string, check func(ctx context.Context) error) {
+	a.checks = append(a.checks, readinessCheck{name: name, check: check})
+}
+
+func (a *App) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", a.serveLiveness)
+	mux.HandleFunc("GET /readyz", a.serveReadiness)
+	mux.Handle("GET /metrics", a.Telemetry.Registry().Handler())
+	return mux
+}
+
+func (a *App) serveLiveness(w http.ResponseWriter, _ *http.Request) {
+	oldest := a.Telemetry.OldestInFlight()
+	if a.Health.StallTimeout > 0 && oldest > a.Health.StallTimeout {
+		httpx.WriteJSON(w, http.StatusServiceUnavailable, map[string]any{
+			"status":              "stalled",
+			"oldest_in_flight_ms": oldest.Milliseconds(),
+		})
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (a *App) serveReadiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), a.checkTimeout())
+	defer cancel()
+	results := map[string]string{}
+	ready := true
+	record := func(name string, err error) {
+		results[name] = "ok"
+		if err != nil {
+			results[name] = err.Error()
+			ready = false
+		}
+	}
+	if checker, ok := a.Bus.(queue.HealthChecker); ok {
+		record("bus", checker.Ping(ctx))
+	}
+	for _, check := range a.checks {
+		record(check.name, check.check(ctx))
+	}
+	for name, err := range a.refreshLag(ctx) {
+		record(name, err)
+	}
+	status, code := "ready", http.StatusOK
+	if !ready {
+		status, code = "unready", http.StatusServiceUnavailable
+	}
+	httpx.WriteJSON(w, code, map[string]any{"status": status, "checks": results})
+}
+
+func (a *App) refreshLag(ctx context.Context) map[string]error {
+	reporter, ok := a.Bus.(queue.LagReporter)
+	if !ok {
+		return nil
+	}
+	results := make(map[string]error, len(a.subscriptions))
+	for _, sub := range a.subscriptions {
+		name := "lag:" + sub.consumer + "/" + sub.topic
+		lag, err := reporter.ConsumerLag(ctx, sub.topic, sub.consumer)
+		if err != nil {
+			results[name] = err
+			continue
+		}
+		a.Telemetry.SetConsumerLag(sub.consumer, sub.topic, lag)
+		if a.Health.MaxConsumerLag > 0 && lag > a.Health.MaxConsumerLag {
+			results[name] = fmt.Errorf("consumer lag %d exceeds %d", lag, a.Health.MaxConsumerLag)
+			continue
+		}
+		results[name] = nil
+	}
+	return results
+}
+
+func (a *App) checkTimeout() time.Duration {
+	if a.Health.CheckTimeout > 0 {
+		return a.Health.CheckTimeout
+	}
+	return defaultCheckTimeout
+}
+
+func (a *App) serveHealth() error {
+	if a.Health.Addr == "" {
+		return nil
+	}
+	listener, err := net.Listen("tcp", a.Health.Addr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", a.Health.Addr, err)
+	}
+	server := &http.Server{Handler: a.Handler(), ReadHeaderTimeout: 5 * time.Second}
+	a.Go(func(ctx context.Context) error {
+		go func() {
+			<-ctx.Done()
+			_ = server.Close()
+		}()
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("serve health: %w", err)
+		}
+		return nil
+	})
+	return nil
+}
@@ -0,0 +1,192 @@
+package workerruntime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+)
+
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+	bus := queue.NewInMemoryBus()
+	app := NewApp(context.Background(), "worker-test", slog.New(slog.NewJSONHandler(io.Discard, nil)), bus)
+	t.Cleanup(app.shutdown)
+	return app
+}
+
+func serve(app *App, path string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	app.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+	return rr
+}
+
+func TestAppMetricsCountOutcomesAndLatency(t *testing.T) {
+	app := newTestApp(t)
+	processor := NewProcessor("worker-test", "greeting.requested.v1", func(context.Context, greeting) ([]Output, error) {
+		return nil, nil
+	}, append(app.Options(), WithGuard(queue.NewIdempotencyGuard()))...)
+	if err := app.Subscribe(processor); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	ctx := context.Background()
+	event := queue.Event{ID: "evt-1", Topic: "greeting.requested.v1", Payload: []byte(`{"name":"mika"}`)}
+	_ = processor.Handle(ctx, event)
+	_ = processor.Handle(ctx, event)
+	_ = processor.Handle(ctx, queue.Event{ID: "evt-2", Topic: "greeting.requested.v1", Payload: []byte(`{}`)})
+
+	body := serve(app, "/metrics").Body.String()
+	for _, want := range []string{
+		`worker_events_total{consumer="worker-test",topic="greeting.requested.v1",outcome="processed"} 1`,
+		`worker_events_total{consumer="worker-test",topic="greeting.requested.v1",outcome="duplicate"} 1`,
+		`worker_events_total{consumer="worker-test",topic="greeting.requested.v1",outcome="failed"} 1`,
+		`worker_handler_duration_seconds_count{consumer="worker-test",topic="greeting.requested.v1"} 3`,
+		`worker_events_in_flight{consumer="worker-test",topic="greeting.requested.v1"} 0`,
+		`worker_consumer_lag{consumer="worker-test",topic="greeting.requested.v1"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("missing %q in:\n%s", want, body)
+		}
+	}
+}
+
+func TestAppReadinessReportsFailingChecks(t *testing.T) {
+	app := newTestApp(t)
+	if rr := serve(app, "/readyz"); rr.Code != http.StatusOK {
+		t.Fatalf("expected ready, got %d: %s", rr.Code, rr.Body.String())
+	}
+	app.AddReadinessCheck("database", func(context.Context) error { return errors.New("connection refused") })
+	rr := serve(app, "/readyz")
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected unready, got %d", rr.Code)
+	}
+	var body struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode readiness: %v", err)
+	}
+	if body.Status != "unready" || body.Checks["database"] != "connection refused" || body.Checks["bus"] != "ok" {
+		t.Fatalf("unexpected readiness body %+v", body)
+	}
+}
+
+func TestAppReadinessFailsWhenLagExceedsThreshold(t *testing.T) {
+	app := newTestApp(t)
+	app.Health.MaxConsumerLag = 1
+	release := make(chan struct{})
+	defer close(release)
+	err := app.SubscribeFunc("greeting.requested.v1", "worker-test", func(ctx context.Context, _ queue.Event) error {
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	for _, id := range []string{"evt-1", "evt-2", "evt-3", "evt-4"} {
+		if err := app.Bus.Publish(context.Background(), queue.Event{ID: id, Topic: "greeting.requested.v1", Payload: []byte(`{}`)}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+	rr := serve(app, "/readyz")
+	if rr.Code != http.StatusServiceUnavailable || !strings.Contains(rr.Body.String(), "exceeds 1") {
+		t.Fatalf("expected lag failure, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestAppLivenessFailsOnStalledHandler(t *testing.T) {
+	app := newTestApp(t)
+	app.Health.StallTimeout = time.Minute
+	now := time.Now()
+	app.Telemetry.now = func() time.Time { return now }
+	finish := app.Telemetry.StartEvent("worker-test", "greeting.requested.v1")
+	if rr := serve(app, "/healthz"); rr.Code != http.StatusOK {
+		t.Fatalf("expected live, got %d", rr.Code)
+	}
+	now = now.Add(2 * time.Minute)
+	if rr := serve(app, "/healthz"); rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected stalled, got %d", rr.Code)
+	}
+	finish(OutcomeProcessed)
+	if rr := serve(app, "/healthz"); rr.Code != http.StatusOK {
+		t.Fatalf("expected live after handler finished, got %d", rr.Code)
+	}
+}
+
+func TestHealthConfigFromEnv(t *testing.T) {
+	env := map[string]string{"WORKER_HTTP_ADDR": "off", "WORKER_STALL_TIMEOUT_MS": "30000", "WORKER_MAX_CONSUMER_LAG": "bad"}
+	cfg := HealthConfigFromEnv(func(key string) string { return env[key] })
+	if cfg.Addr != "" || cfg.StallTimeout != 30*time.Second || cfg.MaxConsumerLag != defaultMaxConsumerLag {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+}
+
+func TestHealthConfigDerivesStallTimeoutFromHandlerTimeout(t *testing.T) {
+	env := map[string]string{"BUS_HANDLER_TIMEOUT_MS": "1800000"}
+	if cfg := HealthConfigFromEnv(func(key string) string { return env[key] }); cfg.StallTimeout != 35*time.Minute {
+		t.Fatalf("expected stall timeout above the handler timeout, got %s", cfg.StallTimeout)
+	}
+	env["BUS_HANDLER_TIMEOUT_MS"] = "60000"
+	if cfg := HealthConfigFromEnv(func(key string) string { return env[key] }); cfg.StallTimeout != defaultStallTimeout {
+		t.Fatalf("expected default stall timeout, got %s", cfg.StallTimeout)
+	}
+}
+
+func TestAppSubscribeRaisesStallTimeoutAboveHandlerTimeout(t *testing.T) {
+	app := newTestApp(t)
+	app.Health = HealthConfigFromEnv(func(string) string { return "" })
+	handler := func(context.Context, queue.Event) error { return nil }
+	if err := app.SubscribeFunc("greeting.requested.v1", "worker-test", handler, queue.WithHandlerTimeout(30*time.Minute)); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if app.Health.StallTimeout != 35*time.Minute {
+		t.Fatalf("expected stall timeout above the handler timeout, got %s", app.Health.StallTimeout)
+	}
+	app.Health = HealthConfigFromEnv(func(key string) string { return map[string]string{"WORKER_STALL_TIMEOUT_MS": "60000"}[key] })
+	if err := app.SubscribeFunc("greeting.requested.v1", "worker-test", handler, queue.WithHandlerTimeout(30*time.Minute)); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if app.Health.StallTimeout != time.Minute {
+		t.Fatalf("expected explicit stall timeout to win, got %s", app.Health.StallTimeout)
+	}
+}
+
+func TestAppSubscribeAppliesSubscribeOptions(t *testing.T) {
+	app := newTestApp(t)
+	attempts := 0
+	err := app.SubscribeFunc("greeting.requested.v1", "worker-test", func(context.Context, queue.Event) error {
+		attempts++
+		return errors.New("poison")
+	}, queue.WithMaxDeliver(1))
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	dead := 0
+	_ = app.Bus.Subscribe(context.Background(), "greeting.requested.v1.dlq.v1", "test-dlq", func(context.Context, queue.Event) error {
+		dead++
+		return nil
+	})
+	if err := app.Bus.Publish(context.Background(), queue.Event{ID: "evt-1", Topic: "greeting.requested.v1", Payload: []byte(`{}`)}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := app.Bus.(*queue.InMemoryBus).WaitIdle(ctx); err != nil {
+		t.Fatalf("wait idle: %v", err)
+	}
+	if attempts != 1 || dead != 1 {
+		t.Fatalf("expected one delivery then dead-letter, attempts=%d dead=%d", attempts, dead)
+	}
+}
@@ -0,0 +1,21 @@
+package workerruntime
+
+type Outcome string
+
+const (
+	OutcomeProcessed Outcome = "processed"
+	OutcomeFailed    Outcome = "failed"
+	OutcomeDuplicate Outcome = "duplicate"
+	OutcomeSkipped   Outcome = "skipped"
+	OutcomeDeferred  Outcome = "deferred"
+)
+
+type Observer interface {
+	StartEvent(consumer, topic string) func(outcome Outcome)
+}
+
+type nopObserver struct{}
+
+func (nopObserver) StartEvent(string, string) func(Outcome) {
+	return func(Outcome) {}
+}
@@ -0,0 +1,168 @@
+package workerruntime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/google/uuid"
+)
+
+type HandleFunc[In Contract] func(ctx context.Context, in In) ([]Output, error)
+
+type Option func(*config)
+
+type config struct {
+	bus      queue.Bus
+	guard    *queue.IdempotencyGuard
+	logger   *slog.Logger
+	observer Observer
+	logAttrs func(any) []any
+}
+
+func WithBus(bus queue.Bus) Option {
+	return func(cfg *config) {
+		cfg.bus = bus
+	}
+}
+
+func WithGuard(guard *queue.IdempotencyGuard) Option {
+	return func(cfg *config) {
+		cfg.guard = guard
+	}
+}
+
+func WithLocalGuard() Option {
+	return func(cfg *config) {
+		cfg.guard = queue.NewIdempotencyGuard()
+	}
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(cfg *config) {
+		cfg.logger = logger
+	}
+}
+
+func WithObserver(observer Observer) Option {
+	return func(cfg *config) {
+		cfg.observer = observer
+	}
+}
+
+func WithLogAttrs[In Contract](attrs func(In) []any) Option {
+	return func(cfg *config) {
+		cfg.logAttrs = func(in any) []any {
+			typed, ok := in.(In)
+			if !ok {
+				return nil
+			}
+			return attrs(typed)
+		}
+	}
+}
+
+type Processor[In Contract] struct {
+	consumer string
+	topic    string
+	handle   HandleFunc[In]
+	cfg      config
+}
+
+func NewProcessor[In Contract](consumer, topic string, handle HandleFunc[In], opts ...Option) *Processor[In] {
+	cfg := config{logger: slog.Default(), observer: nopObserver{}}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.guard == nil {
+		cfg.guard = queue.NewScopedIdempotencyGuard(consumer)
+	}
+	return &Processor[In]{consumer: consumer, topic: topic, handle: handle, cfg: cfg}
+}
+
+func (p *Processor[In]) Topic() string {
+	return p.topic
+}
+
+func (p *Processor[In]) Consumer() string {
+	return p.consumer
+}
+
+func (p *Processor[In]) Handle(ctx context.Context, event queue.Event) error {
+	finish := p.cfg.observer.StartEvent(p.consumer, event.Topic)
+	outcome := OutcomeProcessed
+	duplicate, err := p.cfg.guard.Do(ctx, event.ID, func(ctx context.Context) error {
+		if event.Topic != p.topic {
+			outcome = OutcomeSkipped
+			p.cfg.logger.Info("unexpected topic skipped", "worker", p.consumer, "topic", event.Topic)
+			return nil
+		}
+		return p.process(ctx, event)
+	})
+	switch {
+	case errors.Is(err, queue.ErrIdempotencyInProgress):
+		outcome = OutcomeDeferred
+		p.cfg.logger.Info("event in progress elsewhere, deferred", "worker", p.consumer, "event_id", event.ID)
+	case err != nil:
+		outcome = OutcomeFailed
+	case duplicate:
+		outcome = OutcomeDuplicate
+		p.cfg.logger.Info("duplicate event ignored", "worker", p.consumer, "event_id", event.ID)
+	}
+	finish(outcome)
+	return err
+}
+
+func (p *Processor[In]) process(ctx context.Context, event queue.Event) error {
+	var in In
+	if err := json.Unmarshal(event.Payload, &in); err != nil {
+		return fmt.Errorf("decode %s: %w", event.Topic, err)
+	}
+	if err := in.Validate(); err != nil {
+		return fmt.Errorf("validate %s: %w", event.Topic, err)
+	}
+	outputs, err := p.handle(ctx, in)
+	if err != nil {
+		return err
+	}
+	for _, output := range outputs {
+		if err := p.publish(ctx, output); err != nil {
+			return err
+		}
+	}
+	attrs := []any{"worker", p.consumer, "event_id", event.ID, "outputs", len(outputs)}
+	if p.cfg.logAttrs != nil {
+		attrs = append(attrs, p.cfg.logAttrs(in)...)
+	}
+	p.cfg.logger.Info("event processed", attrs...)
+	return nil
+}
+
+func (p *Processor[In]) publish(ctx context.Context, output Output) error {
+	if p.cfg.bus == nil {
+		return fmt.Errorf("publish %s: worker %s has no bus", output.Topic, p.consumer)
+	}
+	payload, err := output.encode()
+	if err != nil {
+		return err
+	}
+	id := output.ID
+	if id == "" {
+		id = uuid.NewString()
+	}
+	if err := p.cfg.bus.Publish(ctx, queue.Event{ID: id, Topic: output.Topic, Payload: payload}); err != nil {
+		return fmt.Errorf("publish %s: %w", output.Topic, err)
+	}
+	return nil
+}
+
+func (p *Processor[In]) Ping(ctx context.Context) error {
+	return p.cfg.guard.Ping(ctx)
+}
+
+func (p *Processor[In]) Close() error {
+	return p.cfg.guard.Close()
+}
@@ -0,0 +1,145 @@
+package workerruntime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
+)
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+func (g greeting) Validate() error {
+	if g.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+type recordingObserver struct {
+	outcomes []Outcome
+}
+
+func (r *recordingObserver) StartEvent(_, _ string) func(Outcome) {
+	return func(outcome Outcome) {
+		r.outcomes = append(r.outcomes, outcome)
+	}
+}
+
+func newTestProcessor(bus queue.Bus, observer Observer, handle HandleFunc[greeting]) *Processor[greeting] {
+	return NewProcessor("worker-test", "greeting.requested.v1", handle,
+		WithBus(bus),
+		WithGuard(queue.NewIdempotencyGuard()),
+		WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))),
+		WithObserver(observer),
+	)
+}
+
+func TestProcessorDecodesHandlesAndPublishesOutputs(t *testing.T) {
+	bus := queue.NewInMemoryBus()
+	defer bus.Close()
+	var published []string
+	_ = bus.Subscribe(context.Background(), "greeting.sent.v1", "test", func(_ context.Context, event queue.Event) error {
+		published = append(published, string(event.Payload))
+		return nil
+	})
+	observer := &recordingObserver{}
+	processor := newTestProcessor(bus, observer, func(_ context.Context, in greeting) ([]Output, error) {
+		return []Output{Emit("greeting.sent.v1", greeting{Name: "hello " + in.Name})}, nil
+	})
+	event := queue.Event{ID: "evt-1", Topic: "greeting.requested.v1", Payload: []byte(`{"name":"mika"}`)}
+	if err := processor.Handle(context.Background(), event); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if err := processor.Handle(context.Background(), event); err != nil {
+		t.Fatalf("duplicate handle: %v", err)
+	}
+	_ = processor.Handle(context.Background(), queue.Event{ID: "evt-2", Topic: "other.v1", Payload: []byte(`{}`)})
+	testkit.WaitBusIdle(t, bus)
+	if len(published) != 1 || published[0] != `{"name":"hello mika"}` {
+		t.Fatalf("unexpected published payloads: %v", published)
+	}
+	want := []Outcome{OutcomeProcessed, OutcomeDuplicate, OutcomeSkipped}
+	if len(observer.outcomes) != len(want) {
+		t.Fatalf("expected outcomes %v, got %v", want, observer.outcomes)
+	}
+	for i := range want {
+		if observer.outcomes[i] != want[i] {
+			t.Fatalf("expected outcomes %v, got %v", want, observer.outcomes)
+		}
+	}
+}
+
+func TestProcessorRejectsInvalidInputAndAllowsRetry(t *testing.T) {
+	observer := &recordingObserver{}
+	calls := 0
+	processor := newTestProcessor(queue.NewInMemoryBus(), observer, func(context.Context, greeting) ([]Output, error) {
+		calls++
+		return nil, nil
+	})
+	if err := processor.Handle(context.Background(), queue.Event{ID: "evt-1", Topic: "greeting.requested.v1", Payload: []byte(`{}`)}); err == nil {
+		t.Fatalf("expected validation error")
+	}
+	if err := processor.Handle(context.Background(), queue.Event{ID: "evt-1", Topic: "greeting.requested.v1", Payload: []byte(`{"name":"mika"}`)}); err != nil {
+		t.Fatalf("retry after failure: %v", err)
+	}
+	if calls != 1 || observer.outcomes[0] != OutcomeFailed || observer.outcomes[1] != OutcomeProcessed {
+		t.Fatalf("unexpected calls=%d outcomes=%v", calls, observer.outcomes)
+	}
+}
+
+func TestProcessorRefusesInvalidOutput(t *testing.T) {
+	processor := newTestProcessor(queue.NewInMemoryBus(), &recordingObserver{}, func(context.Context, greeting) ([]Output, error) {
+		return []Output{Emit("greeting.sent.v1", greeting{})}, nil
+	})
+	err := processor.Handle(context.Background(), queue.Event{ID: "evt-1", Topic: "greeting.requested.v1", Payload: []byte(`{"name":"mika"}`)})
+	if err == nil {
+		t.Fatalf("expected output validation error")
+	}
+}
+
+func TestProcessorLogsInputAttrs(t *testing.T) {
+	var logs bytes.Buffer
+	processor := NewProcessor("worker-test", "greeting.requested.v1", func(context.Context, greeting) ([]Output, error) {
+		return nil, nil
+	},
+		WithGuard(queue.NewIdempotencyGuard()),
+		WithLogger(slog.New(slog.NewJSONHandler(&logs, nil))),
+		WithLogAttrs(func(in greeting) []any { return []any{"name", in.Name} }),
+	)
+	if err := processor.Handle(context.Background(), queue.Event{ID: "evt-1", Topic: "greeting.requested.v1", Payload: []byte(`{"name":"mika"}`)}); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if !strings.Contains(logs.String(), `"msg":"event processed"`) || !strings.Contains(logs.String(), `"name":"mika"`) {
+		t.Fatalf("expected input attrs on processed log, got %s", logs.String())
+	}
+}
+
+func TestAppRunStopsOnBackgroundError(t *testing.T) {
+	bus := queue.NewInMemoryBus()
+	app := NewApp(context.Background(), "worker-test", slog.New(slog.NewJSONHandler(io.Discard, nil)), bus)
+	boom := errors.New("relay crashed")
+	err := app.Run(func(app *App) error {
+		if err := app.Subscribe(newTestProcessor(app.Bus, &recordingObserver{}, func(context.Context, greeting) ([]Output, error) {
+			return nil, nil
+		})); err != nil {
+			return err
+		}
+		app.Go(func(context.Context) error { return boom })
+		return nil
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected background error, got %v", err)
+	}
+	if app.Context().Err() == nil {
+		t.Fatalf("expected app context cancelled after shutdown")
+	}
+}
@@ -0,0 +1,71 @@
+package workerruntime
+
+import (
+	"sync"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/libs/metrics"
+)
+
+type Telemetry struct {
+	registry *metrics.Registry
+	events   *metrics.CounterVec
+	duration *metrics.HistogramVec
+	inFlight *metrics.GaugeVec
+	lag      *metrics.GaugeVec
+	now      func() time.Time
+
+	mu      sync.Mutex
+	nextID  uint64
+	running map[uint64]time.Time
+}
+
+func NewTelemetry() *Telemetry {
+	registry := metrics.NewRegistry()
+	return &Telemetry{
+		registry: registry,
+		events:   registry.Counter("worker_events_total", "Events handled by outcome.", "consumer", "topic", "outcome"),
+		duration: registry.Histogram("worker_handler_duration_seconds", "Event handler latency in seconds.", metrics.DefaultBuckets, "consumer", "topic"),
+		inFlight: registry.Gauge("worker_events_in_flight", "Events currently being handled.", "consumer", "topic"),
+		lag:      registry.Gauge("worker_consumer_lag", "Messages pending for the consumer.", "consumer", "topic"),
+		now:      time.Now,
+		running:  make(map[uint64]time.Time),
+	}
+}
+
+func (t *Telemetry) Registry() *metrics.Registry {
+	return t.registry
+}
+
+func (t *Telemetry) StartEvent(consumer, topic string) func(Outcome) {
+	started := t.now()
+	t.mu.Lock()
+	t.nextID++
+	id := t.nextID
+	t.running[id] = started
+	t.mu.Unlock()
+	t.inFlight.Add(1, consumer, topic)
+	return func(outcome Outcome) {
+		t.mu.Lock()
+		delete(t.running, id)
+		t.mu.Unlock()
+		t.inFlight.Add(-1, consumer, topic)
+		t.events.Inc(consumer, topic, string(outcome))
+		t.duration.Observe(t.now().Sub(started).Seconds(), consumer, topic)
+	}
+}
+
+func (t *Telemetry) SetConsumerLag(consumer, topic string, lag int64) {
+	t.lag.Set(float64(lag), consumer, topic)
+}
+
+func (t *Telemetry) OldestInFlight() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var oldest time.Duration
+	now := t.now()
+	for _, started := range t.running {
+		oldest = max(oldest, now.Sub(started))
+	}
+	return oldest
+}
@@ -32,16 +32,18 @@ fi
 
 GOCACHE="$GOCACHE" go run ./apps/creator-studio-service/cmd &
 pids=($!)
-GOCACHE="$GOCACHE" go run ./workers/worker-ingest/cmd &
+WORKER_HTTP_ADDR=":9091" GOCACHE="$GOCACHE" go run ./workers/worker-ingest/cmd &
 pids+=($!)
-GOCACHE="$GOCACHE" go run ./workers/worker-transcode/cmd &
+WORKER_HTTP_ADDR=":9092" GOCACHE="$GOCACHE" go run ./workers/worker-transcode/cmd &
 pids+=($!)
-GOCACHE="$GOCACHE" go run ./workers/worker-policy/cmd &
+WORKER_HTTP_ADDR=":9093" GOCACHE="$GOCACHE" go run ./workers/worker-policy/cmd &
 pids+=($!)
-GOCACHE="$GOCACHE" go run ./workers/worker-publish/cmd &
+WORKER_HTTP_ADDR=":9094" GOCACHE="$GOCACHE" go run ./workers/worker-publish/cmd &
 pids+=($!)
 if [[ -n "${DATABASE_URL:-}" ]]; then
-  GOCACHE="$GOCACHE" go run ./workers/worker-outbox-relay/cmd &
+  WORKER_HTTP_ADDR=":9095" GOCACHE="$GOCACHE" go run ./workers/worker-outbox-relay/cmd &
+  pids+=($!)
+  WORKER_HTTP_ADDR=":9096" GOCACHE="$GOCACHE" go run ./workers/worker-dlq/cmd &
   pids+=($!)
 fi
 
@@ -127,6 +127,7 @@ for dep in \
   worker-policy \
   worker-publish \
   worker-outbox-relay \
+  worker-dlq \
   worker-gen-orchestrator \
   worker-gen-nim \
   worker-gen-qc; do
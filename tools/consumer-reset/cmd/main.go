@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/tools/consumer-reset/internal/config"
+	"github.com/delqhi/mikasmissions/platform/tools/consumer-reset/internal/reset"
+	"github.com/nats-io/nats.go"
+)
+
+func main() {
+	opts, err := config.Parse(os.Args[1:], os.Getenv, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid arguments: %v\n\n", err)
+		printUsage()
+		os.Exit(2)
+	}
+	conn, err := nats.Connect(opts.NATSURL, nats.Name("mikasmissions-consumer-reset"), nats.Timeout(opts.Timeout))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "connect failed: %v\n", err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+	js, err := conn.JetStream(nats.MaxWait(opts.Timeout))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jetstream context failed: %v\n", err)
+		os.Exit(1)
+	}
+	result, err := reset.Run(js, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "reset failed: %v\n", err)
+		os.Exit(1)
+	}
+	printResult(result)
+}
+
+func printResult(result reset.Result) {
+	fmt.Printf("consumer:        %s/%s\n", result.Stream, result.Consumer)
+	fmt.Printf("ack floor seq:   %d\n", result.AckFloor)
+	fmt.Printf("pending:         %d\n", result.NumPending)
+	fmt.Printf("ack pending:     %d\n", result.NumAckPending)
+	fmt.Printf("target policy:   %s\n", result.Target)
+	if result.TargetSeq > 0 {
+		fmt.Printf("target sequence: %d\n", result.TargetSeq)
+	}
+	if result.TargetTime != nil {
+		fmt.Printf("target time:     %s\n", result.TargetTime.UTC().Format(time.RFC3339))
+	}
+	if !result.Applied {
+		fmt.Println("dry-run: no changes applied (rerun with -dry-run=false)")
+		return
+	}
+	fmt.Println("consumer recreated at target position")
+}
+
+func printUsage() {
+	fmt.Println("Usage: go run ./tools/consumer-reset/cmd -consumer=<name> (-stream=<name>|-topic=<topic>) [flags]")
+	fmt.Println("")
+	fmt.Println("Stop every worker bound to the consumer before applying a reset.")
+	fmt.Println("")
+	fmt.Println("Examples:")
+	fmt.Println("  go run ./tools/consumer-reset/cmd -consumer=worker-reco-feature -topic=watch.event.v1 -deliver=all")
+	fmt.Println("  go run ./tools/consumer-reset/cmd -consumer=worker-publish -stream=MM_MEDIA -deliver=time -since=6h -dry-run=false")
+	fmt.Println("  go run ./tools/consumer-reset/cmd -consumer=worker-gen-qc -stream=MM_GENERATION -deliver=sequence -sequence=1042 -dry-run=false")
+}
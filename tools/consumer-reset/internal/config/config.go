@@ -0,0 +1,112 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type Deliver string
+
+const (
+	DeliverAll      Deliver = "all"
+	DeliverNew      Deliver = "new"
+	DeliverSequence Deliver = "sequence"
+	DeliverTime     Deliver = "time"
+)
+
+type Options struct {
+	NATSURL  string
+	Stream   string
+	Topic    string
+	Consumer string
+	Deliver  Deliver
+	Sequence uint64
+	Since    time.Time
+	DryRun   bool
+	Timeout  time.Duration
+}
+
+func Parse(args []string, getenv func(string) string, now time.Time) (Options, error) {
+	var opts Options
+	var deliverRaw string
+	var sinceRaw string
+	fs := flag.NewFlagSet("consumer-reset", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&opts.NATSURL, "nats-url", "", "NATS URL (falls back to NATS_URL)")
+	fs.StringVar(&opts.Stream, "stream", "", "Stream name (optional when -topic is set)")
+	fs.StringVar(&opts.Topic, "topic", "", "Topic used to look up the stream")
+	fs.StringVar(&opts.Consumer, "consumer", "", "Durable consumer name, e.g. worker-reco-feature")
+	fs.StringVar(&deliverRaw, "deliver", string(DeliverAll), "Start position: all|new|sequence|time")
+	fs.Uint64Var(&opts.Sequence, "sequence", 0, "Stream sequence for -deliver=sequence")
+	fs.StringVar(&sinceRaw, "since", "", "RFC3339 timestamp or duration ago (e.g. 6h) for -deliver=time")
+	fs.BoolVar(&opts.DryRun, "dry-run", true, "Preview-only; print current and target position")
+	fs.DurationVar(&opts.Timeout, "timeout", 15*time.Second, "Overall command timeout")
+	if err := fs.Parse(args); err != nil {
+		return Options{}, err
+	}
+	if opts.NATSURL == "" {
+		opts.NATSURL = getenv("NATS_URL")
+	}
+	deliver, err := parseDeliver(deliverRaw)
+	if err != nil {
+		return Options{}, err
+	}
+	opts.Deliver = deliver
+	if sinceRaw != "" {
+		since, err := parseSince(sinceRaw, now)
+		if err != nil {
+			return Options{}, err
+		}
+		opts.Since = since
+	}
+	if err := opts.validate(); err != nil {
+		return Options{}, err
+	}
+	return opts, nil
+}
+
+func parseDeliver(raw string) (Deliver, error) {
+	switch Deliver(strings.ToLower(raw)) {
+	case DeliverAll, DeliverNew, DeliverSequence, DeliverTime:
+		return Deliver(strings.ToLower(raw)), nil
+	default:
+		return "", fmt.Errorf("unsupported deliver %q", raw)
+	}
+}
+
+func parseSince(raw string, now time.Time) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+		return parsed.UTC(), nil
+	}
+	ago, err := time.ParseDuration(raw)
+	if err != nil || ago <= 0 {
+		return time.Time{}, fmt.Errorf("since must be RFC3339 or a positive duration, got %q", raw)
+	}
+	return now.UTC().Add(-ago), nil
+}
+
+func (o Options) validate() error {
+	if o.NATSURL == "" {
+		return errors.New("NATS URL is required (flag -nats-url or env NATS_URL)")
+	}
+	if o.Consumer == "" {
+		return errors.New("consumer is required")
+	}
+	if o.Stream == "" && o.Topic == "" {
+		return errors.New("stream or topic is required")
+	}
+	if o.Deliver == DeliverSequence && o.Sequence == 0 {
+		return errors.New("sequence must be > 0 for deliver=sequence")
+	}
+	if o.Deliver == DeliverTime && o.Since.IsZero() {
+		return errors.New("since is required for deliver=time")
+	}
+	if o.Timeout <= 0 {
+		return errors.New("timeout must be > 0")
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var fixedNow = time.Date(2026, 3, 12, 10, 0, 0, 0, time.UTC)
+
+func TestParseDefaultsAndEnvNATSURL(t *testing.T) {
+	opts, err := Parse([]string{"-consumer", "worker-reco-feature", "-topic", "watch.event.v1"}, func(key string) string {
+		if key == "NATS_URL" {
+			return "nats://example:4222"
+		}
+		return ""
+	}, fixedNow)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if opts.NATSURL != "nats://example:4222" {
+		t.Fatalf("unexpected nats url: %s", opts.NATSURL)
+	}
+	if opts.Deliver != DeliverAll {
+		t.Fatalf("unexpected default deliver: %s", opts.Deliver)
+	}
+	if !opts.DryRun {
+		t.Fatalf("expected dry-run true by default")
+	}
+}
+
+func TestParseSinceAcceptsDurationAgo(t *testing.T) {
+	opts, err := Parse([]string{
+		"-nats-url", "nats://example:4222", "-consumer", "worker-publish", "-stream", "MM_MEDIA",
+		"-deliver", "time", "-since", "6h",
+	}, func(string) string { return "" }, fixedNow)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if !opts.Since.Equal(fixedNow.Add(-6 * time.Hour)) {
+		t.Fatalf("unexpected since: %s", opts.Since)
+	}
+}
+
+func TestParseRejectsInvalidCombinations(t *testing.T) {
+	cases := map[string][]string{
+		"consumer is required":       {"-nats-url", "nats://x", "-stream", "MM_MEDIA"},
+		"stream or topic":            {"-nats-url", "nats://x", "-consumer", "worker-publish"},
+		"sequence must be > 0":       {"-nats-url", "nats://x", "-consumer", "c", "-stream", "s", "-deliver", "sequence"},
+		"since is required":          {"-nats-url", "nats://x", "-consumer", "c", "-stream", "s", "-deliver", "time"},
+		"unsupported deliver":        {"-nats-url", "nats://x", "-consumer", "c", "-stream", "s", "-deliver", "last"},
+		"NATS URL is required":       {"-consumer", "c", "-stream", "s"},
+		"since must be RFC3339 or a": {"-nats-url", "nats://x", "-consumer", "c", "-stream", "s", "-since", "yesterday"},
+	}
+	for expected, args := range cases {
+		_, err := Parse(args, func(string) string { return "" }, fixedNow)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected %q error, got %v", expected, err)
+		}
+	}
+}
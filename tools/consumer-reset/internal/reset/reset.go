@@ -0,0 +1,78 @@
+package reset
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/tools/consumer-reset/internal/config"
+	"github.com/nats-io/nats.go"
+)
+
+type Result struct {
+	Stream        string
+	Consumer      string
+	AckFloor      uint64
+	NumPending    uint64
+	NumAckPending int
+	Target        config.Deliver
+	TargetSeq     uint64
+	TargetTime    *time.Time
+	Applied       bool
+}
+
+func Run(js nats.JetStreamContext, opts config.Options) (Result, error) {
+	stream := opts.Stream
+	if stream == "" {
+		resolved, err := js.StreamNameBySubject(opts.Topic)
+		if err != nil {
+			return Result{}, fmt.Errorf("resolve stream for topic %s: %w", opts.Topic, err)
+		}
+		stream = resolved
+	}
+	info, err := js.ConsumerInfo(stream, opts.Consumer)
+	if err != nil {
+		return Result{}, fmt.Errorf("consumer info %s/%s: %w", stream, opts.Consumer, err)
+	}
+	target := retarget(info.Config, opts)
+	result := Result{
+		Stream:        stream,
+		Consumer:      opts.Consumer,
+		AckFloor:      info.AckFloor.Stream,
+		NumPending:    info.NumPending,
+		NumAckPending: info.NumAckPending,
+		Target:        opts.Deliver,
+		TargetSeq:     target.OptStartSeq,
+		TargetTime:    target.OptStartTime,
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+	if err := js.DeleteConsumer(stream, opts.Consumer); err != nil {
+		return result, fmt.Errorf("delete consumer %s/%s: %w", stream, opts.Consumer, err)
+	}
+	if _, err := js.AddConsumer(stream, &target); err != nil {
+		return result, fmt.Errorf("recreate consumer %s/%s: %w", stream, opts.Consumer, err)
+	}
+	result.Applied = true
+	return result, nil
+}
+
+func retarget(current nats.ConsumerConfig, opts config.Options) nats.ConsumerConfig {
+	target := current
+	target.OptStartSeq = 0
+	target.OptStartTime = nil
+	switch opts.Deliver {
+	case config.DeliverNew:
+		target.DeliverPolicy = nats.DeliverNewPolicy
+	case config.DeliverSequence:
+		target.DeliverPolicy = nats.DeliverByStartSequencePolicy
+		target.OptStartSeq = opts.Sequence
+	case config.DeliverTime:
+		since := opts.Since
+		target.DeliverPolicy = nats.DeliverByStartTimePolicy
+		target.OptStartTime = &since
+	default:
+		target.DeliverPolicy = nats.DeliverAllPolicy
+	}
+	return target
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/tools/event-probe/internal/config"
+	"github.com/delqhi/mikasmissions/platform/tools/event-probe/internal/inspect"
+	"github.com/delqhi/mikasmissions/platform/tools/event-probe/internal/render"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+)
+
+func runWait(ctx context.Context, conn *nats.Conn, opts config.Options) {
+	ch := make(chan *nats.Msg, 1)
+	sub, err := conn.Subscribe(opts.Topic, func(msg *nats.Msg) {
+		select {
+		case ch <- msg:
+		default:
+		}
+	})
+	if err != nil {
+		exitErr("subscribe", err)
+	}
+	defer sub.Unsubscribe()
+
+	select {
+	case msg := <-ch:
+		fmt.Println(string(msg.Data))
+	case <-ctx.Done():
+		fmt.Fprintf(os.Stderr, "timeout waiting for topic %s\n", opts.Topic)
+		os.Exit(2)
+	}
+}
+
+func runTail(ctx context.Context, conn *nats.Conn, opts config.Options) {
+	ch := make(chan *nats.Msg, 256)
+	sub, err := conn.ChanSubscribe(opts.Topic, ch)
+	if err != nil {
+		exitErr("subscribe", err)
+	}
+	defer sub.Unsubscribe()
+	fmt.Fprintf(os.Stderr, "tailing %s (ctrl-c to stop)\n", opts.Topic)
+	for seen := 0; opts.Count == 0 || seen < opts.Count; seen++ {
+		select {
+		case msg := <-ch:
+			render.WriteMessage(os.Stdout, messageFromNATS(msg), opts.Validate)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func messageFromNATS(msg *nats.Msg) render.Message {
+	message := render.Message{Subject: msg.Subject, Received: time.Now(), Headers: map[string]string{}, Data: msg.Data}
+	for key, values := range msg.Header {
+		if len(values) > 0 {
+			message.Headers[key] = values[0]
+		}
+	}
+	if meta, err := msg.Metadata(); err == nil {
+		message.Sequence = meta.Sequence.Stream
+	}
+	return message
+}
+
+func runStreams(ctx context.Context, conn *nats.Conn, opts config.Options) {
+	streams, err := inspect.Streams(ctx, jetStream(conn), opts.Stream)
+	if err != nil {
+		exitErr("list streams", err)
+	}
+	if opts.Output == config.OutputJSON {
+		emitJSON(streams)
+		return
+	}
+	if err := render.WriteStreams(os.Stdout, streams); err != nil {
+		exitErr("write streams", err)
+	}
+}
+
+func runConsumers(ctx context.Context, conn *nats.Conn, opts config.Options) {
+	consumers, err := inspect.Consumers(ctx, jetStream(conn), opts.Stream)
+	if err != nil {
+		exitErr("list consumers", err)
+	}
+	if opts.Output == config.OutputJSON {
+		emitJSON(consumers)
+		return
+	}
+	if err := render.WriteConsumers(os.Stdout, consumers); err != nil {
+		exitErr("write consumers", err)
+	}
+}
+
+func jetStream(conn *nats.Conn) nats.JetStreamContext {
+	js, err := conn.JetStream()
+	if err != nil {
+		exitErr("jetstream context", err)
+	}
+	return js
+}
+
+func runPublish(ctx context.Context, conn *nats.Conn, opts config.Options) {
+	payload, err := readPayload(opts.File)
+	if err != nil {
+		exitErr("read payload", err)
+	}
+	if !json.Valid(payload) {
+		exitErr("read payload", errors.New("payload is not valid JSON"))
+	}
+	if opts.Validate {
+		if err := render.Validate(opts.Topic, payload); err != nil {
+			exitErr("validate payload (pass -validate=false to skip)", err)
+		}
+	}
+	eventID := opts.EventID
+	if eventID == "" {
+		eventID = uuid.NewString()
+	}
+	event := queue.Event{ID: eventID, Topic: opts.Topic, Payload: payload, Headers: opts.Headers}
+	msg := queue.NewNATSMsg(queue.ContextWithProducer(ctx, "event-probe"), event)
+	if _, err := jetStream(conn).PublishMsg(msg); err != nil {
+		exitErr("publish", err)
+	}
+	fmt.Printf("published %s to %s\n", eventID, opts.Topic)
+}
+
+func readPayload(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func emitJSON(value any) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		exitErr("encode json output", err)
+	}
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type Command string
+
+const (
+	CommandWait      Command = "wait"
+	CommandStreams   Command = "streams"
+	CommandConsumers Command = "consumers"
+	CommandTail      Command = "tail"
+	CommandPublish   Command = "publish"
+)
+
+type Output string
+
+const (
+	OutputText Output = "text"
+	OutputJSON Output = "json"
+)
+
+type Options struct {
+	Command  Command
+	NATSURL  string
+	Topic    string
+	Stream   string
+	File     string
+	EventID  string
+	Headers  map[string]string
+	Count    int
+	Validate bool
+	Output   Output
+	Timeout  time.Duration
+}
+
+type headerFlags map[string]string
+
+func (h headerFlags) String() string {
+	return fmt.Sprint(map[string]string(h))
+}
+
+func (h headerFlags) Set(raw string) error {
+	key, value, ok := strings.Cut(raw, "=")
+	if !ok || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("header %q must be key=value", raw)
+	}
+	h[strings.TrimSpace(key)] = value
+	return nil
+}
+
+func Parse(args []string, getenv func(string) string) (Options, error) {
+	opts := Options{Command: CommandWait, Headers: map[string]string{}}
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		opts.Command = Command(args[0])
+		args = args[1:]
+	}
+	var outputRaw string
+	fs := flag.NewFlagSet("event-probe "+string(opts.Command), flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&opts.NATSURL, "nats-url", "", "NATS URL (falls back to NATS_URL, then nats://127.0.0.1:4222)")
+	fs.StringVar(&opts.Topic, "topic", "", "Subject to wait on, tail or publish to")
+	fs.StringVar(&opts.Stream, "stream", "", "Stream filter for streams and consumers")
+	fs.StringVar(&opts.File, "file", "", "JSON payload file for publish (- for stdin)")
+	fs.StringVar(&opts.EventID, "event-id", "", "Event ID for publish (default: random UUID)")
+	fs.Var(headerFlags(opts.Headers), "header", "Extra event header key=value for publish (repeatable)")
+	fs.IntVar(&opts.Count, "count", 0, "Stop tail after this many messages (0 = until interrupted)")
+	fs.BoolVar(&opts.Validate, "validate", true, "Validate payloads against contracts-events types")
+	fs.StringVar(&outputRaw, "output", string(OutputText), "Output format for streams and consumers: text|json")
+	fs.DurationVar(&opts.Timeout, "timeout", 0, "Wait timeout (default 20s for wait, none for tail, 10s otherwise)")
+	if err := fs.Parse(args); err != nil {
+		return Options{}, err
+	}
+	if fs.NArg() > 0 {
+		return Options{}, fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+	if opts.NATSURL == "" {
+		opts.NATSURL = getenv("NATS_URL")
+	}
+	if opts.NATSURL == "" {
+		opts.NATSURL = "nats://127.0.0.1:4222"
+	}
+	opts.Output = Output(outputRaw)
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultTimeout(opts.Command)
+	}
+	if opts.Command == CommandWait && opts.Topic == "" {
+		opts.Topic = "episode.published.v1"
+	}
+	if err := opts.validate(); err != nil {
+		return Options{}, err
+	}
+	return opts, nil
+}
+
+func defaultTimeout(command Command) time.Duration {
+	switch command {
+	case CommandWait:
+		return 20 * time.Second
+	case CommandTail:
+		return 0
+	default:
+		return 10 * time.Second
+	}
+}
+
+func (o Options) validate() error {
+	switch o.Command {
+	case CommandWait, CommandStreams, CommandConsumers, CommandTail, CommandPublish:
+	default:
+		return fmt.Errorf("unsupported command %q", o.Command)
+	}
+	if o.Output != OutputText && o.Output != OutputJSON {
+		return fmt.Errorf("unsupported output %q", o.Output)
+	}
+	if o.Timeout < 0 {
+		return errors.New("timeout must be >= 0")
+	}
+	if o.Count < 0 {
+		return errors.New("count must be >= 0")
+	}
+	if (o.Command == CommandTail || o.Command == CommandPublish) && o.Topic == "" {
+		return fmt.Errorf("topic is required for %s", o.Command)
+	}
+	if o.Command == CommandPublish && o.File == "" {
+		return errors.New("file is required for publish")
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func env(values map[string]string) func(string) string {
+	return func(key string) string { return values[key] }
+}
+
+func TestParseKeepsLegacyWaitFlags(t *testing.T) {
+	opts, err := Parse([]string{"-topic", "episode.published.v1", "-timeout", "25s"}, env(map[string]string{"NATS_URL": "nats://nats:4222"}))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if opts.Command != CommandWait || opts.Timeout != 25*time.Second || opts.NATSURL != "nats://nats:4222" {
+		t.Fatalf("unexpected options %+v", opts)
+	}
+}
+
+func TestParseSubcommands(t *testing.T) {
+	opts, err := Parse([]string{"publish", "-topic", "media.uploaded.v1", "-file", "event.json", "-header", "Mm-Trace-Id=trace-1"}, env(nil))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if opts.Command != CommandPublish || opts.Headers["Mm-Trace-Id"] != "trace-1" || opts.Timeout != 10*time.Second {
+		t.Fatalf("unexpected options %+v", opts)
+	}
+	opts, err = Parse([]string{"tail", "-topic", "media.>", "-count", "3"}, env(nil))
+	if err != nil || opts.Timeout != 0 || opts.Count != 3 || opts.NATSURL != "nats://127.0.0.1:4222" {
+		t.Fatalf("unexpected tail options %+v err=%v", opts, err)
+	}
+}
+
+func TestParseRejectsInvalidInput(t *testing.T) {
+	for _, args := range [][]string{
+		{"drain"},
+		{"tail"},
+		{"publish", "-topic", "media.uploaded.v1"},
+		{"streams", "-output", "yaml"},
+		{"publish", "-topic", "x", "-file", "f", "-header", "novalue"},
+		{"streams", "extra"},
+	} {
+		if _, err := Parse(args, env(nil)); err == nil {
+			t.Fatalf("expected %v to be rejected", args)
+		}
+	}
+}
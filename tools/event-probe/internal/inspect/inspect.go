@@ -0,0 +1,110 @@
+package inspect
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+type StreamReport struct {
+	Name      string    `json:"name"`
+	Subjects  []string  `json:"subjects"`
+	Messages  uint64    `json:"messages"`
+	Bytes     uint64    `json:"bytes"`
+	FirstSeq  uint64    `json:"first_seq"`
+	LastSeq   uint64    `json:"last_seq"`
+	LastTime  time.Time `json:"last_time"`
+	Consumers int       `json:"consumers"`
+}
+
+type ConsumerReport struct {
+	Stream         string     `json:"stream"`
+	Name           string     `json:"name"`
+	FilterSubject  string     `json:"filter_subject,omitempty"`
+	NumPending     uint64     `json:"num_pending"`
+	NumAckPending  int        `json:"num_ack_pending"`
+	NumRedelivered int        `json:"num_redelivered"`
+	NumWaiting     int        `json:"num_waiting"`
+	DeliveredSeq   uint64     `json:"delivered_stream_seq"`
+	AckFloorSeq    uint64     `json:"ack_floor_stream_seq"`
+	MaxDeliver     int        `json:"max_deliver"`
+	LastActive     *time.Time `json:"last_active,omitempty"`
+}
+
+type JetStream interface {
+	StreamsInfo(opts ...nats.JSOpt) <-chan *nats.StreamInfo
+	ConsumersInfo(stream string, opts ...nats.JSOpt) <-chan *nats.ConsumerInfo
+}
+
+func Streams(ctx context.Context, js JetStream, stream string) ([]StreamReport, error) {
+	var reports []StreamReport
+	for info := range js.StreamsInfo(nats.Context(ctx)) {
+		if stream != "" && info.Config.Name != stream {
+			continue
+		}
+		reports = append(reports, NewStreamReport(info))
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("list streams: %w", err)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Name < reports[j].Name })
+	return reports, nil
+}
+
+func Consumers(ctx context.Context, js JetStream, stream string) ([]ConsumerReport, error) {
+	streams, err := Streams(ctx, js, stream)
+	if err != nil {
+		return nil, err
+	}
+	if stream != "" && len(streams) == 0 {
+		return nil, fmt.Errorf("stream %s not found", stream)
+	}
+	var reports []ConsumerReport
+	for _, report := range streams {
+		for info := range js.ConsumersInfo(report.Name, nats.Context(ctx)) {
+			reports = append(reports, NewConsumerReport(info))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("list consumers: %w", err)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Stream != reports[j].Stream {
+			return reports[i].Stream < reports[j].Stream
+		}
+		return reports[i].Name < reports[j].Name
+	})
+	return reports, nil
+}
+
+func NewStreamReport(info *nats.StreamInfo) StreamReport {
+	return StreamReport{
+		Name:      info.Config.Name,
+		Subjects:  info.Config.Subjects,
+		Messages:  info.State.Msgs,
+		Bytes:     info.State.Bytes,
+		FirstSeq:  info.State.FirstSeq,
+		LastSeq:   info.State.LastSeq,
+		LastTime:  info.State.LastTime,
+		Consumers: info.State.Consumers,
+	}
+}
+
+func NewConsumerReport(info *nats.ConsumerInfo) ConsumerReport {
+	return ConsumerReport{
+		Stream:         info.Stream,
+		Name:           info.Name,
+		FilterSubject:  info.Config.FilterSubject,
+		NumPending:     info.NumPending,
+		NumAckPending:  info.NumAckPending,
+		NumRedelivered: info.NumRedelivered,
+		NumWaiting:     info.NumWaiting,
+		DeliveredSeq:   info.Delivered.Stream,
+		AckFloorSeq:    info.AckFloor.Stream,
+		MaxDeliver:     info.Config.MaxDeliver,
+		LastActive:     info.Delivered.Last,
+	}
+}
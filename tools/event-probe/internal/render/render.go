@@ -0,0 +1,115 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/tools/event-probe/internal/inspect"
+)
+
+type Message struct {
+	Subject  string
+	Sequence uint64
+	Received time.Time
+	Headers  map[string]string
+	Data     []byte
+}
+
+func Validate(topic string, payload []byte) error {
+	if queue.IsDLQTopic(topic) {
+		dead, err := queue.DecodeDLQEvent(queue.Event{Topic: topic, Payload: payload})
+		if err != nil {
+			return err
+		}
+		if dead.EventID == "" || dead.Error == "" {
+			return fmt.Errorf("%s envelope is missing event_id or error", topic)
+		}
+		return nil
+	}
+	_, err := contractsevents.DecodeAndValidate(topic, payload)
+	return err
+}
+
+func WriteMessage(w io.Writer, msg Message, validate bool) {
+	fmt.Fprintf(w, "--- %s", msg.Subject)
+	if msg.Sequence > 0 {
+		fmt.Fprintf(w, " seq=%d", msg.Sequence)
+	}
+	fmt.Fprintf(w, " received=%s\n", msg.Received.UTC().Format(time.RFC3339Nano))
+	keys := make([]string, 0, len(msg.Headers))
+	for key := range msg.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s: %s\n", key, msg.Headers[key])
+	}
+	if validate {
+		fmt.Fprintf(w, "contract: %s\n", contractStatus(msg.Subject, msg.Data))
+	}
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, msg.Data, "", "  "); err != nil {
+		fmt.Fprintf(w, "%s\n", msg.Data)
+		return
+	}
+	fmt.Fprintf(w, "%s\n", pretty.String())
+}
+
+func contractStatus(topic string, payload []byte) string {
+	if _, ok := contractsevents.ForTopic(topic); !ok && !queue.IsDLQTopic(topic) {
+		return "none registered"
+	}
+	if err := Validate(topic, payload); err != nil {
+		return "INVALID: " + err.Error()
+	}
+	return "ok"
+}
+
+func WriteStreams(w io.Writer, streams []inspect.StreamReport) error {
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "STREAM\tSUBJECTS\tMESSAGES\tBYTES\tFIRST_SEQ\tLAST_SEQ\tCONSUMERS\tLAST_MESSAGE")
+	for _, stream := range streams {
+		fmt.Fprintf(table, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%s\n",
+			stream.Name, strings.Join(stream.Subjects, ","), stream.Messages, stream.Bytes,
+			stream.FirstSeq, stream.LastSeq, stream.Consumers, formatTime(stream.LastTime))
+	}
+	return table.Flush()
+}
+
+func WriteConsumers(w io.Writer, consumers []inspect.ConsumerReport) error {
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "STREAM\tCONSUMER\tFILTER\tPENDING\tACK_PENDING\tREDELIVERED\tWAITING\tDELIVERED_SEQ\tACK_FLOOR\tMAX_DELIVER\tLAST_ACTIVE")
+	for _, consumer := range consumers {
+		lastActive := "-"
+		if consumer.LastActive != nil {
+			lastActive = formatTime(*consumer.LastActive)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%s\n",
+			consumer.Stream, consumer.Name, valueOrDash(consumer.FilterSubject), consumer.NumPending,
+			consumer.NumAckPending, consumer.NumRedelivered, consumer.NumWaiting,
+			consumer.DeliveredSeq, consumer.AckFloorSeq, consumer.MaxDeliver, lastActive)
+	}
+	return table.Flush()
+}
+
+func formatTime(value time.Time) string {
+	if value.IsZero() {
+		return "-"
+	}
+	return value.UTC().Format(time.RFC3339)
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
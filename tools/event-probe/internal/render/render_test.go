@@ -0,0 +1,70 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/tools/event-probe/internal/inspect"
+)
+
+func TestWriteMessagePrettyPrintsAndValidates(t *testing.T) {
+	var out bytes.Buffer
+	WriteMessage(&out, Message{
+		Subject:  "media.uploaded.v1",
+		Sequence: 42,
+		Received: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Headers:  map[string]string{"Mm-Trace-Id": "trace-1", "Mm-Producer": "creator-studio-service"},
+		Data:     []byte(`{"asset_id":"a1","source_url":"https://cdn/x.mp4","uploader_id":"u1","trace_id":"tr1"}`),
+	}, true)
+	text := out.String()
+	for _, want := range []string{
+		"--- media.uploaded.v1 seq=42 received=2026-01-02T03:04:05Z\n",
+		"Mm-Producer: creator-studio-service\nMm-Trace-Id: trace-1\n",
+		"contract: ok\n",
+		"  \"asset_id\": \"a1\",\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("missing %q in:\n%s", want, text)
+		}
+	}
+
+	out.Reset()
+	WriteMessage(&out, Message{Subject: "media.uploaded.v1", Data: []byte(`{"asset_id":"a1"}`)}, true)
+	if !strings.Contains(out.String(), "contract: INVALID: media.uploaded.v1 has missing required fields") {
+		t.Fatalf("expected invalid contract, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	WriteMessage(&out, Message{Subject: "analytics.rollup.v1", Data: []byte(`not json`)}, true)
+	if !strings.Contains(out.String(), "contract: none registered\nnot json\n") {
+		t.Fatalf("expected raw unregistered payload, got:\n%s", out.String())
+	}
+}
+
+func TestValidateDeadLetterEnvelope(t *testing.T) {
+	if err := Validate("media.uploaded.v1.dlq.v1", []byte(`{"event_id":"evt-1","error":"boom","payload":{}}`)); err != nil {
+		t.Fatalf("expected valid dlq envelope, got %v", err)
+	}
+	if err := Validate("media.uploaded.v1.dlq.v1", []byte(`{"payload":{}}`)); err == nil {
+		t.Fatalf("expected invalid dlq envelope")
+	}
+}
+
+func TestWriteConsumersTable(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteConsumers(&out, []inspect.ConsumerReport{{
+		Stream: "MM_MEDIA", Name: "worker-transcode", NumPending: 12, NumAckPending: 2, NumRedelivered: 1, MaxDeliver: 5,
+	}}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "STREAM") || !strings.Contains(lines[1], "worker-transcode") {
+		t.Fatalf("unexpected table:\n%s", out.String())
+	}
+	fields := strings.Fields(lines[1])
+	if fields[3] != "12" || fields[4] != "2" || fields[5] != "1" {
+		t.Fatalf("unexpected counts %v", fields)
+	}
+}
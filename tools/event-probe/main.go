@@ -1,49 +1,72 @@
 package main
 
 import (
-	"flag"
+	"context"
 	"fmt"
 	"os"
-	"time"
+	"os/signal"
+	"syscall"
 
+	"github.com/delqhi/mikasmissions/platform/tools/event-probe/internal/config"
 	"github.com/nats-io/nats.go"
 )
 
 func main() {
-	var natsURL string
-	var topic string
-	var timeout time.Duration
-	flag.StringVar(&natsURL, "nats-url", "nats://127.0.0.1:4222", "NATS URL")
-	flag.StringVar(&topic, "topic", "episode.published.v1", "subject to subscribe")
-	flag.DurationVar(&timeout, "timeout", 20*time.Second, "wait timeout")
-	flag.Parse()
-
-	conn, err := nats.Connect(natsURL)
+	opts, err := config.Parse(os.Args[1:], os.Getenv)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "connect failed: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "invalid arguments: %v\n\n", err)
+		printUsage()
+		os.Exit(2)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
 	}
-	defer conn.Close()
 
-	ch := make(chan *nats.Msg, 1)
-	sub, err := conn.Subscribe(topic, func(msg *nats.Msg) {
-		select {
-		case ch <- msg:
-		default:
-		}
-	})
+	conn, err := nats.Connect(opts.NATSURL, nats.Name("mikasmissions-event-probe"))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "subscribe failed: %v\n", err)
-		os.Exit(1)
+		exitErr("connect", err)
 	}
-	defer sub.Unsubscribe()
+	defer conn.Close()
 
-	select {
-	case msg := <-ch:
-		fmt.Println(string(msg.Data))
-		os.Exit(0)
-	case <-time.After(timeout):
-		fmt.Fprintf(os.Stderr, "timeout waiting for topic %s\n", topic)
-		os.Exit(2)
+	switch opts.Command {
+	case config.CommandWait:
+		runWait(ctx, conn, opts)
+	case config.CommandTail:
+		runTail(ctx, conn, opts)
+	case config.CommandStreams:
+		runStreams(ctx, conn, opts)
+	case config.CommandConsumers:
+		runConsumers(ctx, conn, opts)
+	case config.CommandPublish:
+		runPublish(ctx, conn, opts)
 	}
 }
+
+func exitErr(op string, err error) {
+	fmt.Fprintf(os.Stderr, "%s failed: %v\n", op, err)
+	os.Exit(1)
+}
+
+func printUsage() {
+	fmt.Println("Usage: go run ./tools/event-probe [command] [flags]")
+	fmt.Println("")
+	fmt.Println("Commands:")
+	fmt.Println("  wait        Wait for one message on -topic and print it (default)")
+	fmt.Println("  streams     List JetStream streams with message counts and sequences")
+	fmt.Println("  consumers   List durable consumers with pending, ack-pending and redelivery counts")
+	fmt.Println("  tail        Print messages on -topic continuously, pretty-printed and contract-validated")
+	fmt.Println("  publish     Publish the JSON payload in -file to -topic")
+	fmt.Println("")
+	fmt.Println("NATS URL comes from -nats-url or NATS_URL. Add -output=json to streams/consumers for machine-readable output.")
+	fmt.Println("")
+	fmt.Println("Examples:")
+	fmt.Println("  go run ./tools/event-probe -topic=episode.published.v1 -timeout=25s")
+	fmt.Println("  go run ./tools/event-probe streams")
+	fmt.Println("  go run ./tools/event-probe consumers -stream=MM_MEDIA")
+	fmt.Println("  go run ./tools/event-probe tail -topic='media.>' -count=10")
+	fmt.Println("  go run ./tools/event-probe publish -topic=media.uploaded.v1 -file=uploaded.json -header=Mm-Trace-Id=debug-1")
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-replay/internal/config"
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-replay/internal/store"
+)
+
+type retryingRow struct {
+	store.OutboxRow
+	Schedule retrySchedule `json:"schedule"`
+}
+
+func runListFailed(ctx context.Context, repo *store.Store, opts config.Options) {
+	rows, err := repo.ListFailed(ctx, opts.Topic, opts.Limit)
+	if err != nil {
+		exitErr("list failed rows", err)
+	}
+	if opts.Output == config.OutputJSON {
+		emitJSON(rows)
+		return
+	}
+	if len(rows) == 0 {
+		fmt.Println("no failed outbox rows")
+		return
+	}
+	fmt.Println("event_id\ttopic\tattempts\tavailable_at\tlast_error")
+	for _, row := range rows {
+		fmt.Printf("%s\t%s\t%d\t%s\t%s\n", row.EventID, row.Topic, row.Attempts, formatTime(row.AvailableAt), row.LastError)
+	}
+}
+
+func runListRetrying(ctx context.Context, repo *store.Store, opts config.Options) {
+	policies, err := loadRetryPolicies(opts.RetryPolicies)
+	if err != nil {
+		exitErr("load retry policies", err)
+	}
+	rows, err := repo.ListRetrying(ctx, opts.Topic, opts.Limit)
+	if err != nil {
+		exitErr("list retrying rows", err)
+	}
+	scheduled := make([]retryingRow, 0, len(rows))
+	for _, row := range rows {
+		scheduled = append(scheduled, retryingRow{OutboxRow: row, Schedule: retryScheduleFor(policies.For(row.Topic), row)})
+	}
+	if opts.Output == config.OutputJSON {
+		emitJSON(scheduled)
+		return
+	}
+	if len(scheduled) == 0 {
+		fmt.Println("no retrying outbox rows")
+		return
+	}
+	fmt.Println("event_id\ttopic\tattempts\tnext_retry_at\tthen_backoff\tgives_up_at\tlast_error")
+	for _, row := range scheduled {
+		fmt.Printf(
+			"%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			row.EventID,
+			row.Topic,
+			row.Schedule.Attempts,
+			formatTime(row.AvailableAt),
+			row.Schedule.ThenBackoff,
+			row.Schedule.GivesUpAt,
+			row.LastError,
+		)
+	}
+}
+
+func runList(ctx context.Context, repo *store.Store, opts config.Options) {
+	rows, err := repo.List(ctx, filterFrom(opts))
+	if err != nil {
+		exitErr("list rows", err)
+	}
+	if opts.Output == config.OutputJSON {
+		emitJSON(rows)
+		return
+	}
+	if len(rows) == 0 {
+		fmt.Println("no matching outbox rows")
+		return
+	}
+	fmt.Println("event_id\ttopic\tstatus\tattempts\tcreated_at\tlast_error")
+	for _, row := range rows {
+		fmt.Printf("%s\t%s\t%s\t%d\t%s\t%s\n", row.EventID, row.Topic, row.Status, row.Attempts, formatTime(row.CreatedAt), row.LastError)
+	}
+}
+
+func runShow(ctx context.Context, repo *store.Store, opts config.Options) {
+	row, err := repo.Show(ctx, opts.EventID)
+	if errors.Is(err, store.ErrNotFound) {
+		fmt.Fprintf(os.Stderr, "outbox row %s not found\n", opts.EventID)
+		os.Exit(1)
+	}
+	if err != nil {
+		exitErr("show row", err)
+	}
+	if opts.Output == config.OutputJSON {
+		emitJSON(row)
+		return
+	}
+	fmt.Printf("event_id:      %s\n", row.EventID)
+	fmt.Printf("topic:         %s\n", row.Topic)
+	fmt.Printf("status:        %s\n", row.Status)
+	fmt.Printf("attempts:      %d\n", row.Attempts)
+	fmt.Printf("partition_key: %s\n", row.PartitionKey)
+	fmt.Printf("created_at:    %s\n", formatTime(row.CreatedAt))
+	fmt.Printf("available_at:  %s\n", formatTime(row.AvailableAt))
+	if row.PublishedAt != nil {
+		fmt.Printf("published_at:  %s\n", formatTime(*row.PublishedAt))
+	}
+	fmt.Printf("last_error:    %s\n", row.LastError)
+	fmt.Println("headers:")
+	keys := make([]string, 0, len(row.Headers))
+	for key := range row.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("  %s: %s\n", key, row.Headers[key])
+	}
+	var payload bytes.Buffer
+	if err := json.Indent(&payload, row.Payload, "  ", "  "); err != nil {
+		payload.Reset()
+		payload.Write(row.Payload)
+	}
+	fmt.Printf("payload:\n  %s\n", payload.String())
+}
+
+func runStats(ctx context.Context, repo *store.Store, opts config.Options) {
+	counts, err := repo.Stats(ctx, filterFrom(opts))
+	if err != nil {
+		exitErr("outbox stats", err)
+	}
+	if opts.Output == config.OutputJSON {
+		emitJSON(counts)
+		return
+	}
+	if len(counts) == 0 {
+		fmt.Println("no outbox rows")
+		return
+	}
+	fmt.Println("topic\tpending\tpublished\tfailed\toldest_pending")
+	for _, topic := range histogram(counts) {
+		oldest := "-"
+		if !topic.OldestPending.IsZero() {
+			oldest = formatTime(topic.OldestPending)
+		}
+		fmt.Printf("%s\t%d\t%d\t%d\t%s\n", topic.Topic, topic.Pending, topic.Published, topic.Failed, oldest)
+	}
+}
+
+type topicHistogram struct {
+	Topic         string
+	Pending       int64
+	Published     int64
+	Failed        int64
+	OldestPending time.Time
+}
+
+func histogram(counts []store.StatusCount) []topicHistogram {
+	var result []topicHistogram
+	for _, count := range counts {
+		if len(result) == 0 || result[len(result)-1].Topic != count.Topic {
+			result = append(result, topicHistogram{Topic: count.Topic})
+		}
+		entry := &result[len(result)-1]
+		switch count.Status {
+		case "pending":
+			entry.Pending, entry.OldestPending = count.Count, count.Oldest
+		case "published":
+			entry.Published = count.Count
+		case "failed":
+			entry.Failed = count.Count
+		}
+	}
+	return result
+}
+
+func formatTime(value time.Time) string {
+	return value.UTC().Format(time.RFC3339)
+}
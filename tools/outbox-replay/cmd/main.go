@@ -2,9 +2,9 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
-	"time"
 
 	"github.com/delqhi/mikasmissions/platform/tools/outbox-replay/internal/config"
 	"github.com/delqhi/mikasmissions/platform/tools/outbox-replay/internal/store"
@@ -17,6 +17,13 @@ func main() {
 		printUsage()
 		os.Exit(2)
 	}
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+	if opts.Mode == config.ModeImport {
+		runImport(ctx, opts)
+		return
+	}
+
 	repo, err := store.New(opts.DatabaseURL)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "cannot open outbox store: %v\n", err)
@@ -24,12 +31,21 @@ func main() {
 	}
 	defer repo.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
-	defer cancel()
-
 	switch opts.Mode {
 	case config.ModeListFailed:
 		runListFailed(ctx, repo, opts)
+	case config.ModeListRetrying:
+		runListRetrying(ctx, repo, opts)
+	case config.ModeList:
+		runList(ctx, repo, opts)
+	case config.ModeShow:
+		runShow(ctx, repo, opts)
+	case config.ModeStats:
+		runStats(ctx, repo, opts)
+	case config.ModeExport:
+		runExport(ctx, repo, opts)
+	case config.ModePurgeFailed:
+		runPurgeFailed(ctx, repo, opts)
 	case config.ModeRequeueFailed:
 		runRequeueFailed(ctx, repo, opts)
 	case config.ModeRequeueEvent:
@@ -40,56 +56,19 @@ func main() {
 	}
 }
 
-func runListFailed(ctx context.Context, repo *store.Store, opts config.Options) {
-	rows, err := repo.ListFailed(ctx, opts.Topic, opts.Limit)
-	if err != nil {
-		exitErr("list failed rows", err)
-	}
-	if len(rows) == 0 {
-		fmt.Println("no failed outbox rows")
-		return
-	}
-	fmt.Println("event_id\ttopic\tattempts\tavailable_at\tlast_error")
-	for _, row := range rows {
-		fmt.Printf(
-			"%s\t%s\t%d\t%s\t%s\n",
-			row.EventID,
-			row.Topic,
-			row.Attempts,
-			row.AvailableAt.UTC().Format(time.RFC3339),
-			row.LastError,
-		)
-	}
-}
-
-func runRequeueFailed(ctx context.Context, repo *store.Store, opts config.Options) {
-	rows, err := repo.RequeueFailed(ctx, opts.Topic, opts.Limit, opts.DryRun, opts.ResetAttempts)
-	if err != nil {
-		exitErr("requeue failed rows", err)
-	}
-	printReplayResult(rows, opts.DryRun)
-}
-
-func runRequeueEvent(ctx context.Context, repo *store.Store, opts config.Options) {
-	rows, err := repo.RequeueEvent(ctx, opts.EventID, opts.DryRun, opts.ResetAttempts)
-	if err != nil {
-		exitErr("requeue event", err)
+func filterFrom(opts config.Options) store.Filter {
+	return store.Filter{
+		Status:        opts.Status,
+		Topic:         opts.Topic,
+		OlderThan:     opts.OlderThan,
+		ErrorContains: opts.ErrorContains,
+		Limit:         opts.Limit,
 	}
-	printReplayResult(rows, opts.DryRun)
 }
 
-func printReplayResult(eventIDs []string, dryRun bool) {
-	modeLabel := "requeued"
-	if dryRun {
-		modeLabel = "candidates"
-	}
-	if len(eventIDs) == 0 {
-		fmt.Printf("no %s found\n", modeLabel)
-		return
-	}
-	fmt.Printf("%s (%d):\n", modeLabel, len(eventIDs))
-	for _, eventID := range eventIDs {
-		fmt.Printf("- %s\n", eventID)
+func emitJSON(value any) {
+	if err := json.NewEncoder(os.Stdout).Encode(value); err != nil {
+		exitErr("encode json output", err)
 	}
 }
 
@@ -103,11 +82,26 @@ func printUsage() {
 	fmt.Println("")
 	fmt.Println("Modes:")
 	fmt.Println("  list-failed      List failed outbox rows")
+	fmt.Println("  list-retrying    List pending rows in backoff with their retry schedule")
+	fmt.Println("  list             List rows filtered by -status, -topic, -older-than, -error-contains")
+	fmt.Println("  show             Show one row by event-id with its decoded payload and headers")
+	fmt.Println("  stats            Per-topic status counts")
+	fmt.Println("  export           Write filtered rows as NDJSON to -file")
+	fmt.Println("  import           Publish rows from an NDJSON -file to the bus (supports dry-run)")
+	fmt.Println("  purge-failed     Delete failed rows after confirmation (supports dry-run)")
 	fmt.Println("  requeue-failed   Requeue failed rows (supports dry-run)")
 	fmt.Println("  requeue-event    Requeue one failed row by event-id")
 	fmt.Println("")
+	fmt.Println("Add -output=json for machine-readable output.")
+	fmt.Println("")
 	fmt.Println("Examples:")
 	fmt.Println("  go run ./tools/outbox-replay/cmd -mode=list-failed -limit=20")
+	fmt.Println("  go run ./tools/outbox-replay/cmd -mode=list-retrying -retry-policies=outbox-retry.json")
+	fmt.Println("  go run ./tools/outbox-replay/cmd -mode=list -status=failed -older-than=24h -error-contains=timeout -output=json")
+	fmt.Println("  go run ./tools/outbox-replay/cmd -mode=show -event-id=evt-123")
+	fmt.Println("  go run ./tools/outbox-replay/cmd -mode=export -status=failed -file=failed.ndjson")
+	fmt.Println("  go run ./tools/outbox-replay/cmd -mode=import -file=failed.ndjson -dry-run=false")
+	fmt.Println("  go run ./tools/outbox-replay/cmd -mode=purge-failed -older-than=720h -dry-run=false")
 	fmt.Println("  go run ./tools/outbox-replay/cmd -mode=requeue-failed -topic=episode.published.v1 -dry-run=true")
 	fmt.Println("  go run ./tools/outbox-replay/cmd -mode=requeue-event -event-id=evt-123 -dry-run=false")
 }
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-replay/internal/config"
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-replay/internal/ndjson"
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-replay/internal/store"
+	"github.com/nats-io/nats.go"
+)
+
+type actionResult struct {
+	Mode     config.Mode `json:"mode"`
+	DryRun   bool        `json:"dry_run"`
+	EventIDs []string    `json:"event_ids"`
+}
+
+func runRequeueFailed(ctx context.Context, repo *store.Store, opts config.Options) {
+	rows, err := repo.RequeueFailed(ctx, filterFrom(opts), opts.DryRun, opts.ResetAttempts)
+	if err != nil {
+		exitErr("requeue failed rows", err)
+	}
+	printActionResult(opts, rows, "requeued")
+}
+
+func runRequeueEvent(ctx context.Context, repo *store.Store, opts config.Options) {
+	rows, err := repo.RequeueEvent(ctx, opts.EventID, opts.DryRun, opts.ResetAttempts)
+	if err != nil {
+		exitErr("requeue event", err)
+	}
+	printActionResult(opts, rows, "requeued")
+}
+
+func runPurgeFailed(ctx context.Context, repo *store.Store, opts config.Options) {
+	candidates, err := repo.FailedEventIDs(ctx, filterFrom(opts))
+	if err != nil {
+		exitErr("select purge candidates", err)
+	}
+	if opts.DryRun || len(candidates) == 0 {
+		printActionResult(opts, candidates, "purged")
+		return
+	}
+	if !opts.Yes && !confirm(os.Stdin, fmt.Sprintf("permanently delete %d failed outbox rows? type 'purge' to continue: ", len(candidates))) {
+		fmt.Fprintln(os.Stderr, "purge aborted")
+		os.Exit(1)
+	}
+	purged, err := repo.PurgeFailed(ctx, candidates)
+	if err != nil {
+		exitErr("purge failed rows", err)
+	}
+	printActionResult(opts, purged, "purged")
+}
+
+func confirm(in io.Reader, prompt string) bool {
+	fmt.Fprint(os.Stderr, prompt)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	return strings.TrimSpace(answer) == "purge"
+}
+
+func runExport(ctx context.Context, repo *store.Store, opts config.Options) {
+	out := os.Stdout
+	if opts.File != "-" {
+		file, err := os.Create(opts.File)
+		if err != nil {
+			exitErr("create export file", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	writer := ndjson.NewWriter(out)
+	count, err := repo.Export(ctx, filterFrom(opts), writer.Write)
+	if err != nil {
+		exitErr("export rows", err)
+	}
+	if opts.File != "-" {
+		if err := out.Sync(); err != nil {
+			exitErr("sync export file", err)
+		}
+		fmt.Fprintf(os.Stderr, "exported %d rows to %s\n", count, opts.File)
+	}
+}
+
+func runImport(ctx context.Context, opts config.Options) {
+	in := os.Stdin
+	if opts.File != "-" {
+		file, err := os.Open(opts.File)
+		if err != nil {
+			exitErr("open import file", err)
+		}
+		defer file.Close()
+		in = file
+	}
+	var js nats.JetStreamContext
+	if !opts.DryRun {
+		conn, err := nats.Connect(opts.NATSURL, nats.Name("mikasmissions-outbox-replay"))
+		if err != nil {
+			exitErr("connect nats", err)
+		}
+		defer conn.Close()
+		if js, err = conn.JetStream(); err != nil {
+			exitErr("jetstream context", err)
+		}
+	}
+	replayed := []string{}
+	_, err := ndjson.Read(in, func(_ int, row store.OutboxRow) error {
+		if opts.Topic != "" && row.Topic != opts.Topic {
+			return nil
+		}
+		if js != nil {
+			if _, err := js.PublishMsg(queue.NewNATSMsg(ctx, row.Event())); err != nil {
+				return fmt.Errorf("publish %s: %w", row.EventID, err)
+			}
+		}
+		replayed = append(replayed, row.EventID)
+		return nil
+	})
+	if err != nil {
+		printActionResult(opts, replayed, "published")
+		exitErr("import rows", err)
+	}
+	printActionResult(opts, replayed, "published")
+}
+
+func printActionResult(opts config.Options, eventIDs []string, appliedLabel string) {
+	if opts.Output == config.OutputJSON {
+		emitJSON(actionResult{Mode: opts.Mode, DryRun: opts.DryRun, EventIDs: eventIDs})
+		return
+	}
+	modeLabel := appliedLabel
+	if opts.DryRun {
+		modeLabel = "candidates"
+	}
+	if len(eventIDs) == 0 {
+		fmt.Printf("no %s found\n", modeLabel)
+		return
+	}
+	fmt.Printf("%s (%d):\n", modeLabel, len(eventIDs))
+	for _, eventID := range eventIDs {
+		fmt.Printf("- %s\n", eventID)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-replay/internal/store"
+)
+
+type retrySchedule struct {
+	Attempts    string `json:"attempts"`
+	ThenBackoff string `json:"then_backoff"`
+	GivesUpAt   string `json:"gives_up_at"`
+}
+
+func loadRetryPolicies(path string) (queue.OutboxRetryPolicies, error) {
+	if path == "" {
+		return queue.DefaultOutboxRetryPolicies(), nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return queue.OutboxRetryPolicies{}, fmt.Errorf("read retry policies: %w", err)
+	}
+	return queue.ParseOutboxRetryPolicies(raw)
+}
+
+func retryScheduleFor(policy queue.OutboxRetryPolicy, row store.OutboxRow) retrySchedule {
+	schedule := retrySchedule{
+		Attempts:    fmt.Sprintf("%d/%d", row.Attempts, policy.MaxAttempts),
+		ThenBackoff: "dead-letter",
+		GivesUpAt:   "-",
+	}
+	expires, bounded := policy.Expires(row.CreatedAt)
+	if bounded {
+		schedule.GivesUpAt = expires.UTC().Format(time.RFC3339)
+	}
+	if row.Attempts+1 >= policy.MaxAttempts || (bounded && !row.AvailableAt.Before(expires)) {
+		return schedule
+	}
+	low, high := policy.BackoffWindow(row.Attempts + 1)
+	schedule.ThenBackoff = low.String()
+	if high != low {
+		schedule.ThenBackoff += "-" + high.String()
+	}
+	return schedule
+}
@@ -12,33 +12,65 @@ type Mode string
 
 const (
 	ModeListFailed    Mode = "list-failed"
+	ModeListRetrying  Mode = "list-retrying"
+	ModeList          Mode = "list"
+	ModeShow          Mode = "show"
+	ModeStats         Mode = "stats"
+	ModeExport        Mode = "export"
+	ModeImport        Mode = "import"
+	ModePurgeFailed   Mode = "purge-failed"
 	ModeRequeueFailed Mode = "requeue-failed"
 	ModeRequeueEvent  Mode = "requeue-event"
 )
 
+type Output string
+
+const (
+	OutputText Output = "text"
+	OutputJSON Output = "json"
+)
+
+const defaultListLimit = 25
+
 type Options struct {
 	DatabaseURL   string
+	NATSURL       string
+	RetryPolicies string
 	Mode          Mode
 	Limit         int
 	Topic         string
+	Status        string
+	OlderThan     time.Duration
+	ErrorContains string
 	EventID       string
+	File          string
+	Output        Output
 	DryRun        bool
 	ResetAttempts bool
+	Yes           bool
 	Timeout       time.Duration
 }
 
 func Parse(args []string, getenv func(string) string) (Options, error) {
 	var opts Options
-	var modeRaw string
+	var modeRaw, outputRaw string
 	fs := flag.NewFlagSet("outbox-replay", flag.ContinueOnError)
 	fs.SetOutput(io.Discard)
 	fs.StringVar(&opts.DatabaseURL, "database-url", "", "Postgres database URL (falls back to DATABASE_URL)")
-	fs.StringVar(&modeRaw, "mode", string(ModeListFailed), "Mode: list-failed|requeue-failed|requeue-event")
-	fs.IntVar(&opts.Limit, "limit", 25, "Max rows for list-failed/requeue-failed")
-	fs.StringVar(&opts.Topic, "topic", "", "Optional topic filter for list-failed/requeue-failed")
-	fs.StringVar(&opts.EventID, "event-id", "", "Event ID for requeue-event mode")
-	fs.BoolVar(&opts.DryRun, "dry-run", true, "Preview-only for requeue modes")
+	fs.StringVar(&opts.NATSURL, "nats-url", "", "NATS URL for import (falls back to NATS_URL)")
+	fs.StringVar(&opts.RetryPolicies, "retry-policies", "", "Outbox retry policy JSON file (falls back to OUTBOX_RETRY_POLICIES_FILE)")
+	fs.StringVar(&modeRaw, "mode", string(ModeListFailed), "Mode: list-failed|list-retrying|list|show|stats|export|import|purge-failed|requeue-failed|requeue-event")
+	fs.IntVar(&opts.Limit, "limit", 0, "Max rows (default 25 for list and requeue modes, unlimited for export)")
+	fs.StringVar(&opts.Topic, "topic", "", "Optional topic filter")
+	fs.StringVar(&opts.Status, "status", "", "Optional status filter for list/stats/export: pending|published|failed")
+	fs.DurationVar(&opts.OlderThan, "older-than", 0, "Only rows created longer ago than this duration")
+	fs.StringVar(&opts.ErrorContains, "error-contains", "", "Only rows whose last error contains this text (case-insensitive)")
+	fs.StringVar(&opts.EventID, "event-id", "", "Event ID for show and requeue-event modes")
+	fs.StringVar(&opts.File, "file", "-", "NDJSON file for export/import (- for stdout/stdin)")
+	fs.StringVar(&outputRaw, "output", string(OutputText), "Output format: text|json")
+	fs.BoolVar(&opts.DryRun, "dry-run", true, "Preview-only for requeue, purge and import modes")
 	fs.BoolVar(&opts.ResetAttempts, "reset-attempts", true, "Reset attempts to 0 when requeueing")
+	fs.BoolVar(&opts.Yes, "yes", false, "Skip the interactive confirmation of purge-failed")
 	fs.DurationVar(&opts.Timeout, "timeout", 15*time.Second, "Overall command timeout")
 	if err := fs.Parse(args); err != nil {
 		return Options{}, err
@@ -46,11 +78,21 @@ func Parse(args []string, getenv func(string) string) (Options, error) {
 	if opts.DatabaseURL == "" {
 		opts.DatabaseURL = getenv("DATABASE_URL")
 	}
+	if opts.NATSURL == "" {
+		opts.NATSURL = getenv("NATS_URL")
+	}
+	if opts.RetryPolicies == "" {
+		opts.RetryPolicies = getenv("OUTBOX_RETRY_POLICIES_FILE")
+	}
 	mode, err := parseMode(modeRaw)
 	if err != nil {
 		return Options{}, err
 	}
 	opts.Mode = mode
+	opts.Output = Output(outputRaw)
+	if opts.Limit == 0 && opts.Mode != ModeExport {
+		opts.Limit = defaultListLimit
+	}
 	if err := opts.validate(); err != nil {
 		return Options{}, err
 	}
@@ -59,7 +101,8 @@ func Parse(args []string, getenv func(string) string) (Options, error) {
 
 func parseMode(raw string) (Mode, error) {
 	switch Mode(raw) {
-	case ModeListFailed, ModeRequeueFailed, ModeRequeueEvent:
+	case ModeListFailed, ModeListRetrying, ModeList, ModeShow, ModeStats, ModeExport, ModeImport,
+		ModePurgeFailed, ModeRequeueFailed, ModeRequeueEvent:
 		return Mode(raw), nil
 	default:
 		return "", fmt.Errorf("unsupported mode %q", raw)
@@ -67,17 +110,37 @@ func parseMode(raw string) (Mode, error) {
 }
 
 func (o Options) validate() error {
-	if o.DatabaseURL == "" {
+	if o.DatabaseURL == "" && o.Mode != ModeImport {
 		return errors.New("database URL is required (flag -database-url or env DATABASE_URL)")
 	}
-	if o.Limit <= 0 {
+	if o.Limit < 0 || (o.Limit == 0 && o.Mode != ModeExport) {
 		return errors.New("limit must be > 0")
 	}
 	if o.Timeout <= 0 {
 		return errors.New("timeout must be > 0")
 	}
-	if o.Mode == ModeRequeueEvent && o.EventID == "" {
-		return errors.New("event-id is required in requeue-event mode")
+	if o.OlderThan < 0 {
+		return errors.New("older-than must be >= 0")
+	}
+	switch o.Status {
+	case "", "pending", "published", "failed":
+	default:
+		return fmt.Errorf("unsupported status %q", o.Status)
+	}
+	if o.Output != OutputText && o.Output != OutputJSON {
+		return fmt.Errorf("unsupported output %q", o.Output)
+	}
+	if (o.Mode == ModeRequeueEvent || o.Mode == ModeShow) && o.EventID == "" {
+		return fmt.Errorf("event-id is required in %s mode", o.Mode)
+	}
+	if o.Mode == ModeImport && o.File == "" {
+		return errors.New("file is required in import mode")
+	}
+	if o.Mode == ModeImport && !o.DryRun && o.NATSURL == "" {
+		return errors.New("NATS URL is required to import (flag -nats-url or env NATS_URL)")
+	}
+	if o.Mode == ModePurgeFailed && !o.DryRun && !o.Yes && o.Output == OutputJSON {
+		return errors.New("purge-failed with -output=json requires -yes")
 	}
 	return nil
 }
@@ -59,3 +59,67 @@ func TestParseRejectsMissingDatabaseURL(t *testing.T) {
 		t.Fatalf("expected database URL required error, got: %v", err)
 	}
 }
+
+func TestParseListRetryingUsesEnvRetryPolicies(t *testing.T) {
+	opts, err := Parse([]string{"-database-url", "postgres://example", "-mode", "list-retrying"}, func(key string) string {
+		if key == "OUTBOX_RETRY_POLICIES_FILE" {
+			return "/etc/outbox/retry.json"
+		}
+		return ""
+	})
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if opts.Mode != ModeListRetrying || opts.RetryPolicies != "/etc/outbox/retry.json" {
+		t.Fatalf("unexpected options: %+v", opts)
+	}
+}
+
+func TestParseImportDoesNotNeedDatabaseAndExportIsUnlimited(t *testing.T) {
+	noEnv := func(string) string { return "" }
+	opts, err := Parse([]string{"-mode", "import", "-file", "rows.ndjson"}, noEnv)
+	if err != nil {
+		t.Fatalf("parse import: %v", err)
+	}
+	if opts.Mode != ModeImport || opts.File != "rows.ndjson" || !opts.DryRun {
+		t.Fatalf("unexpected import options: %+v", opts)
+	}
+	opts, err = Parse([]string{"-database-url", "postgres://example", "-mode", "export", "-status", "failed"}, noEnv)
+	if err != nil {
+		t.Fatalf("parse export: %v", err)
+	}
+	if opts.Limit != 0 || opts.Status != "failed" {
+		t.Fatalf("expected unlimited failed export, got %+v", opts)
+	}
+}
+
+func TestParseRejectsInvalidFiltersAndUnconfirmedJSONPurge(t *testing.T) {
+	noEnv := func(string) string { return "" }
+	cases := map[string][]string{
+		"unsupported status":   {"-database-url", "x", "-mode", "list", "-status", "done"},
+		"unsupported output":   {"-database-url", "x", "-output", "yaml"},
+		"event-id is required": {"-database-url", "x", "-mode", "show"},
+		"requires -yes":        {"-database-url", "x", "-mode", "purge-failed", "-dry-run=false", "-output", "json"},
+	}
+	for want, args := range cases {
+		if _, err := Parse(args, noEnv); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error for %v, got %v", want, args, err)
+		}
+	}
+}
+
+func TestParseImportRequiresNATSURLOutsideDryRun(t *testing.T) {
+	noEnv := func(string) string { return "" }
+	if _, err := Parse([]string{"-mode", "import", "-file", "rows.ndjson", "-dry-run=false"}, noEnv); err == nil {
+		t.Fatalf("expected import without NATS URL to be rejected")
+	}
+	opts, err := Parse([]string{"-mode", "import", "-file", "rows.ndjson", "-dry-run=false"}, func(key string) string {
+		if key == "NATS_URL" {
+			return "nats://nats:4222"
+		}
+		return ""
+	})
+	if err != nil || opts.NATSURL != "nats://nats:4222" {
+		t.Fatalf("expected NATS_URL fallback, got %+v err=%v", opts, err)
+	}
+}
@@ -0,0 +1,55 @@
+package ndjson
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-replay/internal/store"
+)
+
+const maxLineBytes = 4 << 20
+
+type Writer struct {
+	encoder *json.Encoder
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{encoder: json.NewEncoder(w)}
+}
+
+func (w *Writer) Write(row store.OutboxRow) error {
+	if err := w.encoder.Encode(row); err != nil {
+		return fmt.Errorf("encode outbox row %s: %w", row.EventID, err)
+	}
+	return nil
+}
+
+func Read(r io.Reader, visit func(line int, row store.OutboxRow) error) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineBytes)
+	line, read := 0, 0
+	for scanner.Scan() {
+		line++
+		raw := scanner.Bytes()
+		if len(raw) == 0 {
+			continue
+		}
+		var row store.OutboxRow
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return read, fmt.Errorf("line %d: decode outbox row: %w", line, err)
+		}
+		if row.EventID == "" || row.Topic == "" || len(row.Payload) == 0 {
+			return read, fmt.Errorf("line %d: event_id, topic and payload are required", line)
+		}
+		if err := visit(line, row); err != nil {
+			return read, fmt.Errorf("line %d: %w", line, err)
+		}
+		read++
+	}
+	if err := scanner.Err(); err != nil {
+		return read, fmt.Errorf("read ndjson: %w", err)
+	}
+	return read, nil
+}
@@ -0,0 +1,44 @@
+package ndjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-replay/internal/store"
+)
+
+func TestWriteThenReadRoundTripsRows(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewWriter(&buf)
+	rows := []store.OutboxRow{
+		{ID: 1, EventID: "evt-1", Topic: "media.uploaded.v1", Status: "failed", Payload: json.RawMessage(`{"asset_id":"a-1"}`), Headers: queue.Headers{queue.HeaderTraceID: "trace-1"}},
+		{ID: 2, EventID: "evt-2", Topic: "episode.published.v1", Status: "published", Payload: json.RawMessage(`{}`)},
+	}
+	for _, row := range rows {
+		if err := writer.Write(row); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	var got []queue.Event
+	count, err := Read(&buf, func(_ int, row store.OutboxRow) error {
+		got = append(got, row.Event())
+		return nil
+	})
+	if err != nil || count != 2 {
+		t.Fatalf("expected 2 rows, got %d err=%v", count, err)
+	}
+	if got[0].ID != "evt-1" || got[0].Headers.TraceID() != "trace-1" || string(got[0].Payload) != `{"asset_id":"a-1"}` {
+		t.Fatalf("unexpected first event: %+v", got[0])
+	}
+}
+
+func TestReadReportsLineOfInvalidRow(t *testing.T) {
+	input := `{"event_id":"evt-1","topic":"media.uploaded.v1","payload":{}}` + "\n\n" + `{"event_id":"evt-2","payload":{}}` + "\n"
+	count, err := Read(strings.NewReader(input), func(int, store.OutboxRow) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "line 3") || count != 1 {
+		t.Fatalf("expected line 3 error after one row, got %d err=%v", count, err)
+	}
+}
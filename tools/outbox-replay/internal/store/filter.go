@@ -0,0 +1,48 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Filter struct {
+	Status        string
+	Topic         string
+	OlderThan     time.Duration
+	ErrorContains string
+	Retrying      bool
+	AfterID       int64
+	Limit         int
+}
+
+func (f Filter) clause(now time.Time) (string, []any) {
+	conditions := make([]string, 0, 6)
+	args := make([]any, 0, 6)
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if f.Status != "" {
+		add("status = $%d", f.Status)
+	}
+	if f.Retrying {
+		conditions = append(conditions, "attempts > 0")
+	}
+	if f.Topic != "" {
+		add("topic = $%d", f.Topic)
+	}
+	if f.OlderThan > 0 {
+		add("created_at < $%d", now.Add(-f.OlderThan))
+	}
+	if f.ErrorContains != "" {
+		add("strpos(lower(coalesce(last_error, '')), lower($%d)) > 0", f.ErrorContains)
+	}
+	if f.AfterID > 0 {
+		add("id > $%d", f.AfterID)
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " where " + strings.Join(conditions, " and "), args
+}
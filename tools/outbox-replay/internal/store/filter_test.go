@@ -0,0 +1,35 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFilterClauseNumbersArgumentsInOrder(t *testing.T) {
+	now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	where, args := Filter{
+		Status:        "failed",
+		Topic:         "media.uploaded.v1",
+		OlderThan:     24 * time.Hour,
+		ErrorContains: "Timeout",
+		AfterID:       42,
+	}.clause(now)
+	want := " where status = $1 and topic = $2 and created_at < $3 and strpos(lower(coalesce(last_error, '')), lower($4)) > 0 and id > $5"
+	if where != want {
+		t.Fatalf("unexpected clause:\n got %s\nwant %s", where, want)
+	}
+	if !reflect.DeepEqual(args, []any{"failed", "media.uploaded.v1", now.Add(-24 * time.Hour), "Timeout", int64(42)}) {
+		t.Fatalf("unexpected args: %v", args)
+	}
+}
+
+func TestFilterClauseEmptyAndRetrying(t *testing.T) {
+	if where, args := (Filter{}).clause(time.Now()); where != "" || len(args) != 0 {
+		t.Fatalf("expected no clause, got %q %v", where, args)
+	}
+	where, _ := Filter{Status: "pending", Retrying: true}.clause(time.Now())
+	if where != " where status = $1 and attempts > 0" {
+		t.Fatalf("unexpected retrying clause: %s", where)
+	}
+}
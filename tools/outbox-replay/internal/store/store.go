@@ -3,22 +3,35 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
-type FailedEvent struct {
-	EventID     string
-	Topic       string
-	Attempts    int
-	LastError   string
-	AvailableAt time.Time
-	CreatedAt   time.Time
+var ErrNotFound = errors.New("outbox row not found")
+
+type OutboxRow struct {
+	ID           int64           `json:"id"`
+	EventID      string          `json:"event_id"`
+	Topic        string          `json:"topic"`
+	Status       string          `json:"status"`
+	Attempts     int             `json:"attempts"`
+	LastError    string          `json:"last_error,omitempty"`
+	PartitionKey string          `json:"partition_key,omitempty"`
+	Payload      json.RawMessage `json:"payload"`
+	Headers      queue.Headers   `json:"headers,omitempty"`
+	AvailableAt  time.Time       `json:"available_at"`
+	CreatedAt    time.Time       `json:"created_at"`
+	PublishedAt  *time.Time      `json:"published_at,omitempty"`
 }
 
+const rowColumns = `id, event_id, topic, status, attempts, coalesce(last_error, ''), coalesce(partition_key, ''),
+	payload, headers, available_at, created_at, published_at`
+
 type Store struct {
 	db *sql.DB
 }
@@ -39,144 +52,105 @@ func (s *Store) Close() error {
 	return s.db.Close()
 }
 
-func (s *Store) ListFailed(ctx context.Context, topic string, limit int) ([]FailedEvent, error) {
-	query := `select event_id, topic, attempts, coalesce(last_error, ''), available_at, created_at
-		from events.outbox
-		where status = 'failed'`
-	args := make([]any, 0, 2)
-	if topic != "" {
-		query += " and topic = $1"
-		args = append(args, topic)
-	}
-	query += fmt.Sprintf(" order by id desc limit $%d", len(args)+1)
-	args = append(args, limit)
-	rows, err := s.db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("query failed outbox rows: %w", err)
-	}
-	defer rows.Close()
-	result := make([]FailedEvent, 0, limit)
-	for rows.Next() {
-		var row FailedEvent
-		if err := rows.Scan(&row.EventID, &row.Topic, &row.Attempts, &row.LastError, &row.AvailableAt, &row.CreatedAt); err != nil {
-			return nil, fmt.Errorf("scan failed outbox row: %w", err)
-		}
-		result = append(result, row)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate failed outbox rows: %w", err)
-	}
-	return result, nil
+func (r OutboxRow) Event() queue.Event {
+	return queue.Event{ID: r.EventID, Topic: r.Topic, Payload: r.Payload, Headers: r.Headers}
 }
 
-func (s *Store) RequeueFailed(
-	ctx context.Context,
-	topic string,
-	limit int,
-	dryRun bool,
-	resetAttempts bool,
-) ([]string, error) {
-	ids, err := s.selectFailedEventIDs(ctx, topic, limit)
-	if err != nil {
-		return nil, err
+func (s *Store) ListFailed(ctx context.Context, topic string, limit int) ([]OutboxRow, error) {
+	return s.list(ctx, "failed", Filter{Status: "failed", Topic: topic, Limit: limit}, "id desc")
+}
+
+func (s *Store) ListRetrying(ctx context.Context, topic string, limit int) ([]OutboxRow, error) {
+	return s.list(ctx, "retrying", Filter{Status: "pending", Retrying: true, Topic: topic, Limit: limit}, "available_at asc, id asc")
+}
+
+func (s *Store) List(ctx context.Context, filter Filter) ([]OutboxRow, error) {
+	return s.list(ctx, "filtered", filter, "id desc")
+}
+
+func (s *Store) Show(ctx context.Context, eventID string) (OutboxRow, error) {
+	row, err := scanRow(s.db.QueryRowContext(ctx, `select `+rowColumns+` from events.outbox where event_id = $1`, eventID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return OutboxRow{}, ErrNotFound
 	}
-	if dryRun || len(ids) == 0 {
-		return ids, nil
+	if err != nil {
+		return OutboxRow{}, fmt.Errorf("query outbox row %s: %w", eventID, err)
 	}
-	return s.requeueEventIDs(ctx, ids, resetAttempts)
+	return row, nil
 }
 
-func (s *Store) RequeueEvent(ctx context.Context, eventID string, dryRun bool, resetAttempts bool) ([]string, error) {
-	if strings.TrimSpace(eventID) == "" {
-		return nil, fmt.Errorf("eventID is required")
-	}
-	if dryRun {
-		exists, err := s.hasFailedRow(ctx, eventID)
+func (s *Store) Export(ctx context.Context, filter Filter, emit func(OutboxRow) error) (int, error) {
+	const pageSize = 500
+	exported := 0
+	for {
+		page := filter
+		page.Limit = pageSize
+		if filter.Limit > 0 {
+			page.Limit = min(pageSize, filter.Limit-exported)
+		}
+		rows, err := s.list(ctx, "export", page, "id asc")
 		if err != nil {
-			return nil, err
+			return exported, err
+		}
+		for _, row := range rows {
+			if err := emit(row); err != nil {
+				return exported, err
+			}
+			exported++
+			filter.AfterID = row.ID
 		}
-		if !exists {
-			return []string{}, nil
+		if len(rows) < page.Limit || (filter.Limit > 0 && exported >= filter.Limit) {
+			return exported, nil
 		}
-		return []string{eventID}, nil
 	}
-	return s.requeueEventIDs(ctx, []string{eventID}, resetAttempts)
 }
 
-func (s *Store) selectFailedEventIDs(ctx context.Context, topic string, limit int) ([]string, error) {
-	query := `select event_id from events.outbox where status = 'failed'`
-	args := make([]any, 0, 2)
-	if topic != "" {
-		query += " and topic = $1"
-		args = append(args, topic)
-	}
-	query += fmt.Sprintf(" order by id asc limit $%d", len(args)+1)
-	args = append(args, limit)
+func (s *Store) list(ctx context.Context, label string, filter Filter, order string) ([]OutboxRow, error) {
+	where, args := filter.clause(time.Now().UTC())
+	query := `select ` + rowColumns + ` from events.outbox` + where
+	query += fmt.Sprintf(" order by %s limit $%d", order, len(args)+1)
+	args = append(args, filter.Limit)
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("query failed requeue candidates: %w", err)
+		return nil, fmt.Errorf("query %s outbox rows: %w", label, err)
 	}
 	defer rows.Close()
-	ids := make([]string, 0, limit)
+	result := make([]OutboxRow, 0, filter.Limit)
 	for rows.Next() {
-		var id string
-		if err := rows.Scan(&id); err != nil {
-			return nil, fmt.Errorf("scan failed requeue candidate: %w", err)
+		row, err := scanRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan %s outbox row: %w", label, err)
 		}
-		ids = append(ids, id)
+		result = append(result, row)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate failed requeue candidates: %w", err)
+		return nil, fmt.Errorf("iterate %s outbox rows: %w", label, err)
 	}
-	return ids, nil
+	return result, nil
 }
 
-func (s *Store) requeueEventIDs(ctx context.Context, eventIDs []string, resetAttempts bool) ([]string, error) {
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, fmt.Errorf("begin requeue tx: %w", err)
-	}
-	defer tx.Rollback()
-	requeued := make([]string, 0, len(eventIDs))
-	for _, eventID := range eventIDs {
-		result, err := tx.ExecContext(
-			ctx,
-			`update events.outbox
-			 set status = 'pending',
-			     available_at = now(),
-			     last_error = null,
-			     attempts = case when $2 then 0 else attempts end
-			 where event_id = $1 and status = 'failed'`,
-			eventID,
-			resetAttempts,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("requeue outbox row %s: %w", eventID, err)
-		}
-		affected, err := result.RowsAffected()
-		if err != nil {
-			return nil, fmt.Errorf("read requeue rows affected for %s: %w", eventID, err)
-		}
-		if affected > 0 {
-			requeued = append(requeued, eventID)
-		}
-	}
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("commit requeue tx: %w", err)
-	}
-	return requeued, nil
+type rowScanner interface {
+	Scan(dest ...any) error
 }
 
-func (s *Store) hasFailedRow(ctx context.Context, eventID string) (bool, error) {
-	var exists bool
-	if err := s.db.QueryRowContext(
-		ctx,
-		`select exists(
-			select 1 from events.outbox where event_id = $1 and status = 'failed'
-		)`,
-		eventID,
-	).Scan(&exists); err != nil {
-		return false, fmt.Errorf("query failed row existence: %w", err)
+func scanRow(scanner rowScanner) (OutboxRow, error) {
+	var row OutboxRow
+	var payload, headers []byte
+	var publishedAt sql.NullTime
+	if err := scanner.Scan(
+		&row.ID, &row.EventID, &row.Topic, &row.Status, &row.Attempts, &row.LastError, &row.PartitionKey,
+		&payload, &headers, &row.AvailableAt, &row.CreatedAt, &publishedAt,
+	); err != nil {
+		return OutboxRow{}, err
+	}
+	row.Payload = json.RawMessage(payload)
+	if len(headers) > 0 {
+		if err := json.Unmarshal(headers, &row.Headers); err != nil {
+			return OutboxRow{}, fmt.Errorf("decode headers: %w", err)
+		}
+	}
+	if publishedAt.Valid {
+		row.PublishedAt = &publishedAt.Time
 	}
-	return exists, nil
+	return row, nil
 }
@@ -0,0 +1,168 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type StatusCount struct {
+	Topic  string    `json:"topic"`
+	Status string    `json:"status"`
+	Count  int64     `json:"count"`
+	Oldest time.Time `json:"oldest_created_at"`
+}
+
+func (s *Store) Stats(ctx context.Context, filter Filter) ([]StatusCount, error) {
+	where, args := filter.clause(time.Now().UTC())
+	rows, err := s.db.QueryContext(
+		ctx,
+		`select topic, status, count(*), min(created_at) from events.outbox`+where+` group by topic, status order by topic, status`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query outbox stats: %w", err)
+	}
+	defer rows.Close()
+	var result []StatusCount
+	for rows.Next() {
+		var row StatusCount
+		if err := rows.Scan(&row.Topic, &row.Status, &row.Count, &row.Oldest); err != nil {
+			return nil, fmt.Errorf("scan outbox stats: %w", err)
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate outbox stats: %w", err)
+	}
+	return result, nil
+}
+
+func (s *Store) RequeueFailed(ctx context.Context, filter Filter, dryRun bool, resetAttempts bool) ([]string, error) {
+	ids, err := s.FailedEventIDs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun || len(ids) == 0 {
+		return ids, nil
+	}
+	return s.requeueEventIDs(ctx, ids, resetAttempts)
+}
+
+func (s *Store) RequeueEvent(ctx context.Context, eventID string, dryRun bool, resetAttempts bool) ([]string, error) {
+	if strings.TrimSpace(eventID) == "" {
+		return nil, fmt.Errorf("eventID is required")
+	}
+	if dryRun {
+		exists, err := s.hasFailedRow(ctx, eventID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return []string{}, nil
+		}
+		return []string{eventID}, nil
+	}
+	return s.requeueEventIDs(ctx, []string{eventID}, resetAttempts)
+}
+
+func (s *Store) PurgeFailed(ctx context.Context, eventIDs []string) ([]string, error) {
+	rows, err := s.db.QueryContext(
+		ctx,
+		`delete from events.outbox where event_id = any($1) and status = 'failed' returning event_id`,
+		eventIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("purge failed outbox rows: %w", err)
+	}
+	defer rows.Close()
+	return collectEventIDs(rows, "purged")
+}
+
+func (s *Store) FailedEventIDs(ctx context.Context, filter Filter) ([]string, error) {
+	filter.Status = "failed"
+	where, args := filter.clause(time.Now().UTC())
+	args = append(args, filter.Limit)
+	rows, err := s.db.QueryContext(
+		ctx,
+		`select event_id from events.outbox`+where+fmt.Sprintf(" order by id asc limit $%d", len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query failed outbox candidates: %w", err)
+	}
+	defer rows.Close()
+	return collectEventIDs(rows, "failed candidate")
+}
+
+type eventIDRows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+}
+
+func collectEventIDs(rows eventIDRows, label string) ([]string, error) {
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan %s event id: %w", label, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate %s event ids: %w", label, err)
+	}
+	return ids, nil
+}
+
+func (s *Store) requeueEventIDs(ctx context.Context, eventIDs []string, resetAttempts bool) ([]string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin requeue tx: %w", err)
+	}
+	defer tx.Rollback()
+	requeued := make([]string, 0, len(eventIDs))
+	for _, eventID := range eventIDs {
+		result, err := tx.ExecContext(
+			ctx,
+			`update events.outbox
+			 set status = 'pending',
+			     available_at = now(),
+			     last_error = null,
+			     attempts = case when $2 then 0 else attempts end
+			 where event_id = $1 and status = 'failed'`,
+			eventID,
+			resetAttempts,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("requeue outbox row %s: %w", eventID, err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("read requeue rows affected for %s: %w", eventID, err)
+		}
+		if affected > 0 {
+			requeued = append(requeued, eventID)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit requeue tx: %w", err)
+	}
+	return requeued, nil
+}
+
+func (s *Store) hasFailedRow(ctx context.Context, eventID string) (bool, error) {
+	var exists bool
+	if err := s.db.QueryRowContext(
+		ctx,
+		`select exists(
+			select 1 from events.outbox where event_id = $1 and status = 'failed'
+		)`,
+		eventID,
+	).Scan(&exists); err != nil {
+		return false, fmt.Errorf("query failed row existence: %w", err)
+	}
+	return exists, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-retention/internal/archive"
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-retention/internal/config"
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-retention/internal/retention"
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-retention/internal/store"
+)
+
+func main() {
+	opts, err := config.Parse(os.Args[1:], os.Getenv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid arguments: %v\n\n", err)
+		printUsage()
+		os.Exit(2)
+	}
+	repo, err := store.New(opts.DatabaseURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot open outbox store: %v\n", err)
+		os.Exit(1)
+	}
+	defer repo.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	now := time.Now().UTC()
+	var sink func([]store.ArchivedRow) error
+	var file *archive.File
+	if opts.Archive == config.ArchiveFile && !opts.DryRun && !opts.SkipOutbox {
+		file, err = archive.Create(opts.ArchiveDir, now)
+		if err != nil {
+			exitErr("create archive", err)
+		}
+		sink = file.Write
+	}
+	result, runErr := retention.Run(ctx, repo, sink, opts, now)
+	if file != nil {
+		if err := file.Close(); err != nil {
+			exitErr("close archive", err)
+		}
+	}
+	printResult(result, opts, file)
+	if runErr != nil {
+		exitErr("retention", runErr)
+	}
+}
+
+func printResult(result retention.Result, opts config.Options, file *archive.File) {
+	fmt.Printf("cutoff:             %s\n", result.Cutoff.Format(time.RFC3339))
+	if !opts.SkipOutbox {
+		fmt.Printf("outbox rows:        %d (%d batches, archive=%s)\n", result.OutboxRows, result.OutboxBatches, opts.Archive)
+	}
+	if file != nil && result.OutboxRows > 0 {
+		fmt.Printf("archive file:       %s\n", file.Path())
+	}
+	if !opts.SkipIdempotency {
+		fmt.Printf("idempotency keys:   %d (%d batches)\n", result.ExpiredKeys, result.KeyBatches)
+	}
+	if !opts.SkipBus {
+		fmt.Printf("bus messages:       %d (%d batches)\n", result.BusMessages, result.BusBatches)
+	}
+	if !result.Applied {
+		fmt.Println("dry-run: no rows archived or pruned (rerun with -dry-run=false)")
+	}
+}
+
+func exitErr(op string, err error) {
+	fmt.Fprintf(os.Stderr, "%s failed: %v\n", op, err)
+	os.Exit(1)
+}
+
+func printUsage() {
+	fmt.Println("Usage: go run ./tools/outbox-retention/cmd [flags]")
+	fmt.Println("")
+	fmt.Println("Archives published outbox rows older than -older-than, prunes expired idempotency keys and")
+	fmt.Println("deletes Postgres bus messages older than -older-than that every consumer has acked,")
+	fmt.Println("in batches of -batch-size rows, each in its own short transaction.")
+	fmt.Println("")
+	fmt.Println("Examples:")
+	fmt.Println("  go run ./tools/outbox-retention/cmd -older-than=168h")
+	fmt.Println("  go run ./tools/outbox-retention/cmd -older-than=168h -archive=file -archive-dir=/var/backups/outbox -dry-run=false")
+	fmt.Println("  go run ./tools/outbox-retention/cmd -older-than=72h -archive=table -batch-size=1000 -dry-run=false")
+}
@@ -0,0 +1,62 @@
+package archive
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-retention/internal/store"
+)
+
+type File struct {
+	path string
+	file *os.File
+	rows int
+}
+
+func Create(dir string, now time.Time) (*File, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create archive dir: %w", err)
+	}
+	path := filepath.Join(dir, "outbox-"+now.UTC().Format("20060102T150405Z")+".ndjson.gz")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("create archive file: %w", err)
+	}
+	return &File{path: path, file: file}, nil
+}
+
+func (f *File) Write(rows []store.ArchivedRow) error {
+	zw := gzip.NewWriter(f.file)
+	encoder := json.NewEncoder(zw)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return fmt.Errorf("encode archived row %s: %w", row.EventID, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("compress archive batch: %w", err)
+	}
+	if err := f.file.Sync(); err != nil {
+		return fmt.Errorf("sync archive file: %w", err)
+	}
+	f.rows += len(rows)
+	return nil
+}
+
+func (f *File) Path() string {
+	return f.path
+}
+
+func (f *File) Close() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	if f.rows == 0 {
+		return os.Remove(f.path)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-retention/internal/store"
+)
+
+func TestFileWritesOneGzipMemberPerBatch(t *testing.T) {
+	file, err := Create(t.TempDir(), time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	batches := [][]store.ArchivedRow{
+		{{ID: 1, EventID: "evt-1", Topic: "media.uploaded.v1", Payload: json.RawMessage(`{}`), Headers: json.RawMessage(`{}`)}},
+		{{ID: 2, EventID: "evt-2", Topic: "media.uploaded.v1", Payload: json.RawMessage(`{}`), Headers: json.RawMessage(`{}`)}},
+	}
+	for _, batch := range batches {
+		if err := file.Write(batch); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	raw, err := os.Open(file.Path())
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	defer raw.Close()
+	zr, err := gzip.NewReader(raw)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	var ids []string
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		var row store.ArchivedRow
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			t.Fatalf("decode line: %v", err)
+		}
+		ids = append(ids, row.EventID)
+	}
+	if len(ids) != 2 || ids[0] != "evt-1" || ids[1] != "evt-2" {
+		t.Fatalf("expected both batches in archive, got %v", ids)
+	}
+}
+
+func TestFileRemovedWhenNothingArchived(t *testing.T) {
+	file, err := Create(t.TempDir(), time.Now())
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := os.Stat(file.Path()); !os.IsNotExist(err) {
+		t.Fatalf("expected empty archive to be removed, got %v", err)
+	}
+}
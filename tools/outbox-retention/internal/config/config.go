@@ -0,0 +1,98 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type Archive string
+
+const (
+	ArchiveFile  Archive = "file"
+	ArchiveTable Archive = "table"
+	ArchiveNone  Archive = "none"
+)
+
+type Options struct {
+	DatabaseURL     string
+	OlderThan       time.Duration
+	Archive         Archive
+	ArchiveDir      string
+	BatchSize       int
+	Pause           time.Duration
+	MaxBatches      int
+	SkipOutbox      bool
+	SkipIdempotency bool
+	SkipBus         bool
+	DryRun          bool
+	Timeout         time.Duration
+}
+
+func Parse(args []string, getenv func(string) string) (Options, error) {
+	var opts Options
+	var archiveRaw string
+	fs := flag.NewFlagSet("outbox-retention", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&opts.DatabaseURL, "database-url", "", "Postgres database URL (falls back to DATABASE_URL)")
+	fs.DurationVar(&opts.OlderThan, "older-than", 7*24*time.Hour, "Archive published outbox rows older than this age")
+	fs.StringVar(&archiveRaw, "archive", string(ArchiveFile), "Archive target: file|table|none")
+	fs.StringVar(&opts.ArchiveDir, "archive-dir", ".", "Directory for gzip NDJSON archives in -archive=file")
+	fs.IntVar(&opts.BatchSize, "batch-size", 500, "Rows deleted per transaction")
+	fs.DurationVar(&opts.Pause, "pause", 100*time.Millisecond, "Pause between batches")
+	fs.IntVar(&opts.MaxBatches, "max-batches", 0, "Stop after this many batches per table (0 = until done)")
+	fs.BoolVar(&opts.SkipOutbox, "skip-outbox", false, "Do not archive published outbox rows")
+	fs.BoolVar(&opts.SkipIdempotency, "skip-idempotency", false, "Do not prune expired idempotency keys")
+	fs.BoolVar(&opts.SkipBus, "skip-bus", false, "Do not prune Postgres bus messages acked by every consumer")
+	fs.BoolVar(&opts.DryRun, "dry-run", true, "Preview-only; count rows that would be archived or pruned")
+	fs.DurationVar(&opts.Timeout, "timeout", 30*time.Minute, "Overall command timeout")
+	if err := fs.Parse(args); err != nil {
+		return Options{}, err
+	}
+	if opts.DatabaseURL == "" {
+		opts.DatabaseURL = getenv("DATABASE_URL")
+	}
+	archive, err := parseArchive(archiveRaw)
+	if err != nil {
+		return Options{}, err
+	}
+	opts.Archive = archive
+	if err := opts.validate(); err != nil {
+		return Options{}, err
+	}
+	return opts, nil
+}
+
+func parseArchive(raw string) (Archive, error) {
+	switch Archive(strings.ToLower(raw)) {
+	case ArchiveFile, ArchiveTable, ArchiveNone:
+		return Archive(strings.ToLower(raw)), nil
+	default:
+		return "", fmt.Errorf("unsupported archive %q", raw)
+	}
+}
+
+func (o Options) validate() error {
+	if o.DatabaseURL == "" {
+		return errors.New("database URL is required (flag -database-url or env DATABASE_URL)")
+	}
+	if o.OlderThan <= 0 {
+		return errors.New("older-than must be > 0")
+	}
+	if o.BatchSize <= 0 {
+		return errors.New("batch-size must be > 0")
+	}
+	if o.Pause < 0 || o.MaxBatches < 0 {
+		return errors.New("pause and max-batches must be >= 0")
+	}
+	if o.Timeout <= 0 {
+		return errors.New("timeout must be > 0")
+	}
+	if o.Archive == ArchiveFile && strings.TrimSpace(o.ArchiveDir) == "" {
+		return errors.New("archive-dir is required for -archive=file")
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDefaultsAndEnvDatabaseURL(t *testing.T) {
+	opts, err := Parse([]string{}, func(key string) string {
+		if key == "DATABASE_URL" {
+			return "postgres://example"
+		}
+		return ""
+	})
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if opts.DatabaseURL != "postgres://example" || opts.Archive != ArchiveFile {
+		t.Fatalf("unexpected options: %+v", opts)
+	}
+	if opts.OlderThan != 7*24*time.Hour || opts.BatchSize != 500 || !opts.DryRun {
+		t.Fatalf("unexpected defaults: %+v", opts)
+	}
+}
+
+func TestParseRejectsUnsupportedArchive(t *testing.T) {
+	_, err := Parse([]string{"-database-url", "postgres://example", "-archive", "s3"}, func(string) string {
+		return ""
+	})
+	if err == nil || !strings.Contains(err.Error(), "unsupported archive") {
+		t.Fatalf("expected unsupported archive error, got: %v", err)
+	}
+}
+
+func TestParseRejectsNonPositiveBatchSize(t *testing.T) {
+	_, err := Parse([]string{"-database-url", "postgres://example", "-batch-size", "0"}, func(string) string {
+		return ""
+	})
+	if err == nil || !strings.Contains(err.Error(), "batch-size") {
+		t.Fatalf("expected batch-size error, got: %v", err)
+	}
+}
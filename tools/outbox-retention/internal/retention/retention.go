@@ -0,0 +1,123 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-retention/internal/config"
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-retention/internal/store"
+)
+
+type Store interface {
+	CountPublished(ctx context.Context, cutoff time.Time) (int64, error)
+	CountExpiredKeys(ctx context.Context, now time.Time) (int64, error)
+	ArchivePublished(ctx context.Context, cutoff time.Time, limit int, sink func([]store.ArchivedRow) error) (int, error)
+	MovePublished(ctx context.Context, cutoff time.Time, limit int) (int, error)
+	PruneExpiredKeys(ctx context.Context, now time.Time, limit int) (int, error)
+	CountAckedBusMessages(ctx context.Context, cutoff time.Time) (int64, error)
+	PruneAckedBusMessages(ctx context.Context, cutoff time.Time, limit int) (int, error)
+}
+
+type Result struct {
+	Cutoff        time.Time
+	OutboxRows    int64
+	OutboxBatches int
+	ExpiredKeys   int64
+	KeyBatches    int
+	BusMessages   int64
+	BusBatches    int
+	Applied       bool
+}
+
+func Run(ctx context.Context, s Store, sink func([]store.ArchivedRow) error, opts config.Options, now time.Time) (Result, error) {
+	result := Result{Cutoff: now.Add(-opts.OlderThan), Applied: !opts.DryRun}
+	if opts.DryRun {
+		return preview(ctx, s, opts, now, result)
+	}
+	if !opts.SkipOutbox {
+		archiveBatch := func(ctx context.Context) (int, error) {
+			if opts.Archive == config.ArchiveTable {
+				return s.MovePublished(ctx, result.Cutoff, opts.BatchSize)
+			}
+			if opts.Archive == config.ArchiveNone {
+				return s.ArchivePublished(ctx, result.Cutoff, opts.BatchSize, nil)
+			}
+			return s.ArchivePublished(ctx, result.Cutoff, opts.BatchSize, sink)
+		}
+		rows, batches, err := drain(ctx, archiveBatch, opts)
+		result.OutboxRows, result.OutboxBatches = rows, batches
+		if err != nil {
+			return result, err
+		}
+	}
+	if !opts.SkipIdempotency {
+		pruneBatch := func(ctx context.Context) (int, error) {
+			return s.PruneExpiredKeys(ctx, now, opts.BatchSize)
+		}
+		rows, batches, err := drain(ctx, pruneBatch, opts)
+		result.ExpiredKeys, result.KeyBatches = rows, batches
+		if err != nil {
+			return result, err
+		}
+	}
+	if !opts.SkipBus {
+		busBatch := func(ctx context.Context) (int, error) {
+			return s.PruneAckedBusMessages(ctx, result.Cutoff, opts.BatchSize)
+		}
+		rows, batches, err := drain(ctx, busBatch, opts)
+		result.BusMessages, result.BusBatches = rows, batches
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func preview(ctx context.Context, s Store, opts config.Options, now time.Time, result Result) (Result, error) {
+	if !opts.SkipOutbox {
+		count, err := s.CountPublished(ctx, result.Cutoff)
+		if err != nil {
+			return result, err
+		}
+		result.OutboxRows = count
+	}
+	if !opts.SkipIdempotency {
+		count, err := s.CountExpiredKeys(ctx, now)
+		if err != nil {
+			return result, err
+		}
+		result.ExpiredKeys = count
+	}
+	if !opts.SkipBus {
+		count, err := s.CountAckedBusMessages(ctx, result.Cutoff)
+		if err != nil {
+			return result, err
+		}
+		result.BusMessages = count
+	}
+	return result, nil
+}
+
+func drain(ctx context.Context, batch func(context.Context) (int, error), opts config.Options) (int64, int, error) {
+	var total int64
+	batches := 0
+	for {
+		n, err := batch(ctx)
+		if err != nil {
+			return total, batches, err
+		}
+		if n == 0 {
+			return total, batches, nil
+		}
+		total += int64(n)
+		batches++
+		if n < opts.BatchSize || (opts.MaxBatches > 0 && batches >= opts.MaxBatches) {
+			return total, batches, nil
+		}
+		select {
+		case <-ctx.Done():
+			return total, batches, ctx.Err()
+		case <-time.After(opts.Pause):
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-retention/internal/config"
+	"github.com/delqhi/mikasmissions/platform/tools/outbox-retention/internal/store"
+)
+
+type fakeStore struct {
+	published int
+	keys      int
+	archived  []store.ArchivedRow
+	moved     int
+	bus       int
+}
+
+func (f *fakeStore) CountPublished(context.Context, time.Time) (int64, error) {
+	return int64(f.published), nil
+}
+
+func (f *fakeStore) CountExpiredKeys(context.Context, time.Time) (int64, error) {
+	return int64(f.keys), nil
+}
+
+func (f *fakeStore) ArchivePublished(_ context.Context, _ time.Time, limit int, sink func([]store.ArchivedRow) error) (int, error) {
+	n := min(limit, f.published)
+	batch := make([]store.ArchivedRow, n)
+	if sink != nil && n > 0 {
+		if err := sink(batch); err != nil {
+			return 0, err
+		}
+	}
+	f.published -= n
+	return n, nil
+}
+
+func (f *fakeStore) MovePublished(_ context.Context, _ time.Time, limit int) (int, error) {
+	n := min(limit, f.published)
+	f.published -= n
+	f.moved += n
+	return n, nil
+}
+
+func (f *fakeStore) PruneExpiredKeys(_ context.Context, _ time.Time, limit int) (int, error) {
+	n := min(limit, f.keys)
+	f.keys -= n
+	return n, nil
+}
+
+func (f *fakeStore) CountAckedBusMessages(context.Context, time.Time) (int64, error) {
+	return int64(f.bus), nil
+}
+
+func (f *fakeStore) PruneAckedBusMessages(_ context.Context, _ time.Time, limit int) (int, error) {
+	n := min(limit, f.bus)
+	f.bus -= n
+	return n, nil
+}
+
+func TestRunArchivesAndPrunesInBatches(t *testing.T) {
+	s := &fakeStore{published: 25, keys: 7}
+	var archived int
+	sink := func(rows []store.ArchivedRow) error {
+		archived += len(rows)
+		return nil
+	}
+	opts := config.Options{OlderThan: time.Hour, Archive: config.ArchiveFile, BatchSize: 10}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	result, err := Run(context.Background(), s, sink, opts, now)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.OutboxRows != 25 || result.OutboxBatches != 3 || archived != 25 {
+		t.Fatalf("expected 25 rows in 3 batches, got %+v archived=%d", result, archived)
+	}
+	if result.ExpiredKeys != 7 || result.KeyBatches != 1 || !result.Cutoff.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("unexpected key pruning result: %+v", result)
+	}
+}
+
+func TestRunStopsAtMaxBatchesAndUsesArchiveTable(t *testing.T) {
+	s := &fakeStore{published: 50}
+	opts := config.Options{OlderThan: time.Hour, Archive: config.ArchiveTable, BatchSize: 10, MaxBatches: 2, SkipIdempotency: true}
+	result, err := Run(context.Background(), s, nil, opts, time.Now())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.OutboxRows != 20 || s.moved != 20 || s.published != 30 {
+		t.Fatalf("expected two batches moved to archive table, got %+v store=%+v", result, s)
+	}
+}
+
+func TestRunStopsWhenArchiveWriteFails(t *testing.T) {
+	s := &fakeStore{published: 5, keys: 3}
+	opts := config.Options{OlderThan: time.Hour, Archive: config.ArchiveFile, BatchSize: 10}
+	_, err := Run(context.Background(), s, func([]store.ArchivedRow) error { return errors.New("disk full") }, opts, time.Now())
+	if err == nil || s.published != 5 || s.keys != 3 {
+		t.Fatalf("expected failed archive to keep rows and stop, got err=%v store=%+v", err, s)
+	}
+}
+
+func TestRunDryRunOnlyCounts(t *testing.T) {
+	s := &fakeStore{published: 12, keys: 4}
+	opts := config.Options{OlderThan: time.Hour, Archive: config.ArchiveFile, BatchSize: 10, DryRun: true}
+	result, err := Run(context.Background(), s, nil, opts, time.Now())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.Applied || result.OutboxRows != 12 || result.ExpiredKeys != 4 || s.published != 12 {
+		t.Fatalf("expected counts only, got %+v store=%+v", result, s)
+	}
+}
+
+func TestRunPrunesAckedBusMessagesUnlessSkipped(t *testing.T) {
+	s := &fakeStore{bus: 23}
+	opts := config.Options{OlderThan: time.Hour, Archive: config.ArchiveNone, BatchSize: 10, SkipOutbox: true, SkipIdempotency: true}
+	result, err := Run(context.Background(), s, nil, opts, time.Now())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.BusMessages != 23 || result.BusBatches != 3 || s.bus != 0 {
+		t.Fatalf("expected acked bus messages pruned in 3 batches, got %+v store=%+v", result, s)
+	}
+	s.bus = 5
+	opts.SkipBus = true
+	if result, err := Run(context.Background(), s, nil, opts, time.Now()); err != nil || result.BusMessages != 0 || s.bus != 5 {
+		t.Fatalf("expected -skip-bus to keep bus messages, got %+v err=%v store=%+v", result, err, s)
+	}
+}
@@ -0,0 +1,45 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const ackedBusMessages = `from events.bus_messages m
+	 where m.created_at < $1
+	   and not exists (
+	     select 1 from events.bus_consumers c
+	     where c.topic = m.topic and (m.tx_id, m.id) > (c.last_tx_id, c.last_message_id)
+	   )
+	   and not exists (
+	     select 1 from events.bus_redeliveries r where r.message_id = m.id
+	   )`
+
+func (s *Store) CountAckedBusMessages(ctx context.Context, cutoff time.Time) (int64, error) {
+	var count int64
+	if err := s.db.QueryRowContext(ctx, `select count(*) `+ackedBusMessages, cutoff).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count acked bus messages: %w", err)
+	}
+	return count, nil
+}
+
+func (s *Store) PruneAckedBusMessages(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	result, err := s.db.ExecContext(
+		ctx,
+		`with batch as (
+		   select m.id `+ackedBusMessages+`
+		   order by m.created_at, m.id
+		   limit $2
+		   for update of m skip locked
+		 )
+		 delete from events.bus_messages m
+		 using batch
+		 where m.id = batch.id`,
+		cutoff, limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("prune acked bus messages: %w", err)
+	}
+	return affected(result, "prune acked bus messages")
+}
@@ -0,0 +1,190 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+type ArchivedRow struct {
+	ID           int64           `json:"id"`
+	EventID      string          `json:"event_id"`
+	Topic        string          `json:"topic"`
+	Payload      json.RawMessage `json:"payload"`
+	Headers      json.RawMessage `json:"headers"`
+	Attempts     int             `json:"attempts"`
+	PartitionKey string          `json:"partition_key,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	PublishedAt  time.Time       `json:"published_at"`
+}
+
+type Store struct {
+	db *sql.DB
+}
+
+func New(databaseURL string) (*Store, error) {
+	db, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("open postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("ping postgres: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) CountPublished(ctx context.Context, cutoff time.Time) (int64, error) {
+	var count int64
+	if err := s.db.QueryRowContext(
+		ctx,
+		`select count(*) from events.outbox where status = 'published' and published_at < $1`,
+		cutoff,
+	).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count archivable outbox rows: %w", err)
+	}
+	return count, nil
+}
+
+func (s *Store) CountExpiredKeys(ctx context.Context, now time.Time) (int64, error) {
+	var count int64
+	if err := s.db.QueryRowContext(
+		ctx,
+		`select count(*) from events.idempotency_keys where expires_at < $1`,
+		now,
+	).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count expired idempotency keys: %w", err)
+	}
+	return count, nil
+}
+
+func (s *Store) ArchivePublished(
+	ctx context.Context,
+	cutoff time.Time,
+	limit int,
+	sink func([]ArchivedRow) error,
+) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin archive tx: %w", err)
+	}
+	defer tx.Rollback()
+	rows, err := tx.QueryContext(
+		ctx,
+		`select id, event_id, topic, payload, headers, attempts, coalesce(partition_key, ''), created_at, published_at
+		 from events.outbox
+		 where status = 'published' and published_at < $1
+		 order by id asc
+		 limit $2
+		 for update skip locked`,
+		cutoff, limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("select archivable outbox rows: %w", err)
+	}
+	batch := make([]ArchivedRow, 0, limit)
+	ids := make([]int64, 0, limit)
+	for rows.Next() {
+		var row ArchivedRow
+		if err := rows.Scan(&row.ID, &row.EventID, &row.Topic, &row.Payload, &row.Headers, &row.Attempts, &row.PartitionKey, &row.CreatedAt, &row.PublishedAt); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan archivable outbox row: %w", err)
+		}
+		batch = append(batch, row)
+		ids = append(ids, row.ID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate archivable outbox rows: %w", err)
+	}
+	if len(batch) == 0 {
+		return 0, nil
+	}
+	if sink != nil {
+		if err := sink(batch); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `delete from events.outbox where id = any($1)`, ids); err != nil {
+		return 0, fmt.Errorf("delete archived outbox rows: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit archive tx: %w", err)
+	}
+	return len(batch), nil
+}
+
+func (s *Store) MovePublished(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	result, err := s.db.ExecContext(
+		ctx,
+		`with batch as (
+		   select id
+		   from events.outbox
+		   where status = 'published' and published_at < $1
+		   order by id asc
+		   limit $2
+		   for update skip locked
+		 ), archived as (
+		   insert into events.outbox_archive (id, event_id, topic, payload, headers, attempts, partition_key, created_at, published_at)
+		   select o.id, o.event_id, o.topic, o.payload, o.headers, o.attempts, o.partition_key, o.created_at, o.published_at
+		   from events.outbox o
+		   join batch on batch.id = o.id
+		   on conflict (id) do update
+		   set event_id = excluded.event_id,
+		       topic = excluded.topic,
+		       payload = excluded.payload,
+		       headers = excluded.headers,
+		       attempts = excluded.attempts,
+		       partition_key = excluded.partition_key,
+		       created_at = excluded.created_at,
+		       published_at = excluded.published_at,
+		       archived_at = now()
+		   returning id
+		 )
+		 delete from events.outbox o
+		 using archived
+		 where o.id = archived.id`,
+		cutoff, limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("move outbox rows to archive table: %w", err)
+	}
+	return affected(result, "move outbox rows to archive table")
+}
+
+func (s *Store) PruneExpiredKeys(ctx context.Context, now time.Time, limit int) (int, error) {
+	result, err := s.db.ExecContext(
+		ctx,
+		`with batch as (
+		   select consumer_scope, event_id
+		   from events.idempotency_keys
+		   where expires_at < $1
+		   limit $2
+		   for update skip locked
+		 )
+		 delete from events.idempotency_keys k
+		 using batch
+		 where k.consumer_scope = batch.consumer_scope and k.event_id = batch.event_id`,
+		now, limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("prune expired idempotency keys: %w", err)
+	}
+	return affected(result, "prune expired idempotency keys")
+}
+
+func affected(result sql.Result, action string) (int, error) {
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s rows affected: %w", action, err)
+	}
+	return int(count), nil
+}
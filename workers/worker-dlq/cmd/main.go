@@ -0,0 +1,32 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
+	"github.com/delqhi/mikasmissions/platform/workers/worker-dlq/internal"
+)
+
+func main() {
+	workerruntime.Main("worker-dlq", func(app *workerruntime.App) error {
+		databaseURL := os.Getenv("DATABASE_URL")
+		if databaseURL == "" {
+			return errors.New("DATABASE_URL is required for worker-dlq")
+		}
+		recorder, err := internal.NewPostgresRecorder(databaseURL)
+		if err != nil {
+			return err
+		}
+		app.OnClose(recorder)
+		app.AddReadinessCheck("database", recorder.Ping)
+
+		processor := internal.NewProcessor(recorder, app.Logger)
+		for _, topic := range internal.DLQTopicsFromEnv(os.Getenv) {
+			if err := app.SubscribeFunc(topic, processor.Consumer(topic), processor.Handle); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+)
+
+type DeadLetter struct {
+	DLQEventID    string
+	EventID       string
+	OriginalTopic string
+	Consumer      string
+	Error         string
+	Attempts      int
+	FailedAt      string
+	Payload       json.RawMessage
+	Headers       queue.Headers
+}
+
+type deadLetterRecorder interface {
+	Record(ctx context.Context, letter DeadLetter) error
+}
+
+type Processor struct {
+	recorder deadLetterRecorder
+	logger   *slog.Logger
+}
+
+func NewProcessor(recorder deadLetterRecorder, logger *slog.Logger) *Processor {
+	return &Processor{recorder: recorder, logger: logger}
+}
+
+func (p *Processor) Consumer(topic string) string {
+	return "worker-dlq-" + strings.ReplaceAll(strings.TrimSuffix(topic, ".dlq.v1"), ".", "_")
+}
+
+func (p *Processor) Handle(ctx context.Context, event queue.Event) error {
+	letter := DeadLetter{
+		DLQEventID:    event.ID,
+		EventID:       event.Headers.CausationID(),
+		OriginalTopic: strings.TrimSuffix(event.Topic, ".dlq.v1"),
+		Payload:       event.Payload,
+		Headers:       event.Headers,
+	}
+	decoded, err := queue.DecodeDLQEvent(event)
+	if err != nil {
+		p.logger.Warn("undecodable dlq event stored raw", "worker", "worker-dlq", "event_id", event.ID, "error", err.Error())
+		letter.Error = err.Error()
+		letter.Payload = rawPayload(event.Payload)
+	} else {
+		letter.EventID = decoded.EventID
+		letter.OriginalTopic = decoded.OriginalTopic
+		letter.Consumer = decoded.Consumer
+		letter.Error = decoded.Error
+		letter.Attempts = decoded.Attempts
+		letter.FailedAt = decoded.FailedAt
+		letter.Payload = decoded.Payload
+	}
+	if letter.EventID == "" {
+		letter.EventID = event.ID
+	}
+	if err := p.recorder.Record(ctx, letter); err != nil {
+		return fmt.Errorf("record dead letter %s: %w", event.ID, err)
+	}
+	p.logger.Info("dead letter recorded", "worker", "worker-dlq", "event_id", letter.EventID, "topic", letter.OriginalTopic, "consumer", letter.Consumer)
+	return nil
+}
+
+func rawPayload(raw []byte) json.RawMessage {
+	if json.Valid(raw) {
+		return json.RawMessage(raw)
+	}
+	encoded, _ := json.Marshal(string(raw))
+	return json.RawMessage(encoded)
+}
@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"slices"
+	"testing"
+
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+)
+
+type recordingRecorder struct {
+	letters []DeadLetter
+}
+
+func (r *recordingRecorder) Record(_ context.Context, letter DeadLetter) error {
+	r.letters = append(r.letters, letter)
+	return nil
+}
+
+func TestProcessorRecordsDeadLettersFromBus(t *testing.T) {
+	bus := queue.NewInMemoryBus()
+	defer bus.Close()
+	recorder := &recordingRecorder{}
+	processor := NewProcessor(recorder, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	topic := queue.DLQTopic("media.uploaded.v1")
+	done := make(chan struct{})
+	_ = bus.Subscribe(context.Background(), topic, processor.Consumer(topic), func(ctx context.Context, event queue.Event) error {
+		defer close(done)
+		return processor.Handle(ctx, event)
+	})
+	_ = bus.Subscribe(context.Background(), "media.uploaded.v1", "worker-ingest", func(context.Context, queue.Event) error {
+		return errors.New("storage down")
+	}, queue.WithMaxDeliver(1))
+	_ = bus.Publish(context.Background(), queue.Event{ID: "evt-1", Topic: "media.uploaded.v1", Payload: []byte(`{"asset_id":"a-1"}`)})
+	<-done
+	if len(recorder.letters) != 1 {
+		t.Fatalf("expected one dead letter, got %d", len(recorder.letters))
+	}
+	letter := recorder.letters[0]
+	if letter.EventID != "evt-1" || letter.OriginalTopic != "media.uploaded.v1" || letter.Consumer != "worker-ingest" {
+		t.Fatalf("unexpected dead letter: %+v", letter)
+	}
+	if letter.Error != "storage down" || string(letter.Payload) != `{"asset_id":"a-1"}` || letter.DLQEventID != "evt-1-worker-ingest-dlq" {
+		t.Fatalf("unexpected dead letter details: %+v", letter)
+	}
+}
+
+func TestProcessorStoresUndecodableDeadLetterRaw(t *testing.T) {
+	recorder := &recordingRecorder{}
+	processor := NewProcessor(recorder, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	err := processor.Handle(context.Background(), queue.Event{
+		ID:      "evt-9-dlq",
+		Topic:   "watch.event.v1.dlq.v1",
+		Payload: []byte("garbage"),
+		Headers: queue.Headers{queue.HeaderCausationID: "evt-9"},
+	})
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	letter := recorder.letters[0]
+	if letter.EventID != "evt-9" || letter.OriginalTopic != "watch.event.v1" || string(letter.Payload) != `"garbage"` {
+		t.Fatalf("unexpected raw dead letter: %+v", letter)
+	}
+}
+
+func TestDLQTopicsFromEnvAndConsumerNames(t *testing.T) {
+	topics := DLQTopicsFromEnv(func(key string) string {
+		if key == "DLQ_TOPICS" {
+			return "media.uploaded.v1, watch.event.v1.dlq.v1,,"
+		}
+		return ""
+	})
+	if !reflect.DeepEqual(topics, []string{"media.uploaded.v1.dlq.v1", "watch.event.v1.dlq.v1"}) {
+		t.Fatalf("unexpected topics: %v", topics)
+	}
+	defaults := DLQTopicsFromEnv(func(string) string { return "" })
+	if len(defaults) != len(contractsevents.Topics())+len(uncontractedTopics) || !slices.Contains(defaults, "video.run.job.updated.v1.dlq.v1") {
+		t.Fatalf("expected default topics to cover every contract topic, got %v", defaults)
+	}
+	processor := NewProcessor(nil, nil)
+	if got := processor.Consumer("media.uploaded.v1.dlq.v1"); got != "worker-dlq-media_uploaded_v1" {
+		t.Fatalf("unexpected consumer name: %s", got)
+	}
+}
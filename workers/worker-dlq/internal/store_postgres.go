@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+type PostgresRecorder struct {
+	db *sql.DB
+}
+
+func NewPostgresRecorder(databaseURL string) (*PostgresRecorder, error) {
+	db, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("open postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("ping postgres: %w", err)
+	}
+	return &PostgresRecorder{db: db}, nil
+}
+
+func (r *PostgresRecorder) Record(ctx context.Context, letter DeadLetter) error {
+	headers, err := json.Marshal(letter.Headers)
+	if err != nil {
+		return fmt.Errorf("marshal dead letter headers: %w", err)
+	}
+	failedAt, err := time.Parse(time.RFC3339, letter.FailedAt)
+	if err != nil {
+		failedAt = time.Now().UTC()
+	}
+	if _, err := r.db.ExecContext(
+		ctx,
+		`insert into events.dead_letters
+		   (dlq_event_id, event_id, original_topic, consumer, error, attempts, failed_at, payload, headers)
+		 values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		 on conflict (dlq_event_id) do nothing`,
+		letter.DLQEventID,
+		letter.EventID,
+		letter.OriginalTopic,
+		letter.Consumer,
+		letter.Error,
+		letter.Attempts,
+		failedAt,
+		[]byte(letter.Payload),
+		headers,
+	); err != nil {
+		return fmt.Errorf("insert dead letter: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRecorder) Close() error {
+	return r.db.Close()
+}
+
+func (r *PostgresRecorder) Ping(ctx context.Context) error {
+	if err := r.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping dead letter database: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"sort"
+	"strings"
+
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+)
+
+var uncontractedTopics = []string{
+	"analytics.rollup.v1",
+	"reco.refresh.requested.v1",
+}
+
+func defaultTopics() []string {
+	topics := append(contractsevents.Topics(), uncontractedTopics...)
+	sort.Strings(topics)
+	return topics
+}
+
+func DLQTopicsFromEnv(getenv func(string) string) []string {
+	topics := defaultTopics()
+	if raw := strings.TrimSpace(getenv("DLQ_TOPICS")); raw != "" {
+		topics = nil
+		for _, topic := range strings.Split(raw, ",") {
+			if topic = strings.TrimSpace(topic); topic != "" {
+				topics = append(topics, topic)
+			}
+		}
+	}
+	dlqTopics := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		if !queue.IsDLQTopic(topic) {
+			topic = queue.DLQTopic(topic)
+		}
+		dlqTopics = append(dlqTopics, topic)
+	}
+	return dlqTopics
+}
@@ -1,30 +1,27 @@
 package main
 
 import (
-	"context"
-	"log"
-	"log/slog"
-	"os"
-	"os/signal"
-	"syscall"
+	"time"
 
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
 	"github.com/delqhi/mikasmissions/platform/workers/worker-gen-nim/internal"
 )
 
 func main() {
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	bus, err := queue.NewBusFromEnv()
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer bus.Close()
-	processor := internal.NewProcessor(bus, logger)
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
-	if err := bus.Subscribe(ctx, processor.Topic(), processor.Consumer(), processor.Handle); err != nil {
-		log.Fatal(err)
-	}
-	logger.Info("worker started", "worker", processor.Consumer(), "topic", processor.Topic())
-	<-ctx.Done()
+	workerruntime.Main("worker-gen-nim", func(app *workerruntime.App) error {
+		worker := internal.NewWorker(app.Options()...)
+		if err := app.Subscribe(worker.Cancellations, queue.WithBroadcast()); err != nil {
+			return err
+		}
+		if err := app.Subscribe(worker.JobUpdates, queue.WithBroadcast()); err != nil {
+			return err
+		}
+		return app.Subscribe(worker.Steps,
+			queue.WithMaxInFlight(4),
+			queue.WithAckWait(2*time.Minute),
+			queue.WithHandlerTimeout(30*time.Minute),
+			queue.WithOrderingKey(queue.OrderingKeyFromJSONField("run_id")),
+		)
+	})
 }
@@ -0,0 +1,35 @@
+package internal
+
+import "sync"
+
+type jobWaiters struct {
+	mu      sync.Mutex
+	waiters map[string]chan struct{}
+}
+
+func newJobWaiters() *jobWaiters {
+	return &jobWaiters{waiters: map[string]chan struct{}{}}
+}
+
+func (w *jobWaiters) wait(jobID string) (<-chan struct{}, func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	wake := make(chan struct{}, 1)
+	w.waiters[jobID] = wake
+	return wake, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if w.waiters[jobID] == wake {
+			delete(w.waiters, jobID)
+		}
+	}
+}
+
+func (w *jobWaiters) notify(jobID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	select {
+	case w.waiters[jobID] <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/delqhi/mikasmissions/platform/libs/generatorprovider"
+	"github.com/delqhi/mikasmissions/platform/libs/runtimecfg"
+)
+
+var errJobStore = errors.New("generation job store unavailable")
+
+type jobRecord struct {
+	RunID     string
+	StepIndex int
+	JobID     string
+	Provider  string
+	Status    generatorprovider.JobStatus
+	Progress  int
+}
+
+type jobStore interface {
+	Find(ctx context.Context, runID string, stepIndex int) (jobRecord, bool, error)
+	Save(ctx context.Context, record jobRecord) error
+}
+
+func newJobStoreFromEnv() jobStore {
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL != "" {
+		store, err := newPostgresJobStore(databaseURL)
+		if err == nil {
+			return store
+		}
+		if runtimecfg.PersistentStorageRequired() {
+			panic(err)
+		}
+	}
+	if runtimecfg.PersistentStorageRequired() {
+		panic("DATABASE_URL is required for worker-gen-nim in strict persistence mode")
+	}
+	return newMemoryJobStore()
+}
+
+type jobKey struct {
+	runID     string
+	stepIndex int
+}
+
+type memoryJobStore struct {
+	mu   sync.Mutex
+	jobs map[jobKey]jobRecord
+}
+
+func newMemoryJobStore() *memoryJobStore {
+	return &memoryJobStore{jobs: map[jobKey]jobRecord{}}
+}
+
+func (s *memoryJobStore) Find(_ context.Context, runID string, stepIndex int) (jobRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.jobs[jobKey{runID: runID, stepIndex: stepIndex}]
+	return record, ok, nil
+}
+
+func (s *memoryJobStore) Save(_ context.Context, record jobRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[jobKey{runID: record.RunID, stepIndex: record.StepIndex}] = record
+	return nil
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/delqhi/mikasmissions/platform/libs/generatorprovider"
+)
+
+type postgresJobStore struct {
+	db *sql.DB
+}
+
+func newPostgresJobStore(databaseURL string) (*postgresJobStore, error) {
+	db, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("open postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("ping postgres: %w", err)
+	}
+	return &postgresJobStore{db: db}, nil
+}
+
+func (s *postgresJobStore) Find(ctx context.Context, runID string, stepIndex int) (jobRecord, bool, error) {
+	record := jobRecord{RunID: runID, StepIndex: stepIndex}
+	var status string
+	err := s.db.QueryRowContext(
+		ctx,
+		`select job_id, provider, status, progress
+		 from creator.generation_jobs
+		 where run_id::text = $1 and step_index = $2`,
+		runID,
+		stepIndex,
+	).Scan(&record.JobID, &record.Provider, &status, &record.Progress)
+	if errors.Is(err, sql.ErrNoRows) {
+		return jobRecord{}, false, nil
+	}
+	if err != nil {
+		return jobRecord{}, false, fmt.Errorf("%w: query generation job: %w", errJobStore, err)
+	}
+	record.Status = generatorprovider.JobStatus(status)
+	return record, true, nil
+}
+
+func (s *postgresJobStore) Save(ctx context.Context, record jobRecord) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		`insert into creator.generation_jobs (run_id, step_index, job_id, provider, status, progress, updated_at)
+		 values ($1::uuid, $2, $3, $4, $5, $6, now())
+		 on conflict (run_id, step_index) do update
+		 set job_id = excluded.job_id,
+		     provider = excluded.provider,
+		     status = excluded.status,
+		     progress = excluded.progress,
+		     updated_at = now()`,
+		record.RunID,
+		record.StepIndex,
+		record.JobID,
+		record.Provider,
+		string(record.Status),
+		record.Progress,
+	)
+	if err != nil {
+		return fmt.Errorf("%w: save generation job: %w", errJobStore, err)
+	}
+	return nil
+}
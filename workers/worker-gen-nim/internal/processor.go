@@ -2,125 +2,155 @@ package internal
 
 import (
 	"context"
-	"encoding/json"
-	"log/slog"
+	"errors"
+	"slices"
 	"time"
 
 	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
 	"github.com/delqhi/mikasmissions/platform/libs/generatorprovider"
 	"github.com/delqhi/mikasmissions/platform/libs/generatorrunstore"
-	"github.com/delqhi/mikasmissions/platform/libs/queue"
-	"github.com/google/uuid"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
 )
 
-type Processor struct {
-	bus          queue.Bus
-	guard        *queue.IdempotencyGuard
-	profileStore modelProfileReader
-	logger       *slog.Logger
+type generator struct {
+	profileStore  modelProfileReader
+	cancellations *generatorrunstore.Cancellations
+	jobs          jobStore
+	waiters       *jobWaiters
+	poll          generatorprovider.PollPolicy
+	retry         generatorprovider.RetryPolicy
+	callbackBase  string
+	callbackToken string
 }
 
-func NewProcessor(bus queue.Bus, logger *slog.Logger) *Processor {
-	return &Processor{
-		bus:          bus,
-		guard:        queue.NewScopedIdempotencyGuard("worker-gen-nim"),
-		profileStore: newModelProfileReaderFromEnv(),
-		logger:       logger,
+type Worker struct {
+	Steps         *workerruntime.Processor[contractsevents.VideoRunStepRequestedV1]
+	Cancellations *workerruntime.Processor[contractsevents.VideoRunCancelledV1]
+	JobUpdates    *workerruntime.Processor[contractsevents.VideoRunJobUpdatedV1]
+}
+
+func NewWorker(opts ...workerruntime.Option) *Worker {
+	poll := generatorprovider.DefaultPollPolicy()
+	poll.Initial = time.Duration(envOrInt("GEN_JOB_POLL_INITIAL_MS", 2000)) * time.Millisecond
+	poll.Max = time.Duration(envOrInt("GEN_JOB_POLL_MAX_MS", 30000)) * time.Millisecond
+	retry := generatorprovider.DefaultRetryPolicy(0)
+	retry.Initial = time.Duration(envOrInt("GEN_RETRY_INITIAL_MS", 1000)) * time.Millisecond
+	retry.Max = time.Duration(envOrInt("GEN_RETRY_MAX_MS", 30000)) * time.Millisecond
+	g := &generator{
+		profileStore:  newModelProfileReaderFromEnv(),
+		cancellations: generatorrunstore.NewCancellations(time.Duration(envOrInt("GEN_CANCEL_POLL_MS", 2000)) * time.Millisecond),
+		jobs:          newJobStoreFromEnv(),
+		poll:          poll,
+		retry:         retry,
+		callbackBase:  envOr("GEN_CALLBACK_URL", ""),
+		callbackToken: envOr("GEN_CALLBACK_TOKEN", ""),
 	}
+	return newWorker(g, opts...)
 }
 
-func (p *Processor) Topic() string {
-	return "video.run.requested.v1"
+func newWorker(g *generator, opts ...workerruntime.Option) *Worker {
+	g.waiters = newJobWaiters()
+	local := append(slices.Clip(opts), workerruntime.WithLocalGuard())
+	return &Worker{
+		Steps:         workerruntime.NewProcessor("worker-gen-nim", "video.run.step.requested.v1", g.generate, opts...),
+		Cancellations: workerruntime.NewProcessor("worker-gen-nim-cancellations", "video.run.cancelled.v1", g.cancel, local...),
+		JobUpdates:    workerruntime.NewProcessor("worker-gen-nim-job-updates", "video.run.job.updated.v1", g.jobUpdated, local...),
+	}
 }
 
-func (p *Processor) Consumer() string {
-	return "worker-gen-nim"
+func (g *generator) jobUpdated(_ context.Context, incoming contractsevents.VideoRunJobUpdatedV1) ([]workerruntime.Output, error) {
+	g.waiters.notify(incoming.JobID)
+	return nil, nil
 }
 
-func (p *Processor) Handle(ctx context.Context, event queue.Event) error {
-	if p.guard.Seen(event.ID) {
-		p.logger.Info("duplicate event ignored", "worker", p.Consumer(), "event_id", event.ID)
-		return nil
-	}
-	if event.Topic != p.Topic() {
-		p.logger.Info("unexpected topic skipped", "worker", p.Consumer(), "topic", event.Topic)
-		return nil
-	}
-	var incoming contractsevents.VideoRunRequestedV1
-	if err := json.Unmarshal(event.Payload, &incoming); err != nil {
-		return err
+func (g *generator) cancel(_ context.Context, incoming contractsevents.VideoRunCancelledV1) ([]workerruntime.Output, error) {
+	g.cancellations.Cancel(incoming.RunID)
+	return nil, nil
+}
+
+func (g *generator) generate(ctx context.Context, incoming contractsevents.VideoRunStepRequestedV1) ([]workerruntime.Output, error) {
+	run, ok := g.runner(incoming.Step)
+	if !ok {
+		return nil, nil
 	}
-	if err := incoming.Validate(); err != nil {
-		return err
+	if g.cancellations.Cancelled(ctx, incoming.RunID) {
+		return stepCancelled(ctx, incoming, "step skipped: run cancelled"), nil
 	}
-	profile, err := p.profileStore.GetProfile(incoming.ModelProfileID)
+	profile, err := g.profileStore.GetProfile(incoming.ModelProfileID)
 	if err != nil {
-		p.publishFailed(ctx, incoming.RunID, "nim_profile_error", err.Error())
-		return nil
+		return stepFailed(ctx, incoming, "nim_profile_error", err.Error()), nil
 	}
 	provider, err := generatorprovider.NewProvider(profile)
 	if err != nil {
-		p.publishFailed(ctx, incoming.RunID, "nim_provider_error", err.Error())
-		return nil
+		return stepFailed(ctx, incoming, "nim_provider_error", err.Error()), nil
 	}
-	result, err := provider.GenerateVideo(generatorprovider.GenerateRequest{
-		RunID:        incoming.RunID,
-		InputPayload: incoming.InputPayload,
+	backend, _ := generatorprovider.Lookup(profile.Provider)
+	if !backend.Capabilities.SupportsStep(incoming.Step) {
+		return stepFailed(ctx, incoming, "nim_provider_error", "provider "+profile.Provider+" does not support step "+incoming.Step), nil
+	}
+	guarded, done := g.cancellations.Guard(ctx, incoming.RunID)
+	req := stepRequest{
+		GenerateRequest: generatorprovider.GenerateRequest{
+			RunID:        incoming.RunID,
+			InputPayload: incoming.InputPayload,
+			Artifacts:    incoming.Artifacts,
+		},
+		StepIndex:    incoming.StepIndex,
+		Provider:     profile.Provider,
+		Capabilities: backend.Capabilities,
+	}
+	result, attempt, err := g.withRetries(ctx, guarded, incoming, profile.MaxRetries, func(attemptCtx context.Context) (stepResult, error) {
+		return run(attemptCtx, provider, req)
 	})
-	if err != nil {
-		p.publishFailed(ctx, incoming.RunID, "nim_provider_error", err.Error())
-		return nil
+	cancelled := generatorrunstore.IsCancelled(guarded)
+	done()
+	if cancelled || g.cancellations.Cancelled(ctx, incoming.RunID) {
+		return stepCancelled(ctx, incoming, "provider call aborted: run cancelled"), nil
 	}
-	ready := contractsevents.VideoAssetReadyV1{
-		RunID:              incoming.RunID,
-		AssetID:            result.AssetID,
-		SourceURL:          result.SourceURL,
-		DurationMS:         result.DurationMS,
-		ContentSuitability: incoming.ContentSuitability,
-		AgeBand:            incoming.AgeBand,
-		UploaderID:         incoming.RequestedBy,
-		ReadyAt:            time.Now().UTC().Format(time.RFC3339),
+	if err != nil && (errors.Is(err, errJobStore) || ctx.Err() != nil) {
+		return nil, err
 	}
-	readyPayload, err := json.Marshal(ready)
 	if err != nil {
-		return err
-	}
-	if err := p.bus.Publish(ctx, queue.Event{ID: uuid.NewString(), Topic: "video.asset.ready.v1", Payload: readyPayload}); err != nil {
-		return err
+		return stepFailed(ctx, incoming, "nim_provider_error", err.Error()+attemptSuffix(attempt)), nil
 	}
-	stepPayload, err := json.Marshal(contractsevents.VideoRunStepCompletedV1{
+	_ = generatorrunstore.AppendRunLog(ctx, incoming.RunID, incoming.Step, "completed", result.details+attemptSuffix(attempt))
+	outputs := []workerruntime.Output{workerruntime.Emit("video.run.step.completed.v1", contractsevents.VideoRunStepCompletedV1{
 		RunID:       incoming.RunID,
-		Step:        "nim",
+		Step:        incoming.Step,
+		StepIndex:   incoming.StepIndex,
 		Status:      "completed",
-		Details:     "nim generation completed",
+		Details:     result.details,
+		Artifacts:   result.artifacts,
 		CompletedAt: time.Now().UTC().Format(time.RFC3339),
-	})
-	if err != nil {
-		return err
-	}
-	if err := p.bus.Publish(ctx, queue.Event{ID: uuid.NewString(), Topic: "video.run.step.completed.v1", Payload: stepPayload}); err != nil {
-		return err
+	})}
+	if result.asset != nil && result.asset.DurationMS > 0 {
+		outputs = append(outputs, workerruntime.Emit("video.asset.ready.v1", contractsevents.VideoAssetReadyV1{
+			RunID:              incoming.RunID,
+			AssetID:            result.asset.AssetID,
+			SourceURL:          result.asset.SourceURL,
+			DurationMS:         result.asset.DurationMS,
+			ContentSuitability: incoming.ContentSuitability,
+			AgeBand:            incoming.AgeBand,
+			UploaderID:         incoming.RequestedBy,
+			ReadyAt:            time.Now().UTC().Format(time.RFC3339),
+		}))
 	}
-	_ = generatorrunstore.AppendRunLog(ctx, incoming.RunID, "nim", "completed", "nim generation completed")
-	p.logger.Info("nim generation completed", "run_id", incoming.RunID, "asset_id", result.AssetID)
-	return nil
+	return outputs, nil
 }
 
-func (p *Processor) publishFailed(ctx context.Context, runID, code, message string) {
-	_ = generatorrunstore.SetRunStatus(ctx, runID, "failed", message)
-	_ = generatorrunstore.AppendRunLog(ctx, runID, "nim", "failed", message)
-	payload, err := json.Marshal(contractsevents.VideoRunFailedV1{
-		RunID:        runID,
-		Step:         "nim",
+func stepFailed(ctx context.Context, incoming contractsevents.VideoRunStepRequestedV1, code, message string) []workerruntime.Output {
+	_ = generatorrunstore.AppendRunLog(ctx, incoming.RunID, incoming.Step, "failed", message)
+	return []workerruntime.Output{workerruntime.Emit("video.run.failed.v1", contractsevents.VideoRunFailedV1{
+		RunID:        incoming.RunID,
+		Step:         incoming.Step,
+		StepIndex:    incoming.StepIndex,
 		ErrorCode:    code,
 		ErrorMessage: message,
 		FailedAt:     time.Now().UTC().Format(time.RFC3339),
-	})
-	if err != nil {
-		p.logger.Error("failed to encode failed event", "error", err.Error(), "run_id", runID)
-		return
-	}
-	if err := p.bus.Publish(ctx, queue.Event{ID: uuid.NewString(), Topic: "video.run.failed.v1", Payload: payload}); err != nil {
-		p.logger.Error("failed to publish failed event", "error", err.Error(), "run_id", runID)
-	}
+	})}
+}
+
+func stepCancelled(ctx context.Context, incoming contractsevents.VideoRunStepRequestedV1, message string) []workerruntime.Output {
+	_ = generatorrunstore.AppendRunLog(ctx, incoming.RunID, incoming.Step, "cancelled", message)
+	return nil
 }
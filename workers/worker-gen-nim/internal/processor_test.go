@@ -0,0 +1,232 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/generatorprovider"
+	"github.com/delqhi/mikasmissions/platform/libs/generatorrunstore"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
+)
+
+type fixedProfile struct {
+	baseURL    string
+	maxRetries int
+}
+
+func (f fixedProfile) GetProfile(string) (generatorprovider.ModelProfile, error) {
+	return generatorprovider.ModelProfile{Provider: "nvidia_nim", BaseURL: f.baseURL, ModelID: "nim-video-v1", MaxRetries: f.maxRetries}, nil
+}
+
+func noCancellations() *generatorrunstore.Cancellations {
+	return generatorrunstore.NewCancellationsWithStatus(func(context.Context, string) (string, error) { return "", nil }, 0)
+}
+
+func testWorker(baseURL string, jobs jobStore, bus queue.Bus) *Worker {
+	return testWorkerWithProfiles(fixedProfile{baseURL: baseURL}, jobs, bus)
+}
+
+func testWorkerWithProfiles(profiles modelProfileReader, jobs jobStore, bus queue.Bus) *Worker {
+	g := &generator{
+		profileStore:  profiles,
+		cancellations: noCancellations(),
+		jobs:          jobs,
+		poll:          generatorprovider.PollPolicy{Initial: time.Millisecond, Max: 5 * time.Millisecond, Multiplier: 2, MaxStatusErrors: 2},
+		retry:         generatorprovider.RetryPolicy{Initial: time.Millisecond, Max: 2 * time.Millisecond, Multiplier: 2},
+	}
+	return newWorker(g, workerruntime.WithBus(bus), workerruntime.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))))
+}
+
+func newNIMServer(t *testing.T) *httptest.Server {
+	server, _ := newCountingNIMServer(t)
+	return server
+}
+
+func newCountingNIMServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var submits, polls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/generate/script", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"script":"Once upon a time in space"}`))
+	})
+	mux.HandleFunc("POST /v1/jobs/video", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Artifacts map[string]string `json:"artifacts"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.Artifacts["script"] == "" {
+			http.Error(w, "missing script", http.StatusBadRequest)
+			return
+		}
+		submits.Add(1)
+		_, _ = w.Write([]byte(`{"job_id":"job-1","status":"queued"}`))
+	})
+	mux.HandleFunc("GET /v1/jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if polls.Add(1) < 3 {
+			_, _ = w.Write([]byte(`{"job_id":"` + r.PathValue("id") + `","status":"running","progress":40}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"job_id":"` + r.PathValue("id") + `","status":"succeeded","progress":100}`))
+	})
+	mux.HandleFunc("GET /v1/jobs/{id}/result", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"asset_id":"asset-1","source_url":"https://cdn.example/asset-1.mp4","duration_ms":90000}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &submits
+}
+
+func stepCommand(t *testing.T, step string, artifacts map[string]string) queue.Event {
+	t.Helper()
+	payload, err := json.Marshal(contractsevents.VideoRunStepRequestedV1{
+		RunID:              "run-1",
+		WorkflowID:         "wf-1",
+		Step:               step,
+		StepIndex:          1,
+		ModelProfileID:     "nim-default",
+		InputPayload:       json.RawMessage(`{"theme":"space"}`),
+		ContentSuitability: "core",
+		AgeBand:            "6-11",
+		RequestedBy:        "admin-1",
+		Artifacts:          artifacts,
+		RequestedAt:        "2026-03-11T10:00:00Z",
+	})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return queue.Event{ID: "cmd-" + step, Topic: "video.run.step.requested.v1", Payload: payload}
+}
+
+func collect[T any](t *testing.T, bus queue.Bus, topic string, into *[]T) {
+	t.Helper()
+	if err := bus.Subscribe(context.Background(), topic, "test-"+topic, func(_ context.Context, event queue.Event) error {
+		var decoded T
+		if err := json.Unmarshal(event.Payload, &decoded); err != nil {
+			return err
+		}
+		*into = append(*into, decoded)
+		return nil
+	}); err != nil {
+		t.Fatalf("subscribe %s: %v", topic, err)
+	}
+}
+
+func TestProcessorRunsVideoStepWithPriorArtifacts(t *testing.T) {
+	bus := queue.NewInMemoryBus()
+	processor := testWorker(newNIMServer(t).URL, newMemoryJobStore(), bus).Steps
+	var completed []contractsevents.VideoRunStepCompletedV1
+	var ready []contractsevents.VideoAssetReadyV1
+	collect(t, bus, "video.run.step.completed.v1", &completed)
+	collect(t, bus, "video.asset.ready.v1", &ready)
+
+	if err := processor.Handle(context.Background(), stepCommand(t, "nim", map[string]string{"script": "Once upon a time"})); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if len(completed) != 1 || completed[0].Step != "nim" || completed[0].StepIndex != 1 {
+		t.Fatalf("unexpected step completion: %+v", completed)
+	}
+	if completed[0].Artifacts["source_url"] != "https://cdn.example/asset-1.mp4" || completed[0].Artifacts["duration_ms"] != "90000" {
+		t.Fatalf("unexpected artifacts: %v", completed[0].Artifacts)
+	}
+	if len(ready) != 1 || ready[0].AssetID != "asset-1" {
+		t.Fatalf("expected asset ready event, got %+v", ready)
+	}
+}
+
+func TestProcessorFailsStepOnProviderRejection(t *testing.T) {
+	bus := queue.NewInMemoryBus()
+	processor := testWorker(newNIMServer(t).URL, newMemoryJobStore(), bus).Steps
+	var failed []contractsevents.VideoRunFailedV1
+	collect(t, bus, "video.run.failed.v1", &failed)
+
+	if err := processor.Handle(context.Background(), stepCommand(t, "nim", nil)); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if len(failed) != 1 || failed[0].Step != "nim" || failed[0].StepIndex != 1 || failed[0].ErrorCode != "nim_provider_error" {
+		t.Fatalf("unexpected failure events: %+v", failed)
+	}
+}
+
+func TestVoiceStepSendsStepIdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		_, _ = w.Write([]byte(`{"audio_url":"https://cdn.example/voice.wav","duration_ms":4000}`))
+	}))
+	t.Cleanup(server.Close)
+	bus := queue.NewInMemoryBus()
+	var completed []contractsevents.VideoRunStepCompletedV1
+	collect(t, bus, "video.run.step.completed.v1", &completed)
+
+	if err := testWorker(server.URL, newMemoryJobStore(), bus).Steps.Handle(context.Background(), stepCommand(t, "voice", map[string]string{"script": "Once upon a time"})); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if len(completed) != 1 || len(keys) != 1 || keys[0] != "run-1:1" {
+		t.Fatalf("expected one keyed voice call, got keys=%v completed=%+v", keys, completed)
+	}
+}
+
+func TestProcessorIgnoresStepsOwnedByOtherWorkers(t *testing.T) {
+	bus := queue.NewInMemoryBus()
+	processor := testWorker("http://127.0.0.1:1", newMemoryJobStore(), bus).Steps
+	var completed []contractsevents.VideoRunStepCompletedV1
+	collect(t, bus, "video.run.step.completed.v1", &completed)
+
+	if err := processor.Handle(context.Background(), stepCommand(t, "qc", nil)); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if len(completed) != 0 {
+		t.Fatalf("expected qc step to be ignored, got %+v", completed)
+	}
+}
+
+func TestCancellationAbortsInFlightProviderCall(t *testing.T) {
+	started := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		close(started)
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+	bus := queue.NewInMemoryBus()
+	worker := testWorker(server.URL, newMemoryJobStore(), bus)
+	var completed []contractsevents.VideoRunStepCompletedV1
+	var failed []contractsevents.VideoRunFailedV1
+	collect(t, bus, "video.run.step.completed.v1", &completed)
+	collect(t, bus, "video.run.failed.v1", &failed)
+
+	go func() {
+		<-started
+		payload, _ := json.Marshal(contractsevents.VideoRunCancelledV1{RunID: "run-1", CancelledBy: "admin-1", CancelledAt: "2026-03-11T10:00:30Z"})
+		_ = worker.Cancellations.Handle(context.Background(), queue.Event{ID: "cancel-1", Topic: "video.run.cancelled.v1", Payload: payload})
+	}()
+	if err := worker.Steps.Handle(context.Background(), stepCommand(t, "nim", map[string]string{"script": "Once upon a time"})); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if len(completed) != 0 || len(failed) != 0 {
+		t.Fatalf("expected cancelled step to emit nothing, got completed=%+v failed=%+v", completed, failed)
+	}
+
+	if err := worker.Steps.Handle(context.Background(), stepCommand(t, "voice", nil)); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if len(completed) != 0 || len(failed) != 0 {
+		t.Fatalf("expected later steps of a cancelled run to be skipped")
+	}
+}
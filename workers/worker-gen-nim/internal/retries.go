@@ -0,0 +1,31 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/generatorprovider"
+	"github.com/delqhi/mikasmissions/platform/libs/generatorrunstore"
+)
+
+func (g *generator) withRetries(ctx, guarded context.Context, incoming contractsevents.VideoRunStepRequestedV1, maxRetries int, call func(context.Context) (stepResult, error)) (stepResult, generatorprovider.Attempt, error) {
+	policy := g.retry
+	policy.MaxRetries = maxRetries
+	var last generatorprovider.Attempt
+	result, err := generatorprovider.Retry(guarded, policy, call, func(attempt generatorprovider.Attempt) {
+		last = attempt
+		if attempt.Retrying {
+			message := fmt.Sprintf("attempt %d/%d failed: %v; retrying in %s", attempt.Number, attempt.Attempts, attempt.Err, attempt.Wait)
+			_ = generatorrunstore.AppendRunLog(ctx, incoming.RunID, incoming.Step, "retrying", message)
+		}
+	})
+	return result, last, err
+}
+
+func attemptSuffix(attempt generatorprovider.Attempt) string {
+	if attempt.Attempts <= 1 {
+		return ""
+	}
+	return fmt.Sprintf(" (attempt %d/%d)", attempt.Number, attempt.Attempts)
+}
@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
+)
+
+func flakyScriptServer(t *testing.T, failures int32, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) <= failures {
+			http.Error(w, "provider unavailable", status)
+			return
+		}
+		_, _ = w.Write([]byte(`{"script":"Once upon a time in space"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func runScriptStep(t *testing.T, server *httptest.Server, maxRetries int) ([]contractsevents.VideoRunStepCompletedV1, []contractsevents.VideoRunFailedV1) {
+	t.Helper()
+	bus := queue.NewInMemoryBus()
+	worker := testWorkerWithProfiles(fixedProfile{baseURL: server.URL, maxRetries: maxRetries}, newMemoryJobStore(), bus)
+	var completed []contractsevents.VideoRunStepCompletedV1
+	var failed []contractsevents.VideoRunFailedV1
+	collect(t, bus, "video.run.step.completed.v1", &completed)
+	collect(t, bus, "video.run.failed.v1", &failed)
+	if err := worker.Steps.Handle(context.Background(), stepCommand(t, "script", nil)); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	return completed, failed
+}
+
+func TestScriptStepRetriesTransientFailures(t *testing.T) {
+	server, calls := flakyScriptServer(t, 2, http.StatusServiceUnavailable)
+	completed, failed := runScriptStep(t, server, 2)
+	if calls.Load() != 3 || len(completed) != 1 || len(failed) != 0 {
+		t.Fatalf("expected success on third attempt, got calls=%d completed=%+v failed=%+v", calls.Load(), completed, failed)
+	}
+}
+
+func TestScriptStepFailsAfterMaxRetries(t *testing.T) {
+	server, calls := flakyScriptServer(t, 5, http.StatusBadGateway)
+	completed, failed := runScriptStep(t, server, 1)
+	if calls.Load() != 2 || len(completed) != 0 || len(failed) != 1 || failed[0].ErrorMessage != "nim server error status: 502 (attempt 2/2)" {
+		t.Fatalf("expected failure after two attempts, got calls=%d failed=%+v", calls.Load(), failed)
+	}
+}
+
+func TestScriptStepDoesNotRetryValidationErrors(t *testing.T) {
+	server, calls := flakyScriptServer(t, 5, http.StatusUnprocessableEntity)
+	_, failed := runScriptStep(t, server, 3)
+	if calls.Load() != 1 || len(failed) != 1 {
+		t.Fatalf("expected a single terminal attempt, got calls=%d failed=%+v", calls.Load(), failed)
+	}
+}
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/delqhi/mikasmissions/platform/libs/generatorprovider"
+)
+
+type stepResult struct {
+	details   string
+	artifacts map[string]string
+	asset     *generatorprovider.GenerateResult
+}
+
+type stepRequest struct {
+	generatorprovider.GenerateRequest
+	StepIndex    int
+	Provider     string
+	Capabilities generatorprovider.Capabilities
+}
+
+type stepRunner func(ctx context.Context, provider generatorprovider.Provider, req stepRequest) (stepResult, error)
+
+func (g *generator) runner(step string) (stepRunner, bool) {
+	switch step {
+	case "script":
+		return runScript, true
+	case "voice":
+		return runVoice, true
+	case "nim":
+		return g.runVideo, true
+	default:
+		return nil, false
+	}
+}
+
+func runScript(ctx context.Context, provider generatorprovider.Provider, req stepRequest) (stepResult, error) {
+	result, err := provider.GenerateScript(ctx, req.GenerateRequest)
+	if err != nil {
+		return stepResult{}, err
+	}
+	return stepResult{
+		details:   "script generation completed",
+		artifacts: map[string]string{"script": result.Script},
+	}, nil
+}
+
+func runVoice(ctx context.Context, provider generatorprovider.Provider, req stepRequest) (stepResult, error) {
+	req.IdempotencyKey = stepKey(req)
+	result, err := provider.GenerateVoice(ctx, req.GenerateRequest)
+	if err != nil {
+		return stepResult{}, err
+	}
+	return stepResult{
+		details: "voice generation completed",
+		artifacts: map[string]string{
+			"voice_url":         result.AudioURL,
+			"voice_duration_ms": strconv.FormatInt(result.DurationMS, 10),
+		},
+	}, nil
+}
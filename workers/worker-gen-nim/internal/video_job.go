@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/delqhi/mikasmissions/platform/libs/generatorprovider"
+	"github.com/delqhi/mikasmissions/platform/libs/generatorrunstore"
+)
+
+func (g *generator) runVideo(ctx context.Context, provider generatorprovider.Provider, req stepRequest) (stepResult, error) {
+	record, found, err := g.jobs.Find(ctx, req.RunID, req.StepIndex)
+	if err != nil {
+		return stepResult{}, err
+	}
+	if found && record.Status != generatorprovider.JobFailed {
+		_ = generatorrunstore.AppendRunLog(ctx, req.RunID, "nim", "resumed", "resuming video job "+record.JobID)
+	} else {
+		if req.Capabilities.Callbacks {
+			req.CallbackURL = g.callbackURL(req.RunID)
+		}
+		req.IdempotencyKey = submitKey(req, record, found)
+		job, err := provider.SubmitVideo(ctx, req.GenerateRequest)
+		if err != nil {
+			return stepResult{}, err
+		}
+		if job.Result != nil {
+			_ = generatorrunstore.AppendRunLog(ctx, req.RunID, "nim", "completed", "video generated synchronously")
+			return videoStepResult(req.Provider, job.ID, *job.Result), nil
+		}
+		record = jobRecord{RunID: req.RunID, StepIndex: req.StepIndex, JobID: job.ID, Provider: req.Provider, Status: job.Status, Progress: job.Progress}
+		if err := g.jobs.Save(ctx, record); err != nil {
+			return stepResult{}, err
+		}
+		_ = generatorrunstore.AppendRunLog(ctx, req.RunID, "nim", "submitted", "video job "+job.ID+" submitted")
+	}
+	wake, release := g.waiters.wait(record.JobID)
+	defer release()
+	result, err := generatorprovider.AwaitVideo(ctx, provider, record.JobID, g.poll, wake, func(job generatorprovider.Job) {
+		record.Status, record.Progress = job.Status, job.Progress
+		_ = g.jobs.Save(ctx, record)
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			record.Status = generatorprovider.JobFailed
+			_ = g.jobs.Save(ctx, record)
+		}
+		return stepResult{}, err
+	}
+	return videoStepResult(req.Provider, record.JobID, result), nil
+}
+
+func videoStepResult(provider, jobID string, result generatorprovider.GenerateResult) stepResult {
+	artifacts := map[string]string{
+		"provider":   provider,
+		"job_id":     jobID,
+		"asset_id":   result.AssetID,
+		"source_url": result.SourceURL,
+	}
+	if result.DurationMS > 0 {
+		artifacts["duration_ms"] = strconv.FormatInt(result.DurationMS, 10)
+	}
+	return stepResult{
+		details:   fmt.Sprintf("nim generation completed (job %s)", jobID),
+		artifacts: artifacts,
+		asset:     &result,
+	}
+}
+
+func stepKey(req stepRequest) string {
+	return fmt.Sprintf("%s:%d", req.RunID, req.StepIndex)
+}
+
+func submitKey(req stepRequest, failed jobRecord, found bool) string {
+	key := stepKey(req)
+	if found {
+		key += ":" + failed.JobID
+	}
+	return key
+}
+
+func (g *generator) callbackURL(runID string) string {
+	if g.callbackBase == "" {
+		return ""
+	}
+	callback := strings.TrimRight(g.callbackBase, "/") + "/v1/admin/runs/" + url.PathEscape(runID) + "/generation-callback"
+	if g.callbackToken != "" {
+		callback += "?token=" + url.QueryEscape(g.callbackToken)
+	}
+	return callback
+}
@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/generatorprovider"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
+)
+
+func TestVideoStepPersistsJobUntilCompletion(t *testing.T) {
+	server, submits := newCountingNIMServer(t)
+	jobs := newMemoryJobStore()
+	bus := queue.NewInMemoryBus()
+	worker := testWorker(server.URL, jobs, bus)
+	var completed []contractsevents.VideoRunStepCompletedV1
+	collect(t, bus, "video.run.step.completed.v1", &completed)
+
+	if err := worker.Steps.Handle(context.Background(), stepCommand(t, "nim", map[string]string{"script": "Once upon a time"})); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if submits.Load() != 1 || len(completed) != 1 || completed[0].Artifacts["job_id"] != "job-1" {
+		t.Fatalf("unexpected result: submits=%d completed=%+v", submits.Load(), completed)
+	}
+	record, found, _ := jobs.Find(context.Background(), "run-1", 1)
+	if !found || record.Status != generatorprovider.JobSucceeded || record.Progress != 100 || record.Provider != "nvidia_nim" {
+		t.Fatalf("unexpected job record: %+v", record)
+	}
+}
+
+func TestVideoStepResumesExistingJobAfterRestart(t *testing.T) {
+	server, submits := newCountingNIMServer(t)
+	jobs := newMemoryJobStore()
+	_ = jobs.Save(context.Background(), jobRecord{RunID: "run-1", StepIndex: 1, JobID: "job-previous", Provider: "nvidia_nim", Status: generatorprovider.JobRunning, Progress: 40})
+	bus := queue.NewInMemoryBus()
+	worker := testWorker(server.URL, jobs, bus)
+	var completed []contractsevents.VideoRunStepCompletedV1
+	collect(t, bus, "video.run.step.completed.v1", &completed)
+
+	if err := worker.Steps.Handle(context.Background(), stepCommand(t, "nim", map[string]string{"script": "Once upon a time"})); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if submits.Load() != 0 {
+		t.Fatalf("expected redelivered step to resume, got %d submits", submits.Load())
+	}
+	if len(completed) != 1 || completed[0].Artifacts["job_id"] != "job-previous" {
+		t.Fatalf("unexpected completion: %+v", completed)
+	}
+}
+
+func TestJobUpdateWakesWaiter(t *testing.T) {
+	g := &generator{}
+	worker := newWorker(g, workerruntime.WithBus(queue.NewInMemoryBus()), workerruntime.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))))
+	wake, release := g.waiters.wait("job-1")
+	defer release()
+	payload, _ := json.Marshal(contractsevents.VideoRunJobUpdatedV1{RunID: "run-1", JobID: "job-1", Status: "succeeded", Progress: 100, UpdatedAt: "2026-03-11T10:01:00Z"})
+	if err := worker.JobUpdates.Handle(context.Background(), queue.Event{ID: "update-1", Topic: "video.run.job.updated.v1", Payload: payload}); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	select {
+	case <-wake:
+	case <-time.After(time.Second):
+		t.Fatal("expected job update to wake the waiting step")
+	}
+}
+
+type providerProfile string
+
+func (p providerProfile) GetProfile(string) (generatorprovider.ModelProfile, error) {
+	return generatorprovider.ModelProfile{Provider: string(p), BaseURL: "http://localhost:9100/fake-assets", ModelID: "fake-v1"}, nil
+}
+
+func TestVideoStepRunsOnLocalFakeProvider(t *testing.T) {
+	t.Setenv("GEN_FAKE_OUTPUT_DIR", t.TempDir())
+	bus := queue.NewInMemoryBus()
+	worker := testWorkerWithProfiles(providerProfile("local_fake"), newMemoryJobStore(), bus)
+	var completed []contractsevents.VideoRunStepCompletedV1
+	collect(t, bus, "video.run.step.completed.v1", &completed)
+
+	if err := worker.Steps.Handle(context.Background(), stepCommand(t, "nim", map[string]string{"script": "Once upon a time"})); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if len(completed) != 1 || !strings.HasPrefix(completed[0].Artifacts["source_url"], "http://localhost:9100/fake-assets/fake-") || completed[0].Artifacts["provider"] != "local_fake" {
+		t.Fatalf("unexpected completion: %+v", completed)
+	}
+}
+
+func TestSubmitKeyIsStablePerStepAndChangesAfterFailedJob(t *testing.T) {
+	req := stepRequest{StepIndex: 2}
+	req.RunID = "run-1"
+	if key := submitKey(req, jobRecord{}, false); key != "run-1:2" {
+		t.Fatalf("unexpected first submit key %q", key)
+	}
+	if key := submitKey(req, jobRecord{JobID: "job-failed"}, true); key != "run-1:2:job-failed" {
+		t.Fatalf("expected resubmission after a failed job to use a new key, got %q", key)
+	}
+}
+
+func TestVideoStepUsesSyncEndpointWhenJobsAreUnsupported(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/generate/video", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"asset_id":"asset-sync","source_url":"https://cdn.example/asset-sync.mp4","duration_ms":60000}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	jobs := newMemoryJobStore()
+	bus := queue.NewInMemoryBus()
+	worker := testWorker(server.URL, jobs, bus)
+	var completed []contractsevents.VideoRunStepCompletedV1
+	collect(t, bus, "video.run.step.completed.v1", &completed)
+
+	if err := worker.Steps.Handle(context.Background(), stepCommand(t, "nim", map[string]string{"script": "Once upon a time"})); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if len(completed) != 1 || completed[0].Artifacts["source_url"] != "https://cdn.example/asset-sync.mp4" {
+		t.Fatalf("unexpected completion: %+v", completed)
+	}
+	if _, found, _ := jobs.Find(context.Background(), "run-1", 1); found {
+		t.Fatalf("a synchronous result must not be recorded as a resumable job")
+	}
+}
@@ -1,30 +1,24 @@
 package main
 
 import (
-	"context"
-	"log"
-	"log/slog"
-	"os"
-	"os/signal"
-	"syscall"
-
-	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
 	"github.com/delqhi/mikasmissions/platform/workers/worker-gen-orchestrator/internal"
 )
 
 func main() {
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	bus, err := queue.NewBusFromEnv()
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer bus.Close()
-	processor := internal.NewProcessor(bus, logger)
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
-	if err := bus.Subscribe(ctx, processor.Topic(), processor.Consumer(), processor.Handle); err != nil {
-		log.Fatal(err)
-	}
-	logger.Info("worker started", "worker", processor.Consumer(), "topic", processor.Topic())
-	<-ctx.Done()
+	workerruntime.Main("worker-gen-orchestrator", func(app *workerruntime.App) error {
+		orchestrator := internal.NewOrchestrator(app.Options()...)
+		app.OnClose(orchestrator)
+		app.AddReadinessCheck("database", orchestrator.Ping)
+		if err := app.Subscribe(orchestrator.Requested); err != nil {
+			return err
+		}
+		if err := app.Subscribe(orchestrator.StepCompleted); err != nil {
+			return err
+		}
+		if err := app.Subscribe(orchestrator.RunFailed); err != nil {
+			return err
+		}
+		return app.Subscribe(orchestrator.RunCancelled)
+	})
 }
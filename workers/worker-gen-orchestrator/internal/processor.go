@@ -2,69 +2,242 @@ package internal
 
 import (
 	"context"
-	"encoding/json"
-	"log/slog"
+	"fmt"
+	"strings"
 	"time"
 
 	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
 	"github.com/delqhi/mikasmissions/platform/libs/generatorrunstore"
-	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
 	"github.com/google/uuid"
 )
 
-type Processor struct {
-	bus    queue.Bus
-	guard  *queue.IdempotencyGuard
-	logger *slog.Logger
+const consumer = "worker-gen-orchestrator"
+
+type statusSetter func(ctx context.Context, runID, status, lastError string) error
+
+type Orchestrator struct {
+	store         stateStore
+	runStatus     generatorrunstore.StatusFunc
+	setStatus     statusSetter
+	Requested     *workerruntime.Processor[contractsevents.VideoRunRequestedV1]
+	StepCompleted *workerruntime.Processor[contractsevents.VideoRunStepCompletedV1]
+	RunFailed     *workerruntime.Processor[contractsevents.VideoRunFailedV1]
+	RunCancelled  *workerruntime.Processor[contractsevents.VideoRunCancelledV1]
+}
+
+func NewOrchestrator(opts ...workerruntime.Option) *Orchestrator {
+	return newOrchestrator(newStateStoreFromEnv(), generatorrunstore.RunStatus, generatorrunstore.SetRunStatus, opts...)
 }
 
-func NewProcessor(bus queue.Bus, logger *slog.Logger) *Processor {
-	return &Processor{bus: bus, guard: queue.NewScopedIdempotencyGuard("worker-gen-orchestrator"), logger: logger}
+func newOrchestrator(store stateStore, runStatus generatorrunstore.StatusFunc, setStatus statusSetter, opts ...workerruntime.Option) *Orchestrator {
+	o := &Orchestrator{store: store, runStatus: runStatus, setStatus: setStatus}
+	o.Requested = workerruntime.NewProcessor(consumer, "video.run.requested.v1", o.start, opts...)
+	o.StepCompleted = workerruntime.NewProcessor(consumer+"-steps", "video.run.step.completed.v1", o.advance, opts...)
+	o.RunFailed = workerruntime.NewProcessor(consumer+"-failures", "video.run.failed.v1", o.fail, opts...)
+	o.RunCancelled = workerruntime.NewProcessor(consumer+"-cancellations", "video.run.cancelled.v1", o.cancel, opts...)
+	return o
+}
+
+func (o *Orchestrator) Ping(ctx context.Context) error {
+	if checker, ok := o.store.(interface{ Ping(context.Context) error }); ok {
+		return checker.Ping(ctx)
+	}
+	return nil
 }
 
-func (p *Processor) Topic() string {
-	return "video.run.requested.v1"
+func (o *Orchestrator) Close() error {
+	if closer, ok := o.store.(interface{ Close() error }); ok {
+		return closer.Close()
+	}
+	return nil
 }
 
-func (p *Processor) Consumer() string {
-	return "worker-gen-orchestrator"
+func (o *Orchestrator) start(ctx context.Context, incoming contractsevents.VideoRunRequestedV1) ([]workerruntime.Output, error) {
+	state := runState{RunID: incoming.RunID, Request: incoming, Status: runRunning, Artifacts: map[string]string{}}
+	steps, resolveErr := resolveSteps(incoming.Steps)
+	state.Steps = steps
+	if resolveErr != nil {
+		state.Status = runFailed
+	}
+	status, _ := o.runStatus(ctx, incoming.RunID)
+	if status == "cancelled" {
+		state.Status = runCancelled
+	}
+	began, err := o.store.Begin(ctx, state, status == "requested" || status == "")
+	if err != nil {
+		return nil, err
+	}
+	if !began {
+		return o.redispatch(ctx, incoming.RunID, -1)
+	}
+	if state.Status == runCancelled {
+		_ = generatorrunstore.AppendRunLog(ctx, incoming.RunID, "orchestrator", "cancelled", "run cancelled before the first step")
+		return nil, nil
+	}
+	if resolveErr != nil {
+		_ = o.setStatus(ctx, incoming.RunID, "failed", resolveErr.Error())
+		_ = generatorrunstore.AppendRunLog(ctx, incoming.RunID, "orchestrator", "failed", resolveErr.Error())
+		return []workerruntime.Output{workerruntime.Emit("video.run.failed.v1", contractsevents.VideoRunFailedV1{
+			RunID:        incoming.RunID,
+			Step:         "orchestrator",
+			ErrorCode:    "workflow_step_unsupported",
+			ErrorMessage: resolveErr.Error(),
+			FailedAt:     time.Now().UTC().Format(time.RFC3339),
+		})}, nil
+	}
+	_ = o.setStatus(ctx, incoming.RunID, "running", "")
+	_ = generatorrunstore.AppendRunLog(ctx, incoming.RunID, "orchestrator", "scheduled", "generation pipeline scheduled: "+strings.Join(steps, " -> "))
+	return []workerruntime.Output{dispatch(state)}, nil
 }
 
-func (p *Processor) Handle(ctx context.Context, event queue.Event) error {
-	if p.guard.Seen(event.ID) {
-		p.logger.Info("duplicate event ignored", "worker", p.Consumer(), "event_id", event.ID)
-		return nil
+func (o *Orchestrator) advance(ctx context.Context, incoming contractsevents.VideoRunStepCompletedV1) ([]workerruntime.Output, error) {
+	state, ok, err := o.expect(ctx, incoming.RunID, incoming.Step, incoming.StepIndex)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return o.redispatch(ctx, incoming.RunID, incoming.StepIndex)
+	}
+	from := state.Current
+	for key, value := range incoming.Artifacts {
+		state.Artifacts[key] = value
+	}
+	state.Current++
+	if state.Current == len(state.Steps) {
+		state.Status = runCompleted
+	} else if o.cancelled(ctx, state.RunID) {
+		state.Status = runCancelled
 	}
-	if event.Topic != p.Topic() {
-		p.logger.Info("unexpected topic skipped", "worker", p.Consumer(), "topic", event.Topic)
-		return nil
+	advanced, err := o.store.Advance(ctx, state, from)
+	if err != nil || !advanced {
+		return nil, err
 	}
-	var incoming contractsevents.VideoRunRequestedV1
-	if err := json.Unmarshal(event.Payload, &incoming); err != nil {
-		return err
+	if state.Status == runCompleted {
+		if err := o.finish(ctx, state); err != nil {
+			return nil, err
+		}
+		_ = generatorrunstore.AppendRunLog(ctx, state.RunID, "orchestrator", "completed", fmt.Sprintf("all %d steps completed", len(state.Steps)))
+		return nil, nil
 	}
-	if err := incoming.Validate(); err != nil {
-		return err
+	if state.Status == runCancelled {
+		_ = generatorrunstore.AppendRunLog(ctx, state.RunID, "orchestrator", "cancelled", "run cancelled before step "+state.currentStep())
+		return nil, nil
 	}
-	payload, err := json.Marshal(contractsevents.VideoRunStepCompletedV1{
-		RunID:       incoming.RunID,
-		Step:        "orchestrator",
-		Status:      "completed",
-		Details:     "generation pipeline scheduled",
-		CompletedAt: time.Now().UTC().Format(time.RFC3339),
-	})
+	return []workerruntime.Output{dispatch(state)}, nil
+}
+
+func (o *Orchestrator) cancel(ctx context.Context, incoming contractsevents.VideoRunCancelledV1) ([]workerruntime.Output, error) {
+	state, found, err := o.store.Load(ctx, incoming.RunID)
+	if err != nil || !found || state.Status != runRunning {
+		return nil, err
+	}
+	from := state.Current
+	state.Status = runCancelled
+	cancelled, err := o.store.Advance(ctx, state, from)
+	if err != nil || !cancelled {
+		return nil, err
+	}
+	_ = generatorrunstore.AppendRunLog(ctx, state.RunID, "orchestrator", "cancelled", "run cancelled during step "+state.currentStep())
+	return nil, nil
+}
+
+func (o *Orchestrator) cancelled(ctx context.Context, runID string) bool {
+	status, err := o.runStatus(ctx, runID)
+	return err == nil && status == "cancelled"
+}
+
+func (o *Orchestrator) fail(ctx context.Context, incoming contractsevents.VideoRunFailedV1) ([]workerruntime.Output, error) {
+	message := fmt.Sprintf("step %s failed: %s", incoming.Step, incoming.ErrorMessage)
+	state, ok, err := o.expect(ctx, incoming.RunID, incoming.Step, incoming.StepIndex)
 	if err != nil {
-		return err
-	}
-	if err := p.bus.Publish(ctx, queue.Event{
-		ID:      uuid.NewString(),
-		Topic:   "video.run.step.completed.v1",
-		Payload: payload,
-	}); err != nil {
-		return err
-	}
-	_ = generatorrunstore.SetRunStatus(ctx, incoming.RunID, "running", "")
-	_ = generatorrunstore.AppendRunLog(ctx, incoming.RunID, "orchestrator", "completed", "generation pipeline scheduled")
-	p.logger.Info("run orchestrated", "worker", p.Consumer(), "run_id", incoming.RunID)
+		return nil, err
+	}
+	if !ok {
+		state, found, err := o.store.Load(ctx, incoming.RunID)
+		if err != nil || !found || state.Status != runFailed || state.Current != incoming.StepIndex {
+			return nil, err
+		}
+		return nil, o.markFailed(ctx, state.RunID, message)
+	}
+	from := state.Current
+	state.Status = runFailed
+	failed, err := o.store.Advance(ctx, state, from)
+	if err != nil || !failed {
+		return nil, err
+	}
+	if err := o.markFailed(ctx, state.RunID, message); err != nil {
+		return nil, err
+	}
+	_ = generatorrunstore.AppendRunLog(ctx, state.RunID, "orchestrator", "failed", message)
+	return nil, nil
+}
+
+func (o *Orchestrator) redispatch(ctx context.Context, runID string, stepIndex int) ([]workerruntime.Output, error) {
+	state, found, err := o.store.Load(ctx, runID)
+	if err != nil || !found {
+		return nil, err
+	}
+	if state.Status == runCompleted && stepIndex == len(state.Steps)-1 {
+		return nil, o.finish(ctx, state)
+	}
+	if state.Status != runRunning || state.Current <= stepIndex {
+		return nil, nil
+	}
+	return []workerruntime.Output{dispatch(state)}, nil
+}
+
+func (o *Orchestrator) finish(ctx context.Context, state runState) error {
+	if err := o.setStatus(ctx, state.RunID, finishedStatus(state.Steps), ""); err != nil {
+		return fmt.Errorf("mark run %s finished: %w", state.RunID, err)
+	}
+	return nil
+}
+
+func (o *Orchestrator) markFailed(ctx context.Context, runID, message string) error {
+	if err := o.setStatus(ctx, runID, "failed", message); err != nil {
+		return fmt.Errorf("mark run %s failed: %w", runID, err)
+	}
 	return nil
 }
+
+func finishedStatus(steps []string) string {
+	if len(steps) > 0 && steps[len(steps)-1] == "publish" {
+		return "publish_queued"
+	}
+	return "completed"
+}
+
+func (o *Orchestrator) expect(ctx context.Context, runID, step string, stepIndex int) (runState, bool, error) {
+	state, found, err := o.store.Load(ctx, runID)
+	if err != nil || !found {
+		return runState{}, false, err
+	}
+	if state.Status != runRunning || state.Current != stepIndex || state.currentStep() != step {
+		return runState{}, false, nil
+	}
+	return state, true, nil
+}
+
+func dispatch(state runState) workerruntime.Output {
+	request := state.Request
+	return workerruntime.Emit("video.run.step.requested.v1", contractsevents.VideoRunStepRequestedV1{
+		RunID:              state.RunID,
+		WorkflowID:         request.WorkflowID,
+		Step:               state.currentStep(),
+		StepIndex:          state.Current,
+		ModelProfileID:     request.ModelProfileID,
+		InputPayload:       request.InputPayload,
+		AutoPublish:        request.AutoPublish,
+		ContentSuitability: request.ContentSuitability,
+		AgeBand:            request.AgeBand,
+		RequestedBy:        request.RequestedBy,
+		Artifacts:          state.Artifacts,
+		RequestedAt:        time.Now().UTC().Format(time.RFC3339),
+	}).WithID(dispatchID(state.RunID, request.RequestedAt, state.Current))
+}
+
+func dispatchID(runID, requestedAt string, stepIndex int) string {
+	name := fmt.Sprintf("video.run.step.requested.v1/%s/%s/%d", runID, requestedAt, stepIndex)
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(name)).String()
+}
@@ -0,0 +1,229 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
+)
+
+type harness struct {
+	t            *testing.T
+	bus          *queue.InMemoryBus
+	store        *memoryStateStore
+	orchestrator *Orchestrator
+	commands     []contractsevents.VideoRunStepRequestedV1
+	dispatches   map[string]int
+	seq          int
+	status       string
+	statuses     []string
+	statusErr    error
+}
+
+func (h *harness) runStatus(context.Context, string) (string, error) {
+	return h.status, nil
+}
+
+func (h *harness) setStatus(_ context.Context, _ string, status, _ string) error {
+	if h.statusErr != nil {
+		return h.statusErr
+	}
+	h.statuses = append(h.statuses, status)
+	return nil
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+	h := &harness{t: t, bus: queue.NewInMemoryBus(), store: newMemoryStateStore(), dispatches: map[string]int{}}
+	h.orchestrator = newOrchestrator(h.store, h.runStatus, h.setStatus, workerruntime.WithBus(h.bus), workerruntime.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))))
+	if err := h.bus.Subscribe(context.Background(), "video.run.step.requested.v1", "test-commands", func(_ context.Context, event queue.Event) error {
+		h.dispatches[event.ID]++
+		if h.dispatches[event.ID] > 1 {
+			return nil
+		}
+		var command contractsevents.VideoRunStepRequestedV1
+		if err := json.Unmarshal(event.Payload, &command); err != nil {
+			return err
+		}
+		h.commands = append(h.commands, command)
+		return nil
+	}); err != nil {
+		t.Fatalf("subscribe commands: %v", err)
+	}
+	return h
+}
+
+func (h *harness) deliver(handle func(context.Context, queue.Event) error, topic string, payload any) {
+	h.t.Helper()
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		h.t.Fatalf("marshal: %v", err)
+	}
+	h.seq++
+	if err := handle(context.Background(), queue.Event{ID: fmt.Sprintf("%s-%d", topic, h.seq), Topic: topic, Payload: raw}); err != nil {
+		h.t.Fatalf("handle %s: %v", topic, err)
+	}
+	testkit.WaitBusIdle(h.t, h.bus)
+}
+
+func (h *harness) request(steps ...string) {
+	h.deliver(h.orchestrator.Requested.Handle, "video.run.requested.v1", contractsevents.VideoRunRequestedV1{
+		RunID:              "run-1",
+		WorkflowID:         "wf-1",
+		ModelProfileID:     "nim-default",
+		InputPayload:       json.RawMessage(`{"theme":"space"}`),
+		Priority:           "normal",
+		ContentSuitability: "core",
+		AgeBand:            "6-11",
+		Steps:              steps,
+		RequestedBy:        "admin-1",
+		RequestedAt:        fmt.Sprintf("2026-03-11T10:00:%02dZ", h.seq),
+		TraceID:            "run-1",
+	})
+}
+
+func (h *harness) complete(step string, index int, artifacts map[string]string) {
+	h.deliver(h.orchestrator.StepCompleted.Handle, "video.run.step.completed.v1", contractsevents.VideoRunStepCompletedV1{
+		RunID: "run-1", Step: step, StepIndex: index, Status: "completed", Artifacts: artifacts, CompletedAt: "2026-03-11T10:01:00Z",
+	})
+}
+
+func TestOrchestratorDispatchesTemplateStepsInOrder(t *testing.T) {
+	h := newHarness(t)
+	h.request("prompt", "voice", "generate", "qc", "publish")
+	h.complete("script", 0, map[string]string{"script": "Once upon a time"})
+	h.complete("voice", 1, map[string]string{"voice_url": "https://cdn.example/voice.mp3"})
+	h.complete("nim", 2, map[string]string{"asset_id": "asset-1"})
+	h.complete("qc", 3, nil)
+
+	want := []string{"script", "voice", "nim", "qc", "publish"}
+	if len(h.commands) != len(want) {
+		t.Fatalf("expected %d step commands, got %+v", len(want), h.commands)
+	}
+	for i, step := range want {
+		if h.commands[i].Step != step || h.commands[i].StepIndex != i {
+			t.Fatalf("command %d: expected %s, got %+v", i, step, h.commands[i])
+		}
+	}
+	last := h.commands[len(h.commands)-1]
+	if last.Artifacts["script"] == "" || last.Artifacts["asset_id"] != "asset-1" {
+		t.Fatalf("expected artifacts to accumulate, got %v", last.Artifacts)
+	}
+	h.complete("publish", 4, nil)
+	state, _, _ := h.store.Load(context.Background(), "run-1")
+	if state.Status != runCompleted || h.statuses[len(h.statuses)-1] != "publish_queued" {
+		t.Fatalf("expected run to finish as publish_queued, got %+v statuses=%v", state, h.statuses)
+	}
+}
+
+func TestOrchestratorRetriesFinalStatusWriteOnRedelivery(t *testing.T) {
+	h := newHarness(t)
+	h.request("nim", "qc")
+	h.complete("nim", 0, nil)
+	h.statusErr = errors.New("database unavailable")
+	raw, _ := json.Marshal(contractsevents.VideoRunStepCompletedV1{RunID: "run-1", Step: "qc", StepIndex: 1, Status: "completed", CompletedAt: "2026-03-11T10:02:00Z"})
+	event := queue.Event{ID: "qc-completed", Topic: "video.run.step.completed.v1", Payload: raw}
+	if err := h.orchestrator.StepCompleted.Handle(context.Background(), event); err == nil {
+		t.Fatalf("expected a failed status write to surface for redelivery")
+	}
+	h.statusErr = nil
+	if err := h.orchestrator.StepCompleted.Handle(context.Background(), event); err != nil {
+		t.Fatalf("redelivery: %v", err)
+	}
+	if len(h.statuses) == 0 || h.statuses[len(h.statuses)-1] != "completed" {
+		t.Fatalf("expected redelivery to record the completed status, got %v", h.statuses)
+	}
+}
+
+func TestOrchestratorIgnoresStaleAndDuplicateCompletions(t *testing.T) {
+	h := newHarness(t)
+	h.request()
+	h.request()
+	h.complete("qc", 1, nil)
+	h.complete("nim", 0, nil)
+	h.complete("nim", 0, nil)
+	if len(h.commands) != 2 || h.commands[1].Step != "qc" {
+		t.Fatalf("expected nim then qc commands once, got %+v", h.commands)
+	}
+}
+
+func TestOrchestratorRedispatchesPersistedStepOnRedelivery(t *testing.T) {
+	h := newHarness(t)
+	h.request("nim", "qc")
+	h.status = "running"
+	h.request("nim", "qc")
+	h.complete("nim", 0, map[string]string{"asset_id": "asset-1"})
+	h.complete("nim", 0, map[string]string{"asset_id": "asset-1"})
+	if len(h.commands) != 2 || h.commands[1].Step != "qc" {
+		t.Fatalf("expected nim then qc commands, got %+v", h.commands)
+	}
+	state, _, _ := h.store.Load(context.Background(), "run-1")
+	requestedAt := state.Request.RequestedAt
+	if h.dispatches[dispatchID("run-1", requestedAt, 0)] != 2 || h.dispatches[dispatchID("run-1", requestedAt, 1)] != 2 {
+		t.Fatalf("expected redeliveries to re-emit the same dispatch ids, got %v", h.dispatches)
+	}
+	h.status = "completed"
+	h.complete("qc", 1, nil)
+	h.request("nim", "qc")
+	if state, _, _ := h.store.Load(context.Background(), "run-1"); state.Status != runCompleted || len(h.commands) != 2 {
+		t.Fatalf("expected redelivered request not to restart a finished run, got %+v commands=%d", state, len(h.commands))
+	}
+}
+
+func TestOrchestratorStopsOnStepFailureAndRestartsOnRetry(t *testing.T) {
+	h := newHarness(t)
+	h.request("nim", "qc")
+	h.deliver(h.orchestrator.RunFailed.Handle, "video.run.failed.v1", contractsevents.VideoRunFailedV1{
+		RunID: "run-1", Step: "nim", ErrorCode: "nim_provider_error", ErrorMessage: "timeout", FailedAt: "2026-03-11T10:01:00Z",
+	})
+	h.complete("nim", 0, nil)
+	if state, _, _ := h.store.Load(context.Background(), "run-1"); state.Status != runFailed || len(h.commands) != 1 {
+		t.Fatalf("expected failed run with no further commands, got %+v commands=%d", state, len(h.commands))
+	}
+	h.status = "requested"
+	h.request("nim", "qc")
+	if len(h.commands) != 2 || h.commands[1].Step != "nim" {
+		t.Fatalf("expected retry to restart at nim, got %+v", h.commands)
+	}
+}
+
+func TestResolveStepsRejectsUnsupportedOrUnorderedSteps(t *testing.T) {
+	if _, err := resolveSteps([]string{"nim", "upscale"}); err == nil {
+		t.Fatalf("expected unsupported step error")
+	}
+	if _, err := resolveSteps([]string{"qc", "nim"}); err == nil {
+		t.Fatalf("expected ordering error for qc before nim")
+	}
+	steps, err := resolveSteps(nil)
+	if err != nil || len(steps) != len(defaultSteps) {
+		t.Fatalf("expected default steps, got %v %v", steps, err)
+	}
+}
+
+func TestOrchestratorStopsDispatchingAfterCancellation(t *testing.T) {
+	h := newHarness(t)
+	h.request("script", "nim", "qc", "publish")
+	h.deliver(h.orchestrator.RunCancelled.Handle, "video.run.cancelled.v1", contractsevents.VideoRunCancelledV1{
+		RunID: "run-1", CancelledBy: "admin-1", CancelledAt: "2026-03-11T10:00:30Z",
+	})
+	h.complete("script", 0, nil)
+	if state, _, _ := h.store.Load(context.Background(), "run-1"); state.Status != runCancelled || len(h.commands) != 1 {
+		t.Fatalf("expected cancelled run with no further commands, got %+v commands=%d", state, len(h.commands))
+	}
+
+	h.status = "requested"
+	h.request("script", "nim")
+	h.status = "cancelled"
+	h.complete("script", 0, nil)
+	if state, _, _ := h.store.Load(context.Background(), "run-1"); state.Status != runCancelled || len(h.commands) != 2 {
+		t.Fatalf("expected stored cancelled status to stop the next step, got %+v commands=%d", state, len(h.commands))
+	}
+}
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/runtimecfg"
+)
+
+const (
+	runRunning   = "running"
+	runCompleted = "completed"
+	runFailed    = "failed"
+	runCancelled = "cancelled"
+)
+
+type runState struct {
+	RunID     string
+	Request   contractsevents.VideoRunRequestedV1
+	Steps     []string
+	Current   int
+	Status    string
+	Artifacts map[string]string
+}
+
+func (s runState) currentStep() string {
+	if s.Current < 0 || s.Current >= len(s.Steps) {
+		return ""
+	}
+	return s.Steps[s.Current]
+}
+
+type stateStore interface {
+	Begin(ctx context.Context, state runState, restart bool) (bool, error)
+	Load(ctx context.Context, runID string) (runState, bool, error)
+	Advance(ctx context.Context, state runState, fromStep int) (bool, error)
+}
+
+func newStateStoreFromEnv() stateStore {
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL != "" {
+		store, err := newPostgresStateStore(databaseURL)
+		if err == nil {
+			return store
+		}
+		if runtimecfg.PersistentStorageRequired() {
+			panic(err)
+		}
+	}
+	if runtimecfg.PersistentStorageRequired() {
+		panic("DATABASE_URL is required for worker-gen-orchestrator in strict persistence mode")
+	}
+	return newMemoryStateStore()
+}
+
+type memoryStateStore struct {
+	mu   sync.Mutex
+	runs map[string]runState
+}
+
+func newMemoryStateStore() *memoryStateStore {
+	return &memoryStateStore{runs: map[string]runState{}}
+}
+
+func (s *memoryStateStore) Begin(_ context.Context, state runState, restart bool) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.runs[state.RunID]; ok && (!restart || existing.Status == runRunning) {
+		return false, nil
+	}
+	s.runs[state.RunID] = cloneState(state)
+	return true, nil
+}
+
+func (s *memoryStateStore) Load(_ context.Context, runID string) (runState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.runs[runID]
+	return cloneState(state), ok, nil
+}
+
+func (s *memoryStateStore) Advance(_ context.Context, state runState, fromStep int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, ok := s.runs[state.RunID]
+	if !ok || existing.Status != runRunning || existing.Current != fromStep {
+		return false, nil
+	}
+	s.runs[state.RunID] = cloneState(state)
+	return true, nil
+}
+
+func cloneState(state runState) runState {
+	state.Steps = append([]string(nil), state.Steps...)
+	artifacts := make(map[string]string, len(state.Artifacts))
+	for key, value := range state.Artifacts {
+		artifacts[key] = value
+	}
+	state.Artifacts = artifacts
+	return state
+}
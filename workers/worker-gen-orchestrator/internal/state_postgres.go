@@ -0,0 +1,138 @@
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+type postgresStateStore struct {
+	db *sql.DB
+}
+
+func newPostgresStateStore(databaseURL string) (*postgresStateStore, error) {
+	db, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("open postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("ping postgres: %w", err)
+	}
+	return &postgresStateStore{db: db}, nil
+}
+
+func (s *postgresStateStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *postgresStateStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *postgresStateStore) Begin(ctx context.Context, state runState, restart bool) (bool, error) {
+	request, steps, artifacts, err := encodeState(state)
+	if err != nil {
+		return false, err
+	}
+	result, err := s.db.ExecContext(
+		ctx,
+		`insert into creator.workflow_run_state (run_id, request, steps, current_step, status, artifacts, updated_at)
+		 values ($1::uuid, $2, $3, $4, $5, $6, now())
+		 on conflict (run_id) do update
+		 set request = excluded.request,
+		     steps = excluded.steps,
+		     current_step = excluded.current_step,
+		     status = excluded.status,
+		     artifacts = excluded.artifacts,
+		     updated_at = now()
+		 where $7 and creator.workflow_run_state.status <> 'running'`,
+		state.RunID, request, steps, state.Current, state.Status, artifacts, restart,
+	)
+	if err != nil {
+		return false, fmt.Errorf("begin run state: %w", err)
+	}
+	return rowsChanged(result)
+}
+
+func (s *postgresStateStore) Load(ctx context.Context, runID string) (runState, bool, error) {
+	state := runState{RunID: runID}
+	var request, steps, artifacts []byte
+	err := s.db.QueryRowContext(
+		ctx,
+		`select request, steps, current_step, status, artifacts
+		 from creator.workflow_run_state
+		 where run_id = $1::uuid`,
+		runID,
+	).Scan(&request, &steps, &state.Current, &state.Status, &artifacts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return runState{}, false, nil
+	}
+	if err != nil {
+		return runState{}, false, fmt.Errorf("load run state: %w", err)
+	}
+	if err := json.Unmarshal(request, &state.Request); err != nil {
+		return runState{}, false, fmt.Errorf("decode run request: %w", err)
+	}
+	if err := json.Unmarshal(steps, &state.Steps); err != nil {
+		return runState{}, false, fmt.Errorf("decode run steps: %w", err)
+	}
+	if err := json.Unmarshal(artifacts, &state.Artifacts); err != nil {
+		return runState{}, false, fmt.Errorf("decode run artifacts: %w", err)
+	}
+	return state, true, nil
+}
+
+func (s *postgresStateStore) Advance(ctx context.Context, state runState, fromStep int) (bool, error) {
+	_, _, artifacts, err := encodeState(state)
+	if err != nil {
+		return false, err
+	}
+	result, err := s.db.ExecContext(
+		ctx,
+		`update creator.workflow_run_state
+		 set current_step = $2,
+		     status = $3,
+		     artifacts = $4,
+		     updated_at = now()
+		 where run_id = $1::uuid
+		   and current_step = $5
+		   and status = 'running'`,
+		state.RunID, state.Current, state.Status, artifacts, fromStep,
+	)
+	if err != nil {
+		return false, fmt.Errorf("advance run state: %w", err)
+	}
+	return rowsChanged(result)
+}
+
+func encodeState(state runState) ([]byte, []byte, []byte, error) {
+	request, err := json.Marshal(state.Request)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("encode run request: %w", err)
+	}
+	steps, err := json.Marshal(state.Steps)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("encode run steps: %w", err)
+	}
+	if state.Artifacts == nil {
+		state.Artifacts = map[string]string{}
+	}
+	artifacts, err := json.Marshal(state.Artifacts)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("encode run artifacts: %w", err)
+	}
+	return request, steps, artifacts, nil
+}
+
+func rowsChanged(result sql.Result) (bool, error) {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("run state rows affected: %w", err)
+	}
+	return affected > 0, nil
+}
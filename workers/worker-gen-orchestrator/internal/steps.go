@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+var defaultSteps = []string{"nim", "qc", "publish"}
+
+var stepRequires = map[string]string{
+	"qc":      "nim",
+	"publish": "qc",
+}
+
+var stepAliases = map[string]string{
+	"script":   "script",
+	"prompt":   "script",
+	"voice":    "voice",
+	"tts":      "voice",
+	"nim":      "nim",
+	"generate": "nim",
+	"qc":       "qc",
+	"publish":  "publish",
+}
+
+func resolveSteps(raw []string) ([]string, error) {
+	if len(raw) == 0 {
+		return append([]string(nil), defaultSteps...), nil
+	}
+	steps := make([]string, 0, len(raw))
+	seen := map[string]bool{}
+	for _, name := range raw {
+		step, ok := stepAliases[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unsupported workflow step %q", name)
+		}
+		if required := stepRequires[step]; required != "" && !seen[required] {
+			return nil, fmt.Errorf("workflow step %q requires an earlier %q step", step, required)
+		}
+		seen[step] = true
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
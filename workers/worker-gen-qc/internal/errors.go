@@ -3,6 +3,8 @@ package internal
 import "errors"
 
 var (
-	errInvalidSourceURL = errors.New("source_url must be absolute http(s) url")
+	errMissingAsset     = errors.New("asset_id artifact is missing; run a nim step first")
+	errInvalidSourceURL = errors.New("source_url must be absolute http(s) url (file:// only from local_fake)")
 	errInvalidDuration  = errors.New("duration_ms outside qc bounds")
+	errUnknownDuration  = errors.New("duration_ms is unknown; the provider did not report the video duration")
 )
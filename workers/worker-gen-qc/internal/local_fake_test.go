@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+
+	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/generatorprovider"
+	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
+)
+
+func TestLocalFakeVideoPassesQCAndPublish(t *testing.T) {
+	provider := generatorprovider.NewFakeProvider(t.TempDir(), "")
+	req := generatorprovider.GenerateRequest{RunID: "run-1", InputPayload: json.RawMessage(`{"theme":"space"}`)}
+	job, err := provider.SubmitVideo(context.Background(), req)
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	video, err := provider.FetchVideo(context.Background(), job.ID)
+	if err != nil || !strings.HasPrefix(video.SourceURL, "file://") {
+		t.Fatalf("expected a file:// fake video, got %+v %v", video, err)
+	}
+	artifacts := map[string]string{
+		"provider":    "local_fake",
+		"job_id":      job.ID,
+		"asset_id":    video.AssetID,
+		"source_url":  video.SourceURL,
+		"duration_ms": strconv.FormatInt(video.DurationMS, 10),
+	}
+
+	bus := queue.NewInMemoryBus()
+	processor := newWorker(noCancellations(), workerruntime.WithBus(bus), workerruntime.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil)))).Steps
+	uploaded, completed, failed := 0, 0, 0
+	countTopic(t, bus, "media.uploaded.v1", &uploaded)
+	countTopic(t, bus, "video.run.step.completed.v1", &completed)
+	countTopic(t, bus, "video.run.failed.v1", &failed)
+	for index, step := range []string{"qc", "publish"} {
+		if err := processor.Handle(context.Background(), fakeStepEvent(t, step, index+1, artifacts)); err != nil {
+			t.Fatalf("handle %s: %v", step, err)
+		}
+	}
+	testkit.WaitBusIdle(t, bus)
+	if completed != 2 || uploaded != 1 || failed != 0 {
+		t.Fatalf("expected local_fake output to pass qc and publish, got completed=%d uploaded=%d failed=%d", completed, uploaded, failed)
+	}
+
+	artifacts["provider"] = "nvidia_nim"
+	if err := processor.Handle(context.Background(), fakeStepEvent(t, "qc", 1, artifacts)); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if failed != 1 {
+		t.Fatalf("expected file:// from a remote provider to fail qc, got failed=%d", failed)
+	}
+}
+
+func fakeStepEvent(t *testing.T, step string, index int, artifacts map[string]string) queue.Event {
+	t.Helper()
+	payload, err := json.Marshal(contractsevents.VideoRunStepRequestedV1{
+		RunID:              "run-1",
+		WorkflowID:         "wf-1",
+		Step:               step,
+		StepIndex:          index,
+		ModelProfileID:     "local-fake",
+		InputPayload:       json.RawMessage(`{"theme":"space"}`),
+		ContentSuitability: "core",
+		AgeBand:            "6-11",
+		RequestedBy:        "admin-1",
+		Artifacts:          artifacts,
+		RequestedAt:        "2026-03-11T10:00:00Z",
+	})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return queue.Event{ID: "fake-" + step + "-" + artifacts["provider"], Topic: "video.run.step.requested.v1", Payload: payload}
+}
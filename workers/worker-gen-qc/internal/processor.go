@@ -2,122 +2,129 @@ package internal
 
 import (
 	"context"
-	"encoding/json"
-	"log/slog"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
 	"github.com/delqhi/mikasmissions/platform/libs/generatorrunstore"
-	"github.com/delqhi/mikasmissions/platform/libs/queue"
-	"github.com/google/uuid"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
 )
 
-type Processor struct {
-	bus    queue.Bus
-	guard  *queue.IdempotencyGuard
-	logger *slog.Logger
+type Worker struct {
+	Steps         *workerruntime.Processor[contractsevents.VideoRunStepRequestedV1]
+	Cancellations *workerruntime.Processor[contractsevents.VideoRunCancelledV1]
 }
 
-func NewProcessor(bus queue.Bus, logger *slog.Logger) *Processor {
-	return &Processor{bus: bus, guard: queue.NewScopedIdempotencyGuard("worker-gen-qc"), logger: logger}
+func NewWorker(opts ...workerruntime.Option) *Worker {
+	return newWorker(generatorrunstore.NewCancellations(0), opts...)
 }
 
-func (p *Processor) Topic() string {
-	return "video.asset.ready.v1"
+func newWorker(cancellations *generatorrunstore.Cancellations, opts ...workerruntime.Option) *Worker {
+	c := &checker{cancellations: cancellations}
+	return &Worker{
+		Steps:         workerruntime.NewProcessor("worker-gen-qc", "video.run.step.requested.v1", c.runStep, opts...),
+		Cancellations: workerruntime.NewProcessor("worker-gen-qc-cancellations", "video.run.cancelled.v1", c.cancel, append(slices.Clip(opts), workerruntime.WithLocalGuard())...),
+	}
 }
 
-func (p *Processor) Consumer() string {
-	return "worker-gen-qc"
+type checker struct {
+	cancellations *generatorrunstore.Cancellations
 }
 
-func (p *Processor) Handle(ctx context.Context, event queue.Event) error {
-	if p.guard.Seen(event.ID) {
-		p.logger.Info("duplicate event ignored", "worker", p.Consumer(), "event_id", event.ID)
-		return nil
-	}
-	if event.Topic != p.Topic() {
-		p.logger.Info("unexpected topic skipped", "worker", p.Consumer(), "topic", event.Topic)
-		return nil
+func (c *checker) cancel(_ context.Context, incoming contractsevents.VideoRunCancelledV1) ([]workerruntime.Output, error) {
+	c.cancellations.Cancel(incoming.RunID)
+	return nil, nil
+}
+
+func (c *checker) runStep(ctx context.Context, incoming contractsevents.VideoRunStepRequestedV1) ([]workerruntime.Output, error) {
+	if incoming.Step != "qc" && incoming.Step != "publish" {
+		return nil, nil
 	}
-	var incoming contractsevents.VideoAssetReadyV1
-	if err := json.Unmarshal(event.Payload, &incoming); err != nil {
-		return err
+	if c.cancellations.Cancelled(ctx, incoming.RunID) {
+		_ = generatorrunstore.AppendRunLog(ctx, incoming.RunID, incoming.Step, "cancelled", "step skipped: run cancelled")
+		return nil, nil
 	}
-	if err := incoming.Validate(); err != nil {
-		return err
+	if incoming.Step == "publish" {
+		return queueUpload(ctx, incoming), nil
 	}
-	if err := qcValidate(incoming); err != nil {
-		return p.publishFailed(ctx, incoming, "generation_qc_failed", err.Error())
+	return checkQuality(ctx, incoming), nil
+}
+
+func checkQuality(ctx context.Context, incoming contractsevents.VideoRunStepRequestedV1) []workerruntime.Output {
+	if err := qcValidate(incoming.Artifacts); err != nil {
+		return stepFailed(ctx, incoming, "generation_qc_failed", err.Error())
 	}
-	if err := p.publishMediaUploaded(ctx, incoming); err != nil {
-		return err
+	return []workerruntime.Output{stepCompleted(ctx, incoming, "qc checks passed")}
+}
+
+func queueUpload(ctx context.Context, incoming contractsevents.VideoRunStepRequestedV1) []workerruntime.Output {
+	if err := qcValidate(incoming.Artifacts); err != nil {
+		return stepFailed(ctx, incoming, "generation_publish_failed", err.Error())
 	}
-	if err := p.publishStepCompleted(ctx, incoming.RunID); err != nil {
-		return err
+	return []workerruntime.Output{
+		workerruntime.Emit("media.uploaded.v1", contractsevents.MediaUploadedV1{
+			AssetID:   incoming.Artifacts["asset_id"],
+			SourceURL: incoming.Artifacts["source_url"],
+			Uploader:  uploaderFromEvent(incoming),
+			TraceID:   incoming.RunID,
+		}),
+		stepCompleted(ctx, incoming, "upload queued"),
 	}
-	_ = generatorrunstore.SetRunStatus(ctx, incoming.RunID, "publish_queued", "")
-	_ = generatorrunstore.AppendRunLog(ctx, incoming.RunID, "qc", "completed", "qc checks passed and upload queued")
-	p.logger.Info("qc passed and media upload event emitted", "run_id", incoming.RunID, "asset_id", incoming.AssetID)
-	return nil
 }
 
-func qcValidate(event contractsevents.VideoAssetReadyV1) error {
-	if !strings.HasPrefix(event.SourceURL, "http://") && !strings.HasPrefix(event.SourceURL, "https://") {
+func qcValidate(artifacts map[string]string) error {
+	if artifacts["asset_id"] == "" {
+		return errMissingAsset
+	}
+	if !validSourceURL(artifacts["source_url"], artifacts["provider"]) {
 		return errInvalidSourceURL
 	}
-	if event.DurationMS < 30000 || event.DurationMS > 1800000 {
+	if raw := artifacts["duration_ms"]; raw == "" || raw == "0" {
+		return errUnknownDuration
+	}
+	durationMS, err := strconv.ParseInt(artifacts["duration_ms"], 10, 64)
+	if err != nil || durationMS < 30000 || durationMS > 1800000 {
 		return errInvalidDuration
 	}
 	return nil
 }
 
-func (p *Processor) publishMediaUploaded(ctx context.Context, incoming contractsevents.VideoAssetReadyV1) error {
-	payload, err := json.Marshal(contractsevents.MediaUploadedV1{
-		AssetID:   incoming.AssetID,
-		SourceURL: incoming.SourceURL,
-		Uploader:  uploaderFromEvent(incoming),
-		TraceID:   incoming.RunID,
-	})
-	if err != nil {
-		return err
+func validSourceURL(sourceURL, provider string) bool {
+	if strings.HasPrefix(sourceURL, "http://") || strings.HasPrefix(sourceURL, "https://") {
+		return true
 	}
-	return p.bus.Publish(ctx, queue.Event{ID: uuid.NewString(), Topic: "media.uploaded.v1", Payload: payload})
-}
-
-func uploaderFromEvent(event contractsevents.VideoAssetReadyV1) string {
-	if event.UploaderID != "" {
-		return event.UploaderID
-	}
-	return "admin-studio"
+	return provider == "local_fake" && strings.HasPrefix(sourceURL, "file://")
 }
 
-func (p *Processor) publishStepCompleted(ctx context.Context, runID string) error {
-	payload, err := json.Marshal(contractsevents.VideoRunStepCompletedV1{
-		RunID:       runID,
-		Step:        "qc",
+func stepCompleted(ctx context.Context, incoming contractsevents.VideoRunStepRequestedV1, details string) workerruntime.Output {
+	_ = generatorrunstore.AppendRunLog(ctx, incoming.RunID, incoming.Step, "completed", details)
+	return workerruntime.Emit("video.run.step.completed.v1", contractsevents.VideoRunStepCompletedV1{
+		RunID:       incoming.RunID,
+		Step:        incoming.Step,
+		StepIndex:   incoming.StepIndex,
 		Status:      "completed",
-		Details:     "qc checks passed and upload queued",
+		Details:     details,
 		CompletedAt: time.Now().UTC().Format(time.RFC3339),
 	})
-	if err != nil {
-		return err
-	}
-	return p.bus.Publish(ctx, queue.Event{ID: uuid.NewString(), Topic: "video.run.step.completed.v1", Payload: payload})
 }
 
-func (p *Processor) publishFailed(ctx context.Context, incoming contractsevents.VideoAssetReadyV1, code, message string) error {
-	_ = generatorrunstore.SetRunStatus(ctx, incoming.RunID, "failed", message)
-	_ = generatorrunstore.AppendRunLog(ctx, incoming.RunID, "qc", "failed", message)
-	payload, err := json.Marshal(contractsevents.VideoRunFailedV1{
+func stepFailed(ctx context.Context, incoming contractsevents.VideoRunStepRequestedV1, code, message string) []workerruntime.Output {
+	_ = generatorrunstore.AppendRunLog(ctx, incoming.RunID, incoming.Step, "failed", message)
+	return []workerruntime.Output{workerruntime.Emit("video.run.failed.v1", contractsevents.VideoRunFailedV1{
 		RunID:        incoming.RunID,
-		Step:         "qc",
+		Step:         incoming.Step,
+		StepIndex:    incoming.StepIndex,
 		ErrorCode:    code,
 		ErrorMessage: message,
 		FailedAt:     time.Now().UTC().Format(time.RFC3339),
-	})
-	if err != nil {
-		return err
+	})}
+}
+
+func uploaderFromEvent(event contractsevents.VideoRunStepRequestedV1) string {
+	if event.RequestedBy != "" {
+		return event.RequestedBy
 	}
-	return p.bus.Publish(ctx, queue.Event{ID: uuid.NewString(), Topic: "video.run.failed.v1", Payload: payload})
+	return "admin-studio"
 }
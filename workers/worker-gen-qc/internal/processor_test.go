@@ -8,37 +8,73 @@ import (
 	"testing"
 
 	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
+	"github.com/delqhi/mikasmissions/platform/libs/generatorrunstore"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
 )
 
-func TestProcessorPublishesMediaUploadedOnQCPass(t *testing.T) {
-	bus := queue.NewInMemoryBus()
-	processor := NewProcessor(bus, slog.New(slog.NewJSONHandler(io.Discard, nil)))
-
-	uploaded := 0
-	if err := bus.Subscribe(context.Background(), "media.uploaded.v1", "test-uploaded", func(_ context.Context, event queue.Event) error {
-		uploaded++
-		return nil
-	}); err != nil {
-		t.Fatalf("subscribe uploaded: %v", err)
-	}
-
-	payload, err := json.Marshal(contractsevents.VideoAssetReadyV1{
+func stepEvent(t *testing.T, id, step, sourceURL string) queue.Event {
+	t.Helper()
+	payload, err := json.Marshal(contractsevents.VideoRunStepRequestedV1{
 		RunID:              "run-1",
-		AssetID:            "asset-1",
-		SourceURL:          "https://cdn.example/asset-1.mp4",
-		DurationMS:         120000,
+		WorkflowID:         "wf-1",
+		Step:               step,
+		StepIndex:          2,
+		ModelProfileID:     "nim-default",
+		InputPayload:       json.RawMessage(`{}`),
 		ContentSuitability: "core",
 		AgeBand:            "6-11",
-		UploaderID:         "admin-1",
-		ReadyAt:            "2026-03-11T10:00:00Z",
+		RequestedBy:        "admin-1",
+		Artifacts:          map[string]string{"asset_id": "asset-1", "source_url": sourceURL, "duration_ms": "120000"},
+		RequestedAt:        "2026-03-11T10:00:00Z",
 	})
 	if err != nil {
 		t.Fatalf("marshal: %v", err)
 	}
-	if err := processor.Handle(context.Background(), queue.Event{ID: "e1", Topic: processor.Topic(), Payload: payload}); err != nil {
+	return queue.Event{ID: id, Topic: "video.run.step.requested.v1", Payload: payload}
+}
+
+func noCancellations() *generatorrunstore.Cancellations {
+	return generatorrunstore.NewCancellationsWithStatus(func(context.Context, string) (string, error) { return "", nil }, 0)
+}
+
+func countTopic(t *testing.T, bus queue.Bus, topic string, count *int) {
+	t.Helper()
+	if err := bus.Subscribe(context.Background(), topic, "test-"+topic, func(_ context.Context, event queue.Event) error {
+		*count++
+		return nil
+	}); err != nil {
+		t.Fatalf("subscribe %s: %v", topic, err)
+	}
+}
+
+func TestProcessorCompletesQCStepWithoutUploading(t *testing.T) {
+	bus := queue.NewInMemoryBus()
+	processor := newWorker(noCancellations(), workerruntime.WithBus(bus), workerruntime.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil)))).Steps
+	uploaded, completed := 0, 0
+	countTopic(t, bus, "media.uploaded.v1", &uploaded)
+	countTopic(t, bus, "video.run.step.completed.v1", &completed)
+
+	if err := processor.Handle(context.Background(), stepEvent(t, "e1", "qc", "https://cdn.example/asset-1.mp4")); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if completed != 1 || uploaded != 0 {
+		t.Fatalf("expected qc completion without upload, got completed=%d uploaded=%d", completed, uploaded)
+	}
+}
+
+func TestProcessorPublishesMediaUploadedOnPublishStep(t *testing.T) {
+	bus := queue.NewInMemoryBus()
+	processor := newWorker(noCancellations(), workerruntime.WithBus(bus), workerruntime.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil)))).Steps
+	uploaded := 0
+	countTopic(t, bus, "media.uploaded.v1", &uploaded)
+
+	if err := processor.Handle(context.Background(), stepEvent(t, "e2", "publish", "https://cdn.example/asset-1.mp4")); err != nil {
 		t.Fatalf("handle: %v", err)
 	}
+	testkit.WaitBusIdle(t, bus)
 	if uploaded != 1 {
 		t.Fatalf("expected 1 uploaded event, got %d", uploaded)
 	}
@@ -46,33 +82,65 @@ func TestProcessorPublishesMediaUploadedOnQCPass(t *testing.T) {
 
 func TestProcessorPublishesFailureOnQCReject(t *testing.T) {
 	bus := queue.NewInMemoryBus()
-	processor := NewProcessor(bus, slog.New(slog.NewJSONHandler(io.Discard, nil)))
-
+	processor := newWorker(noCancellations(), workerruntime.WithBus(bus), workerruntime.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil)))).Steps
 	failed := 0
-	if err := bus.Subscribe(context.Background(), "video.run.failed.v1", "test-failed", func(_ context.Context, event queue.Event) error {
-		failed++
-		return nil
-	}); err != nil {
-		t.Fatalf("subscribe failed: %v", err)
+	countTopic(t, bus, "video.run.failed.v1", &failed)
+
+	if err := processor.Handle(context.Background(), stepEvent(t, "e3", "qc", "ftp://invalid")); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if failed != 1 {
+		t.Fatalf("expected 1 failed event, got %d", failed)
 	}
+}
 
-	payload, err := json.Marshal(contractsevents.VideoAssetReadyV1{
-		RunID:              "run-1",
-		AssetID:            "asset-1",
-		SourceURL:          "ftp://invalid",
-		DurationMS:         120000,
-		ContentSuitability: "core",
-		AgeBand:            "6-11",
-		UploaderID:         "admin-1",
-		ReadyAt:            "2026-03-11T10:00:00Z",
-	})
+func TestCancelledRunDoesNotPublish(t *testing.T) {
+	bus := queue.NewInMemoryBus()
+	worker := newWorker(noCancellations(), workerruntime.WithBus(bus), workerruntime.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))))
+	uploaded, completed := 0, 0
+	countTopic(t, bus, "media.uploaded.v1", &uploaded)
+	countTopic(t, bus, "video.run.step.completed.v1", &completed)
+
+	payload, err := json.Marshal(contractsevents.VideoRunCancelledV1{RunID: "run-1", CancelledBy: "admin-1", CancelledAt: "2026-03-11T10:00:30Z"})
 	if err != nil {
 		t.Fatalf("marshal: %v", err)
 	}
-	if err := processor.Handle(context.Background(), queue.Event{ID: "e2", Topic: processor.Topic(), Payload: payload}); err != nil {
+	if err := worker.Cancellations.Handle(context.Background(), queue.Event{ID: "c1", Topic: "video.run.cancelled.v1", Payload: payload}); err != nil {
+		t.Fatalf("handle cancel: %v", err)
+	}
+	if err := worker.Steps.Handle(context.Background(), stepEvent(t, "e4", "publish", "https://cdn.example/asset-1.mp4")); err != nil {
 		t.Fatalf("handle: %v", err)
 	}
-	if failed != 1 {
-		t.Fatalf("expected 1 failed event, got %d", failed)
+	testkit.WaitBusIdle(t, bus)
+	if uploaded != 0 || completed != 0 {
+		t.Fatalf("expected cancelled run to stop publish, got uploaded=%d completed=%d", uploaded, completed)
+	}
+}
+
+func TestProcessorFailsQCWhenDurationIsUnknown(t *testing.T) {
+	bus := queue.NewInMemoryBus()
+	processor := newWorker(noCancellations(), workerruntime.WithBus(bus), workerruntime.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil)))).Steps
+	var failures []contractsevents.VideoRunFailedV1
+	if err := bus.Subscribe(context.Background(), "video.run.failed.v1", "test-failed", func(_ context.Context, event queue.Event) error {
+		var failed contractsevents.VideoRunFailedV1
+		if err := json.Unmarshal(event.Payload, &failed); err != nil {
+			return err
+		}
+		failures = append(failures, failed)
+		return nil
+	}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	completed := 0
+	countTopic(t, bus, "video.run.step.completed.v1", &completed)
+
+	artifacts := map[string]string{"asset_id": "asset-1", "source_url": "https://cdn.example/asset-1.mp4", "provider": "http_predictions"}
+	if err := processor.Handle(context.Background(), fakeStepEvent(t, "qc", 2, artifacts)); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	testkit.WaitBusIdle(t, bus)
+	if completed != 0 || len(failures) != 1 || failures[0].ErrorMessage != errUnknownDuration.Error() {
+		t.Fatalf("expected unknown-duration failure, got completed=%d failures=%+v", completed, failures)
 	}
 }
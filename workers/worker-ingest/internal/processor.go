@@ -2,64 +2,25 @@ package internal
 
 import (
 	"context"
-	"encoding/json"
-	"log/slog"
+	"slices"
 
 	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
 	"github.com/delqhi/mikasmissions/platform/libs/pipeline"
-	"github.com/delqhi/mikasmissions/platform/libs/queue"
-	"github.com/google/uuid"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
 )
 
-type Processor struct {
-	bus    queue.Bus
-	guard  *queue.IdempotencyGuard
-	logger *slog.Logger
+func NewProcessor(opts ...workerruntime.Option) *workerruntime.Processor[contractsevents.MediaUploadedV1] {
+	return workerruntime.NewProcessor("worker-ingest", "media.uploaded.v1", ingest, append(slices.Clip(opts), workerruntime.WithLogAttrs(assetAttrs))...)
 }
 
-func NewProcessor(bus queue.Bus, logger *slog.Logger) *Processor {
-	return &Processor{bus: bus, guard: queue.NewScopedIdempotencyGuard("worker-ingest"), logger: logger}
-}
-
-func (p *Processor) Topic() string {
-	return "media.uploaded.v1"
-}
-
-func (p *Processor) Consumer() string {
-	return "worker-ingest"
-}
-
-func (p *Processor) Handle(ctx context.Context, event queue.Event) error {
-	if p.guard.Seen(event.ID) {
-		p.logger.Info("duplicate event ignored", "worker", "worker-ingest", "event_id", event.ID)
-		return nil
-	}
-	if event.Topic != p.Topic() {
-		p.logger.Info("unexpected topic skipped", "worker", "worker-ingest", "topic", event.Topic)
-		return nil
-	}
-	var incoming contractsevents.MediaUploadedV1
-	if err := json.Unmarshal(event.Payload, &incoming); err != nil {
-		return err
-	}
-	if err := incoming.Validate(); err != nil {
-		return err
-	}
+func ingest(_ context.Context, incoming contractsevents.MediaUploadedV1) ([]workerruntime.Output, error) {
 	outgoing, err := pipeline.BuildTranscodeRequest(incoming)
 	if err != nil {
-		return err
-	}
-	payload, err := json.Marshal(outgoing)
-	if err != nil {
-		return err
+		return nil, err
 	}
-	if err := p.bus.Publish(ctx, queue.Event{
-		ID:      uuid.NewString(),
-		Topic:   "media.transcode.requested.v1",
-		Payload: payload,
-	}); err != nil {
-		return err
-	}
-	p.logger.Info("event processed", "worker", "worker-ingest", "asset_id", incoming.AssetID)
-	return nil
+	return []workerruntime.Output{workerruntime.Emit("media.transcode.requested.v1", outgoing)}, nil
+}
+
+func assetAttrs(incoming contractsevents.MediaUploadedV1) []any {
+	return []any{"asset_id", incoming.AssetID}
 }
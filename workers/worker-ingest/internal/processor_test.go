@@ -1,19 +1,23 @@
 package internal
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
 	"log/slog"
+	"strings"
 	"testing"
 
 	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
 )
 
 func TestProcessorIdempotency(t *testing.T) {
 	bus := queue.NewInMemoryBus()
-	processor := NewProcessor(bus, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	processor := NewProcessor(workerruntime.WithBus(bus), workerruntime.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))))
 	var seen int
 	_ = bus.Subscribe(context.Background(), "media.transcode.requested.v1", "test-ingest", func(_ context.Context, event queue.Event) error {
 		var decoded contractsevents.MediaTranscodeRequestedV1
@@ -34,7 +38,25 @@ func TestProcessorIdempotency(t *testing.T) {
 	if err := processor.Handle(context.Background(), event); err != nil {
 		t.Fatalf("duplicate handle failed: %v", err)
 	}
+	testkit.WaitBusIdle(t, bus)
 	if seen != 1 {
 		t.Fatalf("expected 1 published event, got %d", seen)
 	}
 }
+
+func TestProcessorLogsAssetID(t *testing.T) {
+	var logs bytes.Buffer
+	processor := NewProcessor(workerruntime.WithBus(queue.NewInMemoryBus()), workerruntime.WithLogger(slog.New(slog.NewJSONHandler(&logs, nil))))
+	payload, _ := json.Marshal(contractsevents.MediaUploadedV1{
+		AssetID:   "asset-7",
+		SourceURL: "https://cdn.local/video.mp4",
+		Uploader:  "u-1",
+		TraceID:   "tr-1",
+	})
+	if err := processor.Handle(context.Background(), queue.Event{ID: "evt7", Topic: "media.uploaded.v1", Payload: payload}); err != nil {
+		t.Fatalf("handle failed: %v", err)
+	}
+	if !strings.Contains(logs.String(), `"asset_id":"asset-7"`) {
+		t.Fatalf("expected asset_id on processed log, got %s", logs.String())
+	}
+}
@@ -2,42 +2,43 @@ package main
 
 import (
 	"context"
-	"log"
-	"log/slog"
+	"errors"
 	"os"
-	"os/signal"
 	"strconv"
-	"syscall"
 	"time"
 
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
 	"github.com/delqhi/mikasmissions/platform/workers/worker-outbox-relay/internal"
 )
 
 func main() {
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	databaseURL := os.Getenv("DATABASE_URL")
-	if databaseURL == "" {
-		log.Fatal("DATABASE_URL is required for worker-outbox-relay")
-	}
-	outbox, err := queue.NewPersistentOutbox(databaseURL)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer outbox.Close()
-
-	bus, err := queue.NewBusFromEnv()
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer bus.Close()
+	workerruntime.Main("worker-outbox-relay", func(app *workerruntime.App) error {
+		databaseURL := os.Getenv("DATABASE_URL")
+		if databaseURL == "" {
+			return errors.New("DATABASE_URL is required for worker-outbox-relay")
+		}
+		retryPolicies, err := queue.OutboxRetryPoliciesFromEnv()
+		if err != nil {
+			return err
+		}
+		opts := append(queue.OutboxOptionsFromEnv(os.Getenv), queue.WithOutboxRetryPolicies(retryPolicies))
+		outbox, err := queue.NewPersistentOutbox(databaseURL, opts...)
+		if err != nil {
+			return err
+		}
+		app.OnClose(outbox)
+		app.AddReadinessCheck("database", outbox.Ping)
 
-	interval := readRelayInterval()
-	relay := internal.NewRelay(outbox, bus, interval, logger)
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
-	logger.Info("worker started", "worker", "worker-outbox-relay", "interval_ms", interval.Milliseconds())
-	relay.Run(ctx)
+		interval := readRelayInterval()
+		relay := internal.NewRelay(outbox, app.Bus, interval, app.Logger)
+		app.Logger.Info("outbox relay configured", "worker", app.Name, "interval_ms", interval.Milliseconds())
+		app.Go(func(ctx context.Context) error {
+			relay.Run(ctx)
+			return nil
+		})
+		return nil
+	})
 }
 
 func readRelayInterval() time.Duration {
@@ -44,7 +44,6 @@ func (r *Relay) Run(ctx context.Context) {
 		}
 		select {
 		case <-ctx.Done():
-			r.logger.Info("worker stopped", "worker", "worker-outbox-relay")
 			return
 		case <-ticker.C:
 		}
@@ -9,11 +9,13 @@ import (
 
 	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
 )
 
 func TestProcessorIdempotency(t *testing.T) {
 	bus := queue.NewInMemoryBus()
-	processor := NewProcessor(bus, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	processor := NewProcessor(workerruntime.WithBus(bus), workerruntime.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))))
 	var reviewedSeen int
 	var approvedSeen int
 	_ = bus.Subscribe(context.Background(), "media.reviewed.v1", "test-reviewed", func(_ context.Context, event queue.Event) error {
@@ -42,6 +44,7 @@ func TestProcessorIdempotency(t *testing.T) {
 	if err := processor.Handle(context.Background(), event); err != nil {
 		t.Fatalf("duplicate handle failed: %v", err)
 	}
+	testkit.WaitBusIdle(t, bus)
 	if reviewedSeen != 1 || approvedSeen != 1 {
 		t.Fatalf("expected reviewed=1 and approved=1, got %d and %d", reviewedSeen, approvedSeen)
 	}
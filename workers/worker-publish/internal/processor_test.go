@@ -3,18 +3,21 @@ package internal
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"log/slog"
 	"testing"
 
 	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
 )
 
 func TestProcessorIdempotency(t *testing.T) {
 	t.Setenv("CATALOG_URL", "")
 	bus := queue.NewInMemoryBus()
-	processor := NewProcessor(bus, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	processor := NewProcessor(workerruntime.WithBus(bus), workerruntime.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))))
 	var seen int
 	_ = bus.Subscribe(context.Background(), "episode.published.v1", "test-publish", func(_ context.Context, event queue.Event) error {
 		var decoded contractsevents.EpisodePublishedV1
@@ -34,7 +37,38 @@ func TestProcessorIdempotency(t *testing.T) {
 	if err := processor.Handle(context.Background(), event); err != nil {
 		t.Fatalf("duplicate handle failed: %v", err)
 	}
+	testkit.WaitBusIdle(t, bus)
 	if seen != 1 {
 		t.Fatalf("expected 1 published event, got %d", seen)
 	}
 }
+
+type flakyProjector struct {
+	failures int
+	calls    int
+}
+
+func (f *flakyProjector) ProjectEpisode(context.Context, episodeProjectionRequest) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("catalog unavailable")
+	}
+	return nil
+}
+
+func TestProcessorRetriesAfterProjectionFailure(t *testing.T) {
+	bus := queue.NewInMemoryBus()
+	projector := &flakyProjector{failures: 1}
+	processor := newProcessor(projector, workerruntime.WithBus(bus), workerruntime.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))))
+	payload, _ := json.Marshal(contractsevents.MediaApprovedV1{AssetID: "asset-1", AgeBand: "6-11", LearningTags: []string{"farben"}})
+	event := queue.Event{ID: "evt1", Topic: "media.approved.v1", Payload: payload}
+	if err := processor.Handle(context.Background(), event); err == nil {
+		t.Fatalf("expected projection failure")
+	}
+	if err := processor.Handle(context.Background(), event); err != nil {
+		t.Fatalf("redelivery failed: %v", err)
+	}
+	if projector.calls != 2 {
+		t.Fatalf("expected redelivery to project again, got %d calls", projector.calls)
+	}
+}
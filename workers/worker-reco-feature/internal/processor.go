@@ -2,31 +2,14 @@ package internal
 
 import (
 	"context"
-	"log/slog"
 
-	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
 )
 
-type Processor struct {
-	name          string
-	expectedTopic string
-	guard         *queue.IdempotencyGuard
-	logger        *slog.Logger
+func NewProcessor(name, expectedTopic string, opts ...workerruntime.Option) *workerruntime.Processor[workerruntime.Raw] {
+	return workerruntime.NewProcessor(name, expectedTopic, consume, opts...)
 }
 
-func NewProcessor(name, expectedTopic string, logger *slog.Logger) *Processor {
-	return &Processor{name: name, expectedTopic: expectedTopic, guard: queue.NewScopedIdempotencyGuard(name), logger: logger}
-}
-
-func (p *Processor) Handle(_ context.Context, event queue.Event) error {
-	if p.guard.Seen(event.ID) {
-		p.logger.Info("duplicate event ignored", "worker", p.name, "event_id", event.ID)
-		return nil
-	}
-	if event.Topic != p.expectedTopic {
-		p.logger.Info("unexpected topic skipped", "worker", p.name, "topic", event.Topic)
-		return nil
-	}
-	p.logger.Info("event processed", "worker", p.name, "topic", event.Topic, "bytes", len(event.Payload))
-	return nil
+func consume(context.Context, workerruntime.Raw) ([]workerruntime.Output, error) {
+	return nil, nil
 }
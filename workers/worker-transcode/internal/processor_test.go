@@ -9,11 +9,13 @@ import (
 
 	contractsevents "github.com/delqhi/mikasmissions/platform/libs/contracts-events"
 	"github.com/delqhi/mikasmissions/platform/libs/queue"
+	"github.com/delqhi/mikasmissions/platform/libs/testkit"
+	"github.com/delqhi/mikasmissions/platform/libs/workerruntime"
 )
 
 func TestProcessorIdempotency(t *testing.T) {
 	bus := queue.NewInMemoryBus()
-	processor := NewProcessor(bus, slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	processor := NewProcessor(workerruntime.WithBus(bus), workerruntime.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))))
 	var seen int
 	_ = bus.Subscribe(context.Background(), "media.transcoded.v1", "test-transcode", func(_ context.Context, event queue.Event) error {
 		var decoded contractsevents.MediaTranscodedV1
@@ -33,6 +35,7 @@ func TestProcessorIdempotency(t *testing.T) {
 	if err := processor.Handle(context.Background(), event); err != nil {
 		t.Fatalf("duplicate handle failed: %v", err)
 	}
+	testkit.WaitBusIdle(t, bus)
 	if seen != 1 {
 		t.Fatalf("expected 1 published event, got %d", seen)
 	}